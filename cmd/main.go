@@ -2,9 +2,15 @@ package main
 
 import (
 	"belimang/internal/config"
+	"belimang/internal/dispatch"
+	"belimang/internal/entities"
+	"belimang/internal/events"
 	"belimang/internal/handlers"
+	"belimang/internal/payments"
+	"belimang/internal/pricing"
 	"belimang/internal/repository"
 	"belimang/internal/route"
+	"belimang/internal/routing"
 	"belimang/internal/services"
 	"belimang/internal/utils"
 	"context"
@@ -40,6 +46,36 @@ func main() {
 		 log.Fatal().Err(err)
 	}
 
+	routingProvider, err := routing.New(cfg.RoutingProvider, cfg.RoutingURL, cfg.RoutingProfile, cfg.RoutingTimeout, cfg.RoutingCacheTTL)
+	if err != nil {
+		 log.Fatal().Err(err).Msg("failed to init routing provider")
+	}
+
+	pricingConfig, err := pricing.LoadConfig(cfg.PricingConfigPath)
+	if err != nil {
+		 log.Fatal().Err(err).Msg("failed to load pricing config")
+	}
+
+	dispatchScore, ok := dispatch.ByName(cfg.DispatchScore)
+	if !ok {
+		 log.Fatal().Str("score", cfg.DispatchScore).Msg("unknown dispatch score")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var eventBus events.Bus
+	switch cfg.EventsBackend {
+	case "", "memory":
+		eventBus = events.NewHub()
+	case "postgres":
+		pgBus := events.NewPostgresBus(dbp)
+		go pgBus.Listen(ctx)
+		eventBus = pgBus
+	default:
+		log.Fatal().Str("backend", cfg.EventsBackend).Msg("unknown events backend")
+	}
+
 	v := validator.New()
 	utils.RegisterCustomValidations(v)
 
@@ -53,17 +89,78 @@ func main() {
 	authRepository := repository.NewAuthRepository(dbp)
 	merchantRepository := repository.NewMerchantRepository(dbp)
 	purchaseRepository := repository.NewPurchaseRepository(dbp)
+	geoRepository := repository.NewGeoRepository(dbp)
+	promotionRepository := repository.NewPromotionRepository(dbp)
+	orderRepository := repository.NewOrderRepository(dbp)
+	merchantOrderRepository := repository.NewMerchantOrderRepository(dbp)
+	courierRepository := repository.NewCourierRepository(dbp)
+	trackingRepository := repository.NewTrackingRepository(dbp)
+	etaRepository := repository.NewETARepository(dbp)
+	favoriteRepository := repository.NewFavoriteRepository(dbp)
+	cartRepository := repository.NewCartRepository(dbp)
+	addressRepository := repository.NewAddressRepository(dbp)
+	reviewRepository := repository.NewReviewRepository(dbp)
+	receiptRepository := repository.NewReceiptRepository(dbp)
+	paymentRepository := repository.NewPaymentRepository(dbp)
+	ledgerRepository := repository.NewLedgerRepository(dbp)
+
+	walletService := services.NewWalletService(ledgerRepository)
+
+	providers := []payments.PaymentProvider{payments.NewCODProvider(), payments.NewWalletProvider(walletService)}
+	if cfg.PaymentFakeSecret != "" {
+		 providers = append(providers, payments.NewFakeProvider(cfg.PaymentFakeSecret))
+	}
+	paymentProviders := payments.NewRegistry(providers...)
 
 	hashingPool := services.NewHashingPool(2, 40)
 	authService := services.NewAuthService(authRepository, hashingPool)
 	fileService := services.NewFileService(mnc, cfg)
 	merchantService := services.NewMerchantService(merchantRepository)
-	purchaseService := services.NewPurchaseService(purchaseRepository)
+	promotionService := services.NewPromotionService(promotionRepository)
+	orderService := services.NewOrderService(orderRepository, merchantOrderRepository, paymentRepository, eventBus, promotionService, services.CancellationPolicy{
+		FreeWindow: cfg.CancelFreeWindow,
+		FeePercent: map[string]int{
+			entities.OrderAccepted:  cfg.CancelFeeAcceptedPercent,
+			entities.OrderPreparing: cfg.CancelFeePreparingPercent,
+		},
+	})
+	paymentService := services.NewPaymentService(paymentRepository, paymentProviders, orderService, walletService)
+	addressService := services.NewAddressService(addressRepository)
+	etaService := services.NewETAService(etaRepository)
+	if err := etaService.Reload(ctx); err != nil {
+		 log.Error().Err(err).Msg("failed to load eta model, using defaults")
+	}
+	purchaseService := services.NewPurchaseService(purchaseRepository, routingProvider, etaService, pricing.NewEngine(pricingConfig), promotionService, orderService, paymentService, addressService, cfg.EstimateTTL, services.SchedulingWindow{
+		MinLead:  cfg.ScheduleMinLead,
+		MaxAhead: cfg.ScheduleMaxAhead,
+	})
+	geoService := services.NewGeoService(geoRepository)
+	merchantOrderService := services.NewMerchantOrderService(merchantOrderRepository, merchantRepository, orderService)
+	courierService := services.NewCourierService(courierRepository, eventBus)
+	locationIngestor := services.NewLocationIngestor(trackingRepository, cfg.LocationFlushInterval)
+	trackingService := services.NewTrackingService(trackingRepository, courierRepository, orderRepository, locationIngestor, eventBus)
+	favoriteService := services.NewFavoriteService(favoriteRepository)
+	reviewService := services.NewReviewService(reviewRepository, fileService)
+	receiptService := services.NewReceiptService(receiptRepository)
+	cartService := services.NewCartService(cartRepository, purchaseService)
 
 	fileHandler := handlers.NewFileHandler(fileService)
 	authHandler := handlers.NewAuthHandler(authService, v)
 	merchantHandler := handlers.NewMerchantHandler(merchantService, v)
 	purchaseHandler := handlers.NewPurchaseHandler(purchaseService, v)
+	geoHandler := handlers.NewGeoHandler(geoService)
+	promotionHandler := handlers.NewPromotionHandler(promotionService, v)
+	orderHandler := handlers.NewOrderHandler(orderService, v)
+	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	walletHandler := handlers.NewWalletHandler(walletService, v)
+	merchantOrderHandler := handlers.NewMerchantOrderHandler(merchantOrderService, v)
+	courierHandler := handlers.NewCourierHandler(courierService, v)
+	trackingHandler := handlers.NewTrackingHandler(trackingService, v)
+	favoriteHandler := handlers.NewFavoriteHandler(favoriteService, v)
+	cartHandler := handlers.NewCartHandler(cartService, v)
+	addressHandler := handlers.NewAddressHandler(addressService, v)
+	reviewHandler := handlers.NewReviewHandler(reviewService, v)
+	receiptHandler := handlers.NewReceiptHandler(receiptService)
 
 	r.Get("/health-check", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -74,6 +171,19 @@ func main() {
 	route.RegisterFileRoutes(r, fileHandler)
 	route.RegisterMerchantRoutes(r, merchantHandler)
 	route.RegisterPurchaseRoutes(r, purchaseHandler)
+	route.RegisterGeoRoutes(r, geoHandler)
+	route.RegisterPromotionRoutes(r, promotionHandler)
+	route.RegisterOrderRoutes(r, orderHandler)
+	route.RegisterPaymentRoutes(r, paymentHandler)
+	route.RegisterWalletRoutes(r, walletHandler)
+	route.RegisterMerchantOrderRoutes(r, merchantOrderHandler)
+	route.RegisterCourierRoutes(r, courierHandler)
+	route.RegisterTrackingRoutes(r, trackingHandler)
+	route.RegisterFavoriteRoutes(r, favoriteHandler)
+	route.RegisterCartRoutes(r, cartHandler)
+	route.RegisterAddressRoutes(r, addressHandler)
+	route.RegisterReviewRoutes(r, reviewHandler)
+	route.RegisterReceiptRoutes(r, receiptHandler)
 
 	server := http.Server{
 		Addr:        cfg.Host + ":" + cfg.Port,
@@ -82,8 +192,34 @@ func main() {
 		IdleTimeout: 60 * time.Second,
 	}
 
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
+	estimatePurger := services.NewEstimatePurger(purchaseRepository, cfg.EstimatePurgeInterval, cfg.EstimateRetention)
+	go estimatePurger.Run(ctx)
+
+	refundProcessor := services.NewRefundProcessor(paymentRepository, paymentProviders, cfg.RefundProcessInterval)
+	go refundProcessor.Run(ctx)
+
+	orderScheduler := services.NewOrderScheduler(orderService, cfg.SchedulerInterval)
+	go orderScheduler.Run(ctx)
+
+	dispatcher := services.NewDispatcher(courierRepository, routingProvider, dispatchScore, services.DispatchConfig{
+		Interval:      cfg.DispatchInterval,
+		OfferTimeout:  cfg.DispatchOfferTimeout,
+		OfferCooldown: cfg.DispatchOfferCooldown,
+		RadiusKm:      float64(cfg.DispatchRadiusMeters) / 1000,
+		HeartbeatTTL:  cfg.CourierHeartbeatTTL,
+	})
+	go dispatcher.Run(ctx)
+
+	// the ingestor outlives the server so pings of requests still in flight
+	// during shutdown are written too
+	ingestCtx, stopIngest := context.WithCancel(context.Background())
+	defer stopIngest()
+	go locationIngestor.Run(ingestCtx)
+
+	tripBuilder := services.NewTripBuilder(trackingRepository, cfg.TripBuildInterval)
+	go tripBuilder.Run(ctx)
+
+	go etaService.Run(ctx, cfg.ETAReloadInterval)
 
 	go func() {
 		log.Info().Str("port", cfg.Port).Msg("server running")
@@ -101,5 +237,8 @@ func main() {
 		log.Fatal().Err(err).Msg("server forced to shutdown")
 	}
 
+	stopIngest()
+	<-locationIngestor.Done()
+
 	log.Info().Msg("server exited")
 }
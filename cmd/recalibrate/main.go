@@ -0,0 +1,53 @@
+// Command recalibrate relearns the ETA model from recent deliveries and stores
+// it, along with per-merchant preparation times. Running servers pick the new
+// model up on their next reload. It is meant to be run periodically, e.g. nightly.
+package main
+
+import (
+	"belimang/internal/config"
+	"belimang/internal/repository"
+	"belimang/internal/services"
+	"context"
+	"flag"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	cfg, err := config.LoadAllAppConfig()
+	if err != nil {
+		 log.Fatal().Err(err).Msg("failed to load config")
+	}
+
+	window := flag.Duration("window", cfg.ETACalibrationWindow, "how far back to learn from")
+	minSamples := flag.Int("min-samples", cfg.ETACalibrationSamples, "observations needed before a coefficient replaces its fallback")
+	flag.Parse()
+
+	dbp, err := config.InitDBConnection(cfg)
+	if err != nil {
+		 log.Fatal().Err(err).Msg("failed to connect to database")
+	}
+	defer dbp.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	repository.SetPool(dbp)
+	etaService := services.NewETAService(repository.NewETARepository(dbp))
+
+	model, merchants, err := etaService.Recalibrate(ctx, *window, *minSamples)
+	if err != nil {
+		 log.Fatal().Err(err).Msg("failed to recalibrate eta model")
+	}
+
+	log.Info().
+		Int64("model", model.ID).
+		Float64("stopDwellMinutes", model.StopDwellMinutes).
+		Floats64("speedKmh", model.SpeedKmh).
+		Int("segments", model.SegmentSamples).
+		Int("trips", model.TripSamples).
+		Int64("merchants", merchants).
+		Msg("eta model recalibrated")
+}
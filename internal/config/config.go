@@ -30,6 +30,61 @@ type Config struct {
 	UseSSL     bool
 	Endpoint   string
 	BucketName string
+
+	RoutingProvider string
+	RoutingURL      string
+	RoutingProfile  string
+	RoutingTimeout  time.Duration
+	RoutingCacheTTL time.Duration
+
+	PricingConfigPath string
+
+	EstimateTTL           time.Duration
+	EstimateRetention     time.Duration
+	EstimatePurgeInterval time.Duration
+
+	EventsBackend string
+
+	CancelFreeWindow          time.Duration
+	CancelFeeAcceptedPercent  int
+	CancelFeePreparingPercent int
+
+	PaymentFakeSecret     string
+	RefundProcessInterval time.Duration
+
+	ScheduleMinLead   time.Duration
+	ScheduleMaxAhead  time.Duration
+	SchedulerInterval time.Duration
+
+	DispatchInterval      time.Duration
+	DispatchOfferTimeout  time.Duration
+	DispatchOfferCooldown time.Duration
+	DispatchRadiusMeters  int
+	DispatchScore         string
+	CourierHeartbeatTTL   time.Duration
+
+	LocationFlushInterval time.Duration
+	TripBuildInterval     time.Duration
+
+	ETAReloadInterval     time.Duration
+	ETACalibrationWindow  time.Duration
+	ETACalibrationSamples int
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		 return d
+	}
+
+	return def
+}
+
+func intEnv(key string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		 return n
+	}
+
+	return def
 }
 
 func LoadAllAppConfig() (Config, error) {
@@ -52,6 +107,45 @@ func LoadAllAppConfig() (Config, error) {
 		UseSSL:     useSSL,
 		Endpoint:   os.Getenv("MINIO_ENDPOINT"),
 		BucketName: os.Getenv("MINIO_BUCKET_NAME"),
+
+		RoutingProvider: os.Getenv("ROUTING_PROVIDER"),
+		RoutingURL:      os.Getenv("ROUTING_URL"),
+		RoutingProfile:  os.Getenv("ROUTING_PROFILE"),
+		RoutingTimeout:  durationEnv("ROUTING_TIMEOUT", 2*time.Second),
+		RoutingCacheTTL: durationEnv("ROUTING_CACHE_TTL", 15*time.Minute),
+
+		PricingConfigPath: os.Getenv("PRICING_CONFIG"),
+
+		EstimateTTL:           durationEnv("ESTIMATE_TTL", 30*time.Minute),
+		EstimateRetention:     durationEnv("ESTIMATE_RETENTION", 24*time.Hour),
+		EstimatePurgeInterval: durationEnv("ESTIMATE_PURGE_INTERVAL", 10*time.Minute),
+
+		EventsBackend: os.Getenv("EVENTS_BACKEND"),
+
+		CancelFreeWindow:          durationEnv("CANCEL_FREE_WINDOW", 2*time.Minute),
+		CancelFeeAcceptedPercent:  intEnv("CANCEL_FEE_ACCEPTED_PERCENT", 10),
+		CancelFeePreparingPercent: intEnv("CANCEL_FEE_PREPARING_PERCENT", 50),
+
+		PaymentFakeSecret:     os.Getenv("PAYMENT_FAKE_SECRET"),
+		RefundProcessInterval: durationEnv("REFUND_PROCESS_INTERVAL", time.Minute),
+
+		ScheduleMinLead:   durationEnv("SCHEDULE_MIN_LEAD", 30*time.Minute),
+		ScheduleMaxAhead:  durationEnv("SCHEDULE_MAX_AHEAD", 7*24*time.Hour),
+		SchedulerInterval: durationEnv("SCHEDULER_INTERVAL", 30*time.Second),
+
+		DispatchInterval:      durationEnv("DISPATCH_INTERVAL", 5*time.Second),
+		DispatchOfferTimeout:  durationEnv("DISPATCH_OFFER_TIMEOUT", 30*time.Second),
+		DispatchOfferCooldown: durationEnv("DISPATCH_OFFER_COOLDOWN", 5*time.Minute),
+		DispatchRadiusMeters:  intEnv("DISPATCH_RADIUS_METERS", 5000),
+		DispatchScore:         os.Getenv("DISPATCH_SCORE"),
+		CourierHeartbeatTTL:   durationEnv("COURIER_HEARTBEAT_TTL", 2*time.Minute),
+
+		LocationFlushInterval: durationEnv("LOCATION_FLUSH_INTERVAL", time.Second),
+		TripBuildInterval:     durationEnv("TRIP_BUILD_INTERVAL", time.Minute),
+
+		ETAReloadInterval:     durationEnv("ETA_RELOAD_INTERVAL", 5*time.Minute),
+		ETACalibrationWindow:  durationEnv("ETA_CALIBRATION_WINDOW", 28*24*time.Hour),
+		ETACalibrationSamples: intEnv("ETA_CALIBRATION_SAMPLES", 20),
 	}, nil
 }
 
@@ -88,6 +182,17 @@ func InitDBConnection(cfg Config) (*pgxpool.Pool, error) {
 	return dbpool, nil
 }
 
+// StorageURL is where uploaded objects are served from, the bucket on the
+// MinIO endpoint as the presigned links address it.
+func (c Config) StorageURL() string {
+	scheme := "http"
+	if c.UseSSL {
+		 scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s/%s/", scheme, c.Endpoint, c.BucketName)
+}
+
 func InitMCConncection(cfg Config) (*minio.Client, error) {
 	minioClient, err := minio.New(cfg.Endpoint, &minio.Options{
 		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
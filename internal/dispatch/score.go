@@ -0,0 +1,73 @@
+// Package dispatch ranks couriers for an order waiting to be picked up.
+package dispatch
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/routing"
+	"sort"
+)
+
+// ScoreFunc rates how well candidate suits order. Lower scores are better.
+type ScoreFunc func(order entities.DispatchOrder, candidate entities.CourierCandidate) float64
+
+// idleCredit is how many minutes of approach an idle courier is forgiven per
+// minute spent waiting, capped at maxIdleCredit, so work spreads across couriers.
+const (
+	idleCredit    = 0.1
+	maxIdleCredit = 5.0
+)
+
+// DefaultScore is the expected minutes until the customer has the order: the
+// courier's drive to the first pickup plus the quoted route at the same pace,
+// less a small credit for couriers that have been waiting longest.
+func DefaultScore(order entities.DispatchOrder, c entities.CourierCandidate) float64 {
+	minutesPerKm := 60 / routing.DefaultSpeedKmh
+	if c.ApproachKm > 0 && c.ApproachMinutes > 0 {
+		 minutesPerKm = c.ApproachMinutes / c.ApproachKm
+	}
+
+	minutes := c.ApproachMinutes + order.RouteKm*minutesPerKm
+	return minutes - min(c.IdleFor.Minutes()*idleCredit, maxIdleCredit)
+}
+
+// NearestScore ranks purely by road distance to the first pickup.
+func NearestScore(_ entities.DispatchOrder, c entities.CourierCandidate) float64 {
+	return c.ApproachKm
+}
+
+// Scored is a candidate with its score.
+type Scored struct {
+	entities.CourierCandidate
+	Score float64
+}
+
+// Rank scores every candidate and sorts them best first. Ties go to the
+// courier idle the longest.
+func Rank(order entities.DispatchOrder, candidates []entities.CourierCandidate, score ScoreFunc) []Scored {
+	ranked := make([]Scored, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, Scored{CourierCandidate: c, Score: score(order, c)})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score < ranked[j].Score
+		}
+		return ranked[i].IdleFor > ranked[j].IdleFor
+	})
+
+	return ranked
+}
+
+// ByName returns the scoring function called name, or DefaultScore when name
+// is empty. ok is false for unknown names.
+func ByName(name string) (ScoreFunc, bool) {
+	switch name {
+	case "", "eta":
+		return DefaultScore, true
+	case "nearest":
+		return NearestScore, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type (
+	AddressRequest struct {
+		Label    string   `json:"label" validate:"required,max=50"`
+		Location Location `json:"location" validate:"required"`
+		Building string   `json:"building" validate:"max=100"`
+		Floor    string   `json:"floor" validate:"max=20"`
+		Notes    string   `json:"notes" validate:"max=500"`
+	}
+
+	Address struct {
+		ID        string    `json:"addressId"`
+		Label     string    `json:"label"`
+		Location  Location  `json:"location"`
+		Building  string    `json:"building"`
+		Floor     string    `json:"floor"`
+		Notes     string    `json:"notes"`
+		CreatedAt time.Time `json:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	Delivery struct {
+		AddressID *string  `json:"addressId,omitempty"`
+		Location  Location `json:"location"`
+		Building  string   `json:"building,omitempty"`
+		Floor     string   `json:"floor,omitempty"`
+		Notes     string   `json:"notes,omitempty"`
+	}
+)
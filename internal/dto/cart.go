@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type (
+	AddCartItemRequest struct {
+		ItemID   string `json:"itemId" validate:"required,uuid"`
+		Quantity int    `json:"quantity" validate:"required,min=1,max=99"`
+	}
+
+	UpdateCartItemRequest struct {
+		Quantity int `json:"quantity" validate:"required,min=1,max=99"`
+	}
+
+	// CartEstimateRequest turns the cart into an estimate. The route starts at
+	// StartMerchantID, or at the merchant added to the cart first when empty.
+	CartEstimateRequest struct {
+		AddressID       string     `json:"addressId" validate:"omitempty,uuid"`
+		UserLocation    *Location  `json:"userLocation" validate:"required_without=AddressID"`
+		StartMerchantID string     `json:"startMerchantId" validate:"omitempty,uuid"`
+		PromoCode       string     `json:"promoCode" validate:"omitempty,max=32"`
+		ScheduledFor    *time.Time `json:"scheduledFor"`
+	}
+
+	// CartResponse is checked against current prices and availability on
+	// every read. TotalPrice only counts available items, and Changed is set
+	// when any item changed price or became unavailable since it was added.
+	CartResponse struct {
+		Merchants  []CartMerchant `json:"merchants"`
+		TotalPrice int            `json:"totalPrice"`
+		Changed    bool           `json:"changed"`
+	}
+
+	CartMerchant struct {
+		Merchant Merchant   `json:"merchant"`
+		Items    []CartItem `json:"items"`
+		Subtotal int        `json:"subtotal"`
+	}
+
+	CartItem struct {
+		ItemID          string    `json:"itemId"`
+		Name            string    `json:"name"`
+		ProductCategory string    `json:"productCategory"`
+		ImageURL        string    `json:"imageUrl"`
+		Quantity        int       `json:"quantity"`
+		Price           int       `json:"price"`
+		PriceWhenAdded  int       `json:"priceWhenAdded"`
+		PriceChanged    bool      `json:"priceChanged"`
+		IsAvailable     bool      `json:"isAvailable"`
+		AddedAt         time.Time `json:"addedAt"`
+	}
+)
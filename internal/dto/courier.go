@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+type (
+	CreateCourierRequest struct {
+		UserID string `json:"userId" validate:"required,uuid"`
+	}
+
+	SetAvailabilityRequest struct {
+		Available *bool `json:"available" validate:"required"`
+	}
+
+	HeartbeatRequest struct {
+		Location Location `json:"location" validate:"required"`
+	}
+
+	Courier struct {
+		CourierID  string     `json:"courierId"`
+		UserID     string     `json:"userId"`
+		Status     string     `json:"status"`
+		Location   *Location  `json:"location,omitempty"`
+		LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+		CreatedAt  time.Time  `json:"createdAt"`
+	}
+
+	DispatchOffer struct {
+		OfferID    string    `json:"offerId"`
+		OrderID    string    `json:"orderId"`
+		Status     string    `json:"status"`
+		ApproachKm float64   `json:"approachKm"`
+		RouteKm    float64   `json:"routeKm"`
+		ExpiresAt  time.Time `json:"expiresAt"`
+		CreatedAt  time.Time `json:"createdAt"`
+	}
+)
@@ -0,0 +1,19 @@
+package dto
+
+type (
+	// FavoriteRequest names exactly one merchant or item.
+	FavoriteRequest struct {
+		MerchantID string `json:"merchantId" validate:"omitempty,uuid"`
+		ItemID     string `json:"itemId" validate:"omitempty,uuid"`
+	}
+
+	FavoriteItem struct {
+		MercItem
+		MerchantID string `json:"merchantId"`
+	}
+
+	FavoritesResponse struct {
+		Merchants []Merchant     `json:"merchants"`
+		Items     []FavoriteItem `json:"items"`
+	}
+)
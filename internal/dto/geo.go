@@ -0,0 +1,81 @@
+package dto
+
+type (
+	MapMerchant struct {
+		ID       string   `json:"merchantId"`
+		Name     string   `json:"name"`
+		Category string   `json:"merchantCategory"`
+		ImageURL string   `json:"imageUrl"`
+		Location Location `json:"location"`
+	}
+
+	MapCluster struct {
+		ID       string     `json:"clusterId"`
+		Count    int        `json:"count"`
+		Location Location   `json:"location"`
+		Bounds   [4]float64 `json:"bbox"`
+	}
+
+	MapResponse struct {
+		Zoom      int           `json:"zoom"`
+		Clustered bool          `json:"clustered"`
+		Truncated bool          `json:"truncated"`
+		Merchants []MapMerchant `json:"merchants"`
+		Clusters  []MapCluster  `json:"clusters"`
+	}
+
+	GeoJSONGeometry struct {
+		Type        string     `json:"type"`
+		Coordinates [2]float64 `json:"coordinates"`
+	}
+
+	GeoJSONFeature struct {
+		Type       string          `json:"type"`
+		ID         string          `json:"id,omitempty"`
+		Geometry   GeoJSONGeometry `json:"geometry"`
+		Properties map[string]any  `json:"properties"`
+	}
+
+	GeoJSONFeatureCollection struct {
+		Type     string           `json:"type"`
+		Features []GeoJSONFeature `json:"features"`
+	}
+)
+
+func NewPointFeature(id string, loc Location, props map[string]any) GeoJSONFeature {
+	return GeoJSONFeature{
+		Type: "Feature",
+		ID:   id,
+		Geometry: GeoJSONGeometry{
+			Type:        "Point",
+			Coordinates: [2]float64{loc.Lon, loc.Lat},
+		},
+		Properties: props,
+	}
+}
+
+func (m MapResponse) GeoJSON() GeoJSONFeatureCollection {
+	features := make([]GeoJSONFeature, 0, len(m.Merchants)+len(m.Clusters))
+
+	for _, mc := range m.Merchants {
+		features = append(features, NewPointFeature(mc.ID, mc.Location, map[string]any{
+			"kind":             "merchant",
+			"name":             mc.Name,
+			"merchantCategory": mc.Category,
+			"imageUrl":         mc.ImageURL,
+		}))
+	}
+
+	for _, cl := range m.Clusters {
+		features = append(features, NewPointFeature(cl.ID, cl.Location, map[string]any{
+			"kind":  "cluster",
+			"count": cl.Count,
+			"bbox":  cl.Bounds,
+		}))
+	}
+
+	return GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
@@ -30,6 +30,8 @@ type (
 		ImageURL string    `json:"imageUrl" db:"imageurl"`
 		Price    int       `json:"price" db:"price"`
 		CreateAt time.Time `json:"createdAt" db:"created_at"`
+
+		IsAvailable bool `json:"isAvailable" db:"is_available"`
 	}
 
 	MerchantResponse struct {
@@ -56,6 +58,20 @@ type (
 		Price           int    `json:"price" validate:"required,min=1"`
 	}
 
+	OpeningHour struct {
+		DayOfWeek int    `json:"dayOfWeek" validate:"min=0,max=6"`
+		OpensAt   string `json:"opensAt" validate:"required,datetime=15:04"`
+		ClosesAt  string `json:"closesAt" validate:"required,datetime=15:04"`
+	}
+
+	SetItemAvailabilityRequest struct {
+		IsAvailable *bool `json:"isAvailable" validate:"required"`
+	}
+
+	SetOpeningHoursRequest struct {
+		OpeningHours []OpeningHour `json:"openingHours" validate:"dive"`
+	}
+
 	CreateMerchantResponse struct {
 		ID string `json:"merchantId" db:"id"`
 	}
@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type (
+	AcceptMerchantOrderRequest struct {
+		PrepMinutes int `json:"prepMinutes" validate:"required,min=1,max=240"`
+	}
+
+	RejectMerchantOrderRequest struct {
+		ReasonCode string `json:"reasonCode" validate:"required,oneof=MerchantClosed OutOfStock Other"`
+		Note       string `json:"note" validate:"max=255"`
+	}
+
+	SetMerchantOwnerRequest struct {
+		UserID string `json:"userId" validate:"required,uuid"`
+	}
+
+	MerchantOrderItem struct {
+		ItemID   string `json:"itemId"`
+		Name     string `json:"name"`
+		Quantity int    `json:"quantity"`
+		Price    int    `json:"price"`
+	}
+
+	MerchantOrder struct {
+		MerchantOrderID string              `json:"merchantOrderId"`
+		OrderID         string              `json:"orderId"`
+		MerchantID      string              `json:"merchantId"`
+		Status          string              `json:"status"`
+		PrepMinutes     *int                `json:"prepMinutes,omitempty"`
+		AcceptedAt      *time.Time          `json:"acceptedAt,omitempty"`
+		ReadyBy         *time.Time          `json:"readyBy,omitempty"`
+		ReadyAt         *time.Time          `json:"readyAt,omitempty"`
+		ScheduledFor    *time.Time          `json:"scheduledFor,omitempty"`
+		Reason          string              `json:"reason,omitempty"`
+		CreatedAt       time.Time           `json:"createdAt"`
+		Items           []MerchantOrderItem `json:"items"`
+	}
+
+	MerchantOrderResponse struct {
+		Data []MerchantOrder `json:"data"`
+		Meta Meta            `json:"meta"`
+	}
+)
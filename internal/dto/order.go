@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+type (
+	UpdateOrderStatusRequest struct {
+		Status string `json:"status" validate:"required,oneof=Accepted Preparing PickedUp Delivered"`
+		Reason string `json:"reason" validate:"max=255"`
+	}
+
+	CancelOrderRequest struct {
+		Note string `json:"note" validate:"max=255"`
+	}
+
+	OperatorCancelOrderRequest struct {
+		ReasonCode  string `json:"reasonCode" validate:"required,oneof=CustomerRequest MerchantClosed OutOfStock CourierUnavailable SuspectedFraud Other"`
+		InitiatedBy string `json:"initiatedBy" validate:"required,oneof=Merchant Admin"`
+		Note        string `json:"note" validate:"max=255"`
+	}
+
+	Refund struct {
+		RefundID string `json:"refundId"`
+		Amount   int    `json:"amount"`
+		Status   string `json:"status"`
+	}
+
+	CancelOrderResponse struct {
+		OrderID string  `json:"orderId"`
+		Status  string  `json:"status"`
+		Fee     int     `json:"cancellationFee"`
+		Refund  *Refund `json:"refund,omitempty"`
+	}
+
+	OrderStatusChange struct {
+		FromStatus string    `json:"fromStatus,omitempty"`
+		ToStatus   string    `json:"toStatus"`
+		Reason     string    `json:"reason,omitempty"`
+		CreatedAt  time.Time `json:"createdAt"`
+	}
+
+	OrderStatusResponse struct {
+		OrderID string              `json:"orderId"`
+		Status  string              `json:"status"`
+		History []OrderStatusChange `json:"history"`
+	}
+)
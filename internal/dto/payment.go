@@ -0,0 +1,12 @@
+package dto
+
+type (
+	Payment struct {
+		PaymentID     string `json:"paymentId"`
+		Method        string `json:"method"`
+		Amount        int    `json:"amount"`
+		Status        string `json:"status"`
+		ClientSecret  string `json:"clientSecret,omitempty"`
+		FailureReason string `json:"failureReason,omitempty"`
+	}
+)
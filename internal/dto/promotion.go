@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+type (
+	CreatePromotionRequest struct {
+		Code               string    `json:"code" validate:"required,min=3,max=32,alphanum"`
+		Description        string    `json:"description" validate:"max=200"`
+		Type               string    `json:"type" validate:"required,oneof=Percentage Fixed FreeDelivery"`
+		Value              int       `json:"value" validate:"min=0"`
+		MaxDiscount        int       `json:"maxDiscount" validate:"min=0"`
+		MinBasket          int       `json:"minBasket" validate:"min=0"`
+		GlobalLimit        int       `json:"globalLimit" validate:"min=0"`
+		PerUserLimit       int       `json:"perUserLimit" validate:"min=0"`
+		MerchantIDs        []string  `json:"merchantIds" validate:"dive,uuid"`
+		MerchantCategories []string  `json:"merchantCategories" validate:"dive,oneof=SmallRestaurant MediumRestaurant LargeRestaurant MerchandiseRestaurant BoothKiosk ConvenienceStore"`
+		ProductCategories  []string  `json:"productCategories" validate:"dive,oneof=Beverage Food Snack Condiments Additions"`
+		StartsAt           time.Time `json:"startsAt" validate:"required"`
+		EndsAt             time.Time `json:"endsAt" validate:"required"`
+	}
+
+	CreatePromotionResponse struct {
+		ID string `json:"promotionId"`
+	}
+
+	SetPromotionActiveRequest struct {
+		IsActive *bool `json:"isActive" validate:"required"`
+	}
+
+	Promotion struct {
+		ID                 string    `json:"promotionId"`
+		Code               string    `json:"code"`
+		Description        string    `json:"description"`
+		Type               string    `json:"type"`
+		Value              int       `json:"value"`
+		MaxDiscount        int       `json:"maxDiscount"`
+		MinBasket          int       `json:"minBasket"`
+		GlobalLimit        int       `json:"globalLimit"`
+		PerUserLimit       int       `json:"perUserLimit"`
+		UsedCount          int       `json:"usedCount"`
+		MerchantIDs        []string  `json:"merchantIds"`
+		MerchantCategories []string  `json:"merchantCategories"`
+		ProductCategories  []string  `json:"productCategories"`
+		StartsAt           time.Time `json:"startsAt"`
+		EndsAt             time.Time `json:"endsAt"`
+		IsActive           bool      `json:"isActive"`
+		CreatedAt          time.Time `json:"createdAt"`
+	}
+
+	PromotionResponse struct {
+		Data []Promotion `json:"data"`
+		Meta Meta        `json:"meta"`
+	}
+)
@@ -1,18 +1,93 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type (
+	// EstimateReq is delivered either to the saved address AddressID or to
+	// UserLocation, exactly one of them must be set.
 	EstimateReq struct {
-		UserID       string          `json:"_"`
+		UserID       string          `json:"-"`
 		UserPurchase []EstimateOrder `json:"orders" validate:"required,dive"`
-		UserLocation Location        `json:"userLocation" validate:"required"`
+		AddressID    string          `json:"addressId,omitempty" validate:"omitempty,uuid"`
+		UserLocation *Location       `json:"userLocation,omitempty" validate:"required_without=AddressID"`
+		PromoCode    string          `json:"promoCode" validate:"omitempty,max=32"`
+		ScheduledFor *time.Time      `json:"scheduledFor"`
 	}
 
 	EstimateRes struct {
-		TotalPrice                   int    `json:"totalPrice"`
-		CalculatedEstimateId         string `json:"calculatedEstimateId"`
-		EstimatedDeliveryTimeMinutes int    `json:"estimatedDeliveryTimeInMinutes"`
+		TotalPrice                   int             `json:"totalPrice"`
+		CalculatedEstimateId         string          `json:"calculatedEstimateId"`
+		EstimatedDeliveryTimeMinutes int             `json:"estimatedDeliveryTimeInMinutes"`
+		ETABreakdown                 ETABreakdown    `json:"etaBreakdown"`
+		Route                        []EstimateRoute `json:"route"`
+		PriceBreakdown               PriceBreakdown  `json:"priceBreakdown"`
+		ExpiresAt                    time.Time       `json:"expiresAt"`
+		ScheduledFor                 *time.Time      `json:"scheduledFor,omitempty"`
+		Delivery                     Delivery        `json:"delivery"`
+	}
+
+	// EstimateDetail is a stored estimate with the quote behind it: the request
+	// as it was sent, the route, the price breakdown and the algorithm version.
+	EstimateDetail struct {
+		CalculatedEstimateId         string          `json:"calculatedEstimateId"`
+		TotalPrice                   int             `json:"totalPrice"`
+		EstimatedDeliveryTimeMinutes int             `json:"estimatedDeliveryTimeInMinutes"`
+		ETABreakdown                 ETABreakdown    `json:"etaBreakdown"`
+		StartMerchantID              string          `json:"startMerchantId"`
+		RouteDistanceKm              float64         `json:"routeDistanceInKm"`
+		Route                        []EstimateRoute `json:"route"`
+		PriceBreakdown               PriceBreakdown  `json:"priceBreakdown"`
+		Delivery                     Delivery        `json:"delivery"`
+		Input                        json.RawMessage `json:"input"`
+		AlgorithmVersion             string          `json:"algorithmVersion"`
+		ScheduledFor                 *time.Time      `json:"scheduledFor,omitempty"`
+		CreatedAt                    time.Time       `json:"createdAt"`
+		ExpiresAt                    time.Time       `json:"expiresAt"`
+		Expired                      bool            `json:"expired"`
+		Used                         bool            `json:"used"`
+	}
+
+	// ETABreakdown splits estimatedDeliveryTimeInMinutes into the slowest
+	// kitchen, the drive and the time spent at pickups.
+	ETABreakdown struct {
+		PrepMinutes   float64 `json:"prepMinutes"`
+		TravelMinutes float64 `json:"travelMinutes"`
+		DwellMinutes  float64 `json:"dwellMinutes"`
+	}
+
+	MerchantSubtotal struct {
+		MerchantID string `json:"merchantId"`
+		Subtotal   int    `json:"subtotal"`
+	}
+
+	PriceBreakdown struct {
+		City             string             `json:"city"`
+		Merchants        []MerchantSubtotal `json:"merchants"`
+		ItemsSubtotal    int                `json:"itemsSubtotal"`
+		DeliveryFee      int                `json:"deliveryFee"`
+		StopSurcharge    int                `json:"stopSurcharge"`
+		ServiceFee       int                `json:"serviceFee"`
+		PromoCode        string             `json:"promoCode,omitempty"`
+		Discount         int                `json:"discount"`
+		DeliveryDiscount int                `json:"deliveryDiscount"`
+		TaxName          string             `json:"taxName"`
+		TaxRate          float64            `json:"taxRate"`
+		Taxable          int                `json:"taxable"`
+		Tax              int                `json:"tax"`
+		Rounding         int                `json:"rounding"`
+		Total            int                `json:"total"`
+	}
+
+	EstimateRoute struct {
+		Sequence        int      `json:"sequence"`
+		MerchantID      string   `json:"merchantId,omitempty"`
+		IsDestination   bool     `json:"isDestination"`
+		Location        Location `json:"location"`
+		DistanceKm      float64  `json:"distanceFromPreviousInKm"`
+		DurationMinutes float64  `json:"durationFromPreviousInMinutes"`
 	}
 
 	EstimateOrder struct {
@@ -27,15 +102,47 @@ type (
 	}
 
 	CreateOrderRequest struct {
-		EstimateID string `json:"calculatedEstimateId" validate:"required"`
+		UserID        string `json:"-"`
+		EstimateID    string `json:"calculatedEstimateId" validate:"required"`
+		PaymentMethod string `json:"paymentMethod" validate:"omitempty,oneof=cod fake wallet"`
 	}
 
 	CreateOrderResponse struct {
-		OrderID string `json:"orderId"`
+		OrderID      string     `json:"orderId"`
+		Status       string     `json:"status"`
+		TotalPrice   int        `json:"totalPrice"`
+		ScheduledFor *time.Time `json:"scheduledFor,omitempty"`
+		Payment      *Payment   `json:"payment,omitempty"`
+	}
+
+	ReorderRequest struct {
+		AddressID    string    `json:"addressId" validate:"omitempty,uuid"`
+		UserLocation *Location `json:"userLocation" validate:"required_without=AddressID"`
+	}
+
+	// ReorderResponse is a past order rebuilt as an estimate request for a new
+	// location, ready to be sent to POST /users/estimate, with the items that
+	// had to be left out.
+	ReorderResponse struct {
+		EstimateRequest EstimateReq       `json:"estimateRequest"`
+		Unavailable     []UnavailableItem `json:"unavailable"`
+	}
+
+	UnavailableItem struct {
+		MerchantID string `json:"merchantId"`
+		ItemID     string `json:"itemId"`
+		Name       string `json:"name"`
+		Quantity   int    `json:"quantity"`
+		// ItemUnavailable, MerchantClosed or OutOfRange
+		Reason string `json:"reason"`
 	}
 
 	OrderHistory struct {
 		OrderID      string                 `json:"orderId"`
+		Status       string                 `json:"status"`
+		CreatedAt    time.Time              `json:"createdAt"`
+		UpdatedAt    time.Time              `json:"updatedAt"`
+		ScheduledFor *time.Time             `json:"scheduledFor,omitempty"`
 		OrderHistory []OrderHistoryMerchant `json:"orders"`
 	}
 
@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+type (
+	// EInvoice is the structured form of an order's receipt, for corporate
+	// accounts to import. Amounts are in rupiah; each merchant sells its own
+	// lines under its own invoice number with its share of the order's tax,
+	// and the order level tax is the sum of those shares.
+	EInvoice struct {
+		OrderID        string            `json:"orderId"`
+		Status         string            `json:"status"`
+		OrderedAt      time.Time         `json:"orderedAt"`
+		Currency       string            `json:"currency"`
+		Buyer          InvoiceBuyer      `json:"buyer"`
+		Delivery       Delivery          `json:"delivery"`
+		Invoices       []MerchantInvoice `json:"invoices"`
+		PriceBreakdown PriceBreakdown    `json:"priceBreakdown"`
+		Tax            InvoiceTax        `json:"tax"`
+		Total          int               `json:"total"`
+	}
+
+	InvoiceBuyer struct {
+		UserID   string `json:"userId"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+
+	MerchantInvoice struct {
+		InvoiceNumber string        `json:"invoiceNumber"`
+		Sequence      int64         `json:"sequence"`
+		IssuedAt      time.Time     `json:"issuedAt"`
+		Seller        InvoiceSeller `json:"seller"`
+		Lines         []InvoiceLine `json:"lines"`
+		Subtotal      int           `json:"subtotal"`
+		Tax           InvoiceTax    `json:"tax"`
+	}
+
+	InvoiceSeller struct {
+		MerchantID string   `json:"merchantId"`
+		Name       string   `json:"name"`
+		Location   Location `json:"location"`
+	}
+
+	// InvoiceLine has a null unitPrice and amount when the price the item was
+	// ordered at is not known, for orders placed before prices were kept.
+	InvoiceLine struct {
+		ItemID    string `json:"itemId"`
+		Name      string `json:"name"`
+		Category  string `json:"category"`
+		Quantity  int    `json:"quantity"`
+		UnitPrice *int   `json:"unitPrice"`
+		Amount    *int   `json:"amount"`
+	}
+
+	InvoiceTax struct {
+		Name   string  `json:"name"`
+		Rate   float64 `json:"rate"`
+		Base   int     `json:"base"`
+		Amount int     `json:"amount"`
+	}
+)
@@ -0,0 +1,70 @@
+package dto
+
+import "time"
+
+type (
+	// CreateReviewRequest rates one merchant of a delivered order and,
+	// optionally, the items the user had from it. Photos are uploaded first
+	// through POST /users/reviews/photos and referenced by the link it
+	// returned; links to anything else are refused.
+	CreateReviewRequest struct {
+		MerchantID string       `json:"merchantId" validate:"required,uuid"`
+		Rating     int          `json:"rating" validate:"required,min=1,max=5"`
+		Comment    string       `json:"comment" validate:"max=1000"`
+		PhotoURLs  []string     `json:"photoUrls" validate:"max=4,dive,validUrl"`
+		Items      []ItemRating `json:"items" validate:"dive"`
+	}
+
+	ItemRating struct {
+		ItemID string `json:"itemId" validate:"required,uuid"`
+		Rating int    `json:"rating" validate:"required,min=1,max=5"`
+	}
+
+	CreateCourierReviewRequest struct {
+		Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+		Comment string `json:"comment" validate:"max=1000"`
+	}
+
+	ModerateReviewRequest struct {
+		Status string `json:"status" validate:"required,oneof=Published Hidden"`
+		Reason string `json:"reason" validate:"max=500"`
+	}
+
+	// Review carries its photos as signed links that work for a week from
+	// when the review was read.
+	Review struct {
+		ID               string       `json:"reviewId"`
+		OrderID          string       `json:"orderId"`
+		MerchantID       string       `json:"merchantId"`
+		Rating           int          `json:"rating"`
+		Comment          string       `json:"comment"`
+		PhotoURLs        []string     `json:"photoUrls"`
+		Items            []ItemRating `json:"items"`
+		Status           string       `json:"status"`
+		ModerationReason string       `json:"moderationReason,omitempty"`
+		ModeratedAt      *time.Time   `json:"moderatedAt,omitempty"`
+		CreatedAt        time.Time    `json:"createdAt"`
+	}
+
+	CourierReview struct {
+		ID               string     `json:"reviewId"`
+		OrderID          string     `json:"orderId"`
+		CourierID        string     `json:"courierId"`
+		Rating           int        `json:"rating"`
+		Comment          string     `json:"comment"`
+		Status           string     `json:"status"`
+		ModerationReason string     `json:"moderationReason,omitempty"`
+		ModeratedAt      *time.Time `json:"moderatedAt,omitempty"`
+		CreatedAt        time.Time  `json:"createdAt"`
+	}
+
+	ReviewResponse struct {
+		Data []Review `json:"data"`
+		Meta Meta     `json:"meta"`
+	}
+
+	CourierReviewResponse struct {
+		Data []CourierReview `json:"data"`
+		Meta Meta            `json:"meta"`
+	}
+)
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type (
+	LocationPing struct {
+		Lat        float64   `json:"lat"  validate:"required,min=-90,max=90"`
+		Lon        float64   `json:"long" validate:"required,min=-180,max=180"`
+		RecordedAt time.Time `json:"recordedAt" validate:"required"`
+		AccuracyM  *float32  `json:"accuracyMeters" validate:"omitempty,min=0"`
+		SpeedKmh   *float32  `json:"speedKmh" validate:"omitempty,min=0"`
+	}
+
+	LocationBatchRequest struct {
+		Points []LocationPing `json:"points" validate:"required,min=1,max=500,dive"`
+	}
+
+	LocationBatchResponse struct {
+		Accepted int     `json:"accepted"`
+		OrderID  *string `json:"orderId,omitempty"`
+	}
+
+	Trip struct {
+		OrderID          string     `json:"orderId"`
+		Polyline         string     `json:"polyline"`
+		Points           int        `json:"points"`
+		DistanceKm       float64    `json:"distanceKm"`
+		DurationMinutes  float64    `json:"durationMinutes"`
+		EstimatedMinutes *int       `json:"estimatedMinutes,omitempty"`
+		StartedAt        *time.Time `json:"startedAt,omitempty"`
+		EndedAt          *time.Time `json:"endedAt,omitempty"`
+	}
+
+	ETAAccuracy struct {
+		Trips               int     `json:"trips"`
+		MeanErrorMinutes    float64 `json:"meanErrorMinutes"`
+		MeanAbsErrorMinutes float64 `json:"meanAbsoluteErrorMinutes"`
+		MeanAbsPctError     float64 `json:"meanAbsolutePercentageError"`
+		P50AbsErrorMinutes  float64 `json:"p50AbsoluteErrorMinutes"`
+		P90AbsErrorMinutes  float64 `json:"p90AbsoluteErrorMinutes"`
+		WithinFiveMinutes   float64 `json:"withinFiveMinutesShare"`
+	}
+)
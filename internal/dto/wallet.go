@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type (
+	Wallet struct {
+		Balance   int       `json:"balance"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	WalletTransaction struct {
+		TransactionID string    `json:"transactionId"`
+		Type          string    `json:"type"`
+		Reference     string    `json:"reference"`
+		Description   string    `json:"description"`
+		Amount        int       `json:"amount"`
+		BalanceAfter  int       `json:"balanceAfter"`
+		CreatedAt     time.Time `json:"createdAt"`
+	}
+
+	WalletTransactionResponse struct {
+		Data []WalletTransaction `json:"data"`
+		Meta Meta                `json:"meta"`
+	}
+
+	CreditWalletRequest struct {
+		Amount      int    `json:"amount" validate:"required,gt=0"`
+		Type        string `json:"type" validate:"required,oneof=TopUp PromoCredit"`
+		Reference   string `json:"reference" validate:"required,max=100"`
+		Description string `json:"description" validate:"max=255"`
+	}
+
+	CreditWalletResponse struct {
+		TransactionID string `json:"transactionId"`
+		Balance       int    `json:"balance"`
+	}
+)
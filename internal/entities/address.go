@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+type (
+	Address struct {
+		ID        string `db:"id"`
+		UserID    string `db:"user_id"`
+		Label     string `db:"label"`
+		Location  Location
+		Building  string    `db:"building"`
+		Floor     string    `db:"floor"`
+		Notes     string    `db:"notes"`
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	// Delivery is where an estimate or order goes, with the saved address it
+	// was taken from, if any.
+	Delivery struct {
+		AddressID *string `db:"delivery_address_id"`
+		Location  Location
+		Building  string `db:"delivery_building"`
+		Floor     string `db:"delivery_floor"`
+		Notes     string `db:"delivery_notes"`
+	}
+)
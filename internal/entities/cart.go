@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+type (
+	// CartLine is an item in a user's cart joined with the item and merchant
+	// as they are now.
+	CartLine struct {
+		ItemID     string    `db:"item_id"`
+		MerchantID string    `db:"merchant_id"`
+		Quantity   int       `db:"quantity"`
+		PriceAtAdd int       `db:"price_at_add"`
+		CreatedAt  time.Time `db:"created_at"`
+
+		Item     MercItem
+		Merchant Merchant
+	}
+)
@@ -0,0 +1,63 @@
+package entities
+
+import "time"
+
+const (
+	CourierOffline   = "Offline"
+	CourierAvailable = "Available"
+	CourierBusy      = "Busy"
+
+	OfferPending   = "Pending"
+	OfferAccepted  = "Accepted"
+	OfferDeclined  = "Declined"
+	OfferExpired   = "Expired"
+	OfferCancelled = "Cancelled"
+)
+
+type (
+	Courier struct {
+		ID         string     `db:"id"`
+		UserID     string     `db:"user_id"`
+		Status     string     `db:"status"`
+		Location   *Location  `db:"location"`
+		LastSeenAt *time.Time `db:"last_seen_at"`
+		CreatedAt  time.Time  `db:"created_at"`
+		UpdatedAt  time.Time  `db:"updated_at"`
+	}
+
+	DispatchOffer struct {
+		ID          string     `db:"id"`
+		OrderID     string     `db:"order_id"`
+		CourierID   string     `db:"courier_id"`
+		Status      string     `db:"status"`
+		Score       float64    `db:"score"`
+		ApproachKm  float64    `db:"approach_km"`
+		RouteKm     float64    `db:"route_km"`
+		ExpiresAt   time.Time  `db:"expires_at"`
+		RespondedAt *time.Time `db:"responded_at"`
+		CreatedAt   time.Time  `db:"created_at"`
+
+		// computed against the database clock when the offer is loaded
+		Expired bool
+	}
+
+	// DispatchOrder is an order waiting for a courier: where the courier picks
+	// up first and how long the quoted route runs from there.
+	DispatchOrder struct {
+		OrderID          string
+		PickupMerchantID string
+		Pickup           Location
+		RouteKm          float64
+	}
+
+	// CourierCandidate is an available courier near a pickup. StraightKm comes
+	// from PostGIS; ApproachKm and ApproachMinutes from the routing provider.
+	CourierCandidate struct {
+		CourierID       string
+		Location        Location
+		StraightKm      float64
+		ApproachKm      float64
+		ApproachMinutes float64
+		IdleFor         time.Duration
+	}
+)
@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+type (
+	// ETAModel holds the coefficients learned from completed orders. SpeedKmh
+	// has one entry per local hour of day.
+	ETAModel struct {
+		ID               int64     `db:"id"`
+		StopDwellMinutes float64   `db:"stop_dwell_minutes"`
+		SpeedKmh         []float64 `db:"speed_kmh"`
+		SegmentSamples   int       `db:"segment_samples"`
+		TripSamples      int       `db:"trip_samples"`
+		CreatedAt        time.Time `db:"created_at"`
+	}
+
+	MerchantPrepStat struct {
+		MerchantID    string    `db:"merchant_id"`
+		MedianMinutes float64   `db:"median_minutes"`
+		P90Minutes    float64   `db:"p90_minutes"`
+		Samples       int       `db:"samples"`
+		UpdatedAt     time.Time `db:"updated_at"`
+	}
+
+	// SpeedBucket is the median speed couriers moved at during one hour.
+	SpeedBucket struct {
+		Hour      time.Time
+		MedianKmh float64
+		Samples   int
+	}
+
+	// TripSample is a delivered trip with the number of merchants it picked up from.
+	TripSample struct {
+		StartedAt       time.Time
+		DurationSeconds int
+		DistanceKm      float64
+		Stops           int
+	}
+)
@@ -0,0 +1,53 @@
+package entities
+
+import "time"
+
+const (
+	AccountCustomerWallet = "CustomerWallet"
+	AccountCashClearing   = "CashClearing"
+	AccountRevenue        = "Revenue"
+	AccountPromoExpense   = "PromoExpense"
+
+	LedgerTopUp        = "TopUp"
+	LedgerOrderPayment = "OrderPayment"
+	LedgerRefund       = "Refund"
+	LedgerPromoCredit  = "PromoCredit"
+)
+
+type (
+	LedgerAccount struct {
+		ID        string    `db:"id"`
+		OwnerID   *string   `db:"owner_id"`
+		Kind      string    `db:"kind"`
+		Balance   int       `db:"balance"`
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+
+	LedgerTransaction struct {
+		ID          string    `db:"id"`
+		Kind        string    `db:"kind"`
+		Reference   string    `db:"reference"`
+		Description string    `db:"description"`
+		CreatedBy   *string   `db:"created_by"`
+		CreatedAt   time.Time `db:"created_at"`
+	}
+
+	// LedgerLeg is one side of a transaction before it is posted. A positive
+	// amount raises the account balance, a negative one lowers it.
+	LedgerLeg struct {
+		AccountID string
+		Amount    int
+	}
+
+	// WalletEntry is a movement on a customer wallet with its transaction.
+	WalletEntry struct {
+		TransactionID string    `db:"transaction_id"`
+		Kind          string    `db:"kind"`
+		Reference     string    `db:"reference"`
+		Description   string    `db:"description"`
+		Amount        int       `db:"amount"`
+		BalanceAfter  int       `db:"balance_after"`
+		CreatedAt     time.Time `db:"created_at"`
+	}
+)
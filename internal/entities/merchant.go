@@ -15,6 +15,10 @@ type (
 		ImageURL  string `db:"imageurl"`
 		Location  Location
 		CreatedAt time.Time `db:"created_at"`
+
+		RatingAvg   float64 `db:"rating_avg"`
+		RatingCount int     `db:"rating_count"`
+		PrepMinutes int     `db:"prep_minutes"`
 	}
 
 	MercItem struct {
@@ -25,6 +29,17 @@ type (
 		ImageURL   string    `db:"imageurl"`
 		Price      int       `db:"price"`
 		CreatedAt  time.Time `db:"created_at"`
+
+		IsAvailable bool    `db:"is_available"`
+		RatingAvg   float64 `db:"rating_avg"`
+		RatingCount int     `db:"rating_count"`
+	}
+
+	OpeningHour struct {
+		MerchantID string `db:"merchant_id"`
+		DayOfWeek  int    `db:"day_of_week"`
+		OpensAt    string `db:"opens_at"`
+		ClosesAt   string `db:"closes_at"`
 	}
 
 	MerchantFilter struct {
@@ -45,3 +60,25 @@ type (
 		Offset          int
 	}
 )
+
+type (
+	BoundingBox struct {
+		MinLon float64
+		MinLat float64
+		MaxLon float64
+		MaxLat float64
+	}
+
+	MapFilter struct {
+		BBox             BoundingBox
+		Zoom             int
+		MerchantCategory string
+	}
+
+	MerchantCluster struct {
+		Cell     string
+		Count    int
+		Location Location
+		Bounds   BoundingBox
+	}
+)
@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+const (
+	MerchantOrderPending   = "Pending"
+	MerchantOrderAccepted  = "Accepted"
+	MerchantOrderReady     = "Ready"
+	MerchantOrderPickedUp  = "PickedUp"
+	MerchantOrderRejected  = "Rejected"
+	MerchantOrderCancelled = "Cancelled"
+)
+
+type (
+	// MerchantOrder is one merchant's share of an order.
+	MerchantOrder struct {
+		ID           string     `db:"id"`
+		OrderID      string     `db:"order_id"`
+		MerchantID   string     `db:"merchant_id"`
+		Status       string     `db:"status"`
+		PrepMinutes  *int       `db:"prep_minutes"`
+		AcceptedAt   *time.Time `db:"accepted_at"`
+		ReadyBy      *time.Time `db:"ready_by"`
+		ReadyAt      *time.Time `db:"ready_at"`
+		Reason       string     `db:"reason"`
+		ScheduledFor *time.Time `db:"scheduled_for"`
+		CreatedAt    time.Time  `db:"created_at"`
+		UpdatedAt    time.Time  `db:"updated_at"`
+
+		Items []MerchantOrderItem
+	}
+
+	MerchantOrderItem struct {
+		ItemID   string `db:"item_id"`
+		Name     string `db:"name"`
+		Quantity int    `db:"quantity"`
+		Price    int    `db:"price"`
+	}
+
+	MerchantOrderFilter struct {
+		MerchantID string
+		Status     string
+		Limit      int
+		Offset     int
+	}
+)
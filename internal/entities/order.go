@@ -0,0 +1,63 @@
+package entities
+
+import "time"
+
+const (
+	OrderScheduled = "Scheduled"
+	OrderPlaced    = "Placed"
+	OrderAccepted  = "Accepted"
+	OrderPreparing = "Preparing"
+	OrderPickedUp  = "PickedUp"
+	OrderDelivered = "Delivered"
+	OrderCancelled = "Cancelled"
+	OrderRejected  = "Rejected"
+)
+
+const (
+	CancelledByUser     = "User"
+	CancelledByMerchant = "Merchant"
+	CancelledByAdmin    = "Admin"
+	CancelledBySystem   = "System"
+
+	ReasonCustomerRequest = "CustomerRequest"
+	ReasonPaymentFailed   = "PaymentFailed"
+
+	RefundPending   = "Pending"
+	RefundCompleted = "Completed"
+	RefundFailed    = "Failed"
+)
+
+type (
+	OrderStatusChange struct {
+		ID         string    `db:"id"`
+		OrderID    string    `db:"order_id"`
+		FromStatus *string   `db:"from_status"`
+		ToStatus   string    `db:"to_status"`
+		Reason     string    `db:"reason"`
+		ChangedBy  *string   `db:"changed_by"`
+		CreatedAt  time.Time `db:"created_at"`
+	}
+
+	OrderCancellation struct {
+		OrderID        string    `db:"order_id"`
+		ReasonCode     string    `db:"reason_code"`
+		Note           string    `db:"note"`
+		InitiatedBy    string    `db:"initiated_by"`
+		ActorID        *string   `db:"actor_id"`
+		PreviousStatus string    `db:"previous_status"`
+		Fee            int       `db:"fee"`
+		CreatedAt      time.Time `db:"created_at"`
+	}
+
+	Refund struct {
+		ID            string    `db:"id"`
+		OrderID       string    `db:"order_id"`
+		PaymentID     *string   `db:"payment_id"`
+		Amount        int       `db:"amount"`
+		Status        string    `db:"status"`
+		ProviderRef   *string   `db:"provider_ref"`
+		FailureReason string    `db:"failure_reason"`
+		CreatedAt     time.Time `db:"created_at"`
+		UpdatedAt     time.Time `db:"updated_at"`
+	}
+)
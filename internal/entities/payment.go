@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+type (
+	Payment struct {
+		ID            string    `db:"id"`
+		OrderID       string    `db:"order_id"`
+		UserID        string    `db:"user_id"`
+		Provider      string    `db:"provider"`
+		ProviderRef   *string   `db:"provider_ref"`
+		Amount        int       `db:"amount"`
+		Status        string    `db:"status"`
+		FailureReason string    `db:"failure_reason"`
+		CreatedAt     time.Time `db:"created_at"`
+		UpdatedAt     time.Time `db:"updated_at"`
+	}
+
+	WebhookEvent struct {
+		Provider string `db:"provider"`
+		EventID  string `db:"event_id"`
+		Payload  []byte `db:"payload"`
+	}
+
+	// PendingRefund is a refund waiting to be sent through the provider that
+	// took the original payment.
+	PendingRefund struct {
+		RefundID    string
+		PaymentID   string
+		UserID      string
+		Provider    string
+		ProviderRef string
+		Amount      int
+		// how many times the refund was sent before
+		Attempts int
+		// the payment is fully refunded once this refund goes through
+		FullRefund bool
+	}
+)
@@ -0,0 +1,59 @@
+package entities
+
+import "time"
+
+type (
+	Promotion struct {
+		ID                 string    `db:"id"`
+		Code               string    `db:"code"`
+		Description        string    `db:"description"`
+		Type               string    `db:"type"`
+		Value              int       `db:"value"`
+		MaxDiscount        int       `db:"max_discount"`
+		MinBasket          int       `db:"min_basket"`
+		GlobalLimit        int       `db:"global_limit"`
+		PerUserLimit       int       `db:"per_user_limit"`
+		UsedCount          int       `db:"used_count"`
+		MerchantIDs        []string  `db:"merchant_ids"`
+		MerchantCategories []string  `db:"merchant_categories"`
+		ProductCategories  []string  `db:"product_categories"`
+		StartsAt           time.Time `db:"starts_at"`
+		EndsAt             time.Time `db:"ends_at"`
+		IsActive           bool      `db:"is_active"`
+		CreatedAt          time.Time `db:"created_at"`
+
+		// computed against the database clock when the promotion is loaded
+		Started bool
+		Ended   bool
+	}
+
+	// PromotionLine is one purchased item as seen by the promotion scope rules.
+	PromotionLine struct {
+		MerchantID       string
+		MerchantCategory string
+		ProductCategory  string
+		Amount           int
+	}
+
+	AppliedPromotion struct {
+		PromotionID      string
+		Code             string
+		Discount         int
+		DeliveryDiscount int
+	}
+
+	Redemption struct {
+		PromotionID string `db:"promotion_id"`
+		UserID      string `db:"user_id"`
+		OrderID     string `db:"order_id"`
+		EstimateID  string `db:"estimate_id"`
+		Discount    int    `db:"discount"`
+	}
+
+	PromotionFilter struct {
+		Limit    int
+		Offset   int
+		Code     string
+		IsActive *bool
+	}
+)
@@ -1,17 +1,50 @@
 package entities
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type (
 	Estimate struct {
-		ID        string    `db:"id"`
-		UserID    string    `db:"user_id"`
-		CreatedAt time.Time `db:"created_at"`
+		ID             string         `db:"id"`
+		UserID         string         `db:"user_id"`
+		TotalPrice     int            `db:"total_price"`
+		PriceBreakdown PriceBreakdown `db:"price_breakdown"`
+		PromotionID    *string        `db:"promotion_id"`
+		Discount       int            `db:"discount"`
+		CreatedAt      time.Time      `db:"created_at"`
+		ExpiresAt      time.Time      `db:"expires_at"`
+		ScheduledFor   *time.Time     `db:"scheduled_for"`
+		ReleaseAt      *time.Time     `db:"release_at"`
+
+		// first stop and length of the quoted route, the quoted delivery time
+		// and its part after pickup, used by dispatch and to measure ETA accuracy
+		StartMerchantID  string  `db:"start_merchant_id"`
+		RouteDistanceKm  float64 `db:"route_distance_km"`
+		EstimatedMinutes int     `db:"estimated_delivery_minutes"`
+		TravelMinutes    int     `db:"travel_minutes"`
+
+		Delivery Delivery
+		Quote    Quote
+
+		// computed against the database clock when the estimate is loaded
+		Expired bool
+		Used    bool
 	}
 
 	Order struct {
-		ID         string `db:"id"`
-		EstimateID string `db:"estimate_id"`
+		ID           string     `db:"id"`
+		UserID       string     `db:"user_id"`
+		EstimateID   string     `db:"estimate_id"`
+		TotalPrice   int        `db:"total_price"`
+		Status       string     `db:"status"`
+		ScheduledFor *time.Time `db:"scheduled_for"`
+		CreatedAt    time.Time  `db:"created_at"`
+		UpdatedAt    time.Time  `db:"updated_at"`
+
+		// time since the order was placed, measured on the database clock
+		Age time.Duration
 	}
 
 	OrderItem struct {
@@ -20,6 +53,7 @@ type (
 		MerchantID     string `db:"merchant_id"`
 		MerchantItemID string `db:"merchant_item_id"`
 		Quantity       int    `db:"quantity"`
+		UnitPrice      int    `db:"unit_price"`
 	}
 
 	OrderFilter struct {
@@ -29,25 +63,32 @@ type (
 		MerchantCategory string
 		UserID           string
 		Offset           int
+		Status           string
+		// only orders scheduled for a time that has not come yet
+		UpcomingScheduled bool
 	}
 
 	OrderDetail struct {
-		UserID            string    `db:"user_id" json:"userId"`
-		ItemID            string    `db:"item_id" json:"itemId"`
-		MerchantID        string    `db:"merchant_id" json:"merchantId"`
-		MerchantImageURL  string    `db:"merchant_image_url" json:"merchantImageUrl"`
-		OrderID           string    `db:"order_id" json:"orderId"`
-		Quantity          int       `db:"quantity" json:"quantity"`
-		MerchantLat       float64   `db:"merchant_lat" json:"merchantLat"`
-		MerchantLon       float64   `db:"merchant_long" json:"merchantLong"`
-		MerchantName      string    `db:"merchant_name" json:"merchantName"`
-		MerchantCategory  string    `db:"merchant_category" json:"merchantCategory"`
-		ItemName          string    `db:"item_name" json:"itemName"`
-		ItemCategory      string    `db:"item_category" json:"itemCategory"`
-		ItemImageURL      string    `db:"item_image_url" json:"itemImageUrl"`
-		ItemPrice         int       `db:"item_price" json:"itemPrice"`
-		ItemCreatedAt     time.Time `db:"item_created_at" json:"itemCreatedAt"`
-		MerchantCreatedAt time.Time `db:"merchant_created_at" json:"merchantCreatedAt"`
+		UserID            string     `db:"user_id" json:"userId"`
+		ItemID            string     `db:"item_id" json:"itemId"`
+		MerchantID        string     `db:"merchant_id" json:"merchantId"`
+		MerchantImageURL  string     `db:"merchant_image_url" json:"merchantImageUrl"`
+		OrderID           string     `db:"order_id" json:"orderId"`
+		Quantity          int        `db:"quantity" json:"quantity"`
+		MerchantLat       float64    `db:"merchant_lat" json:"merchantLat"`
+		MerchantLon       float64    `db:"merchant_long" json:"merchantLong"`
+		MerchantName      string     `db:"merchant_name" json:"merchantName"`
+		MerchantCategory  string     `db:"merchant_category" json:"merchantCategory"`
+		ItemName          string     `db:"item_name" json:"itemName"`
+		ItemCategory      string     `db:"item_category" json:"itemCategory"`
+		ItemImageURL      string     `db:"item_image_url" json:"itemImageUrl"`
+		ItemPrice         int        `db:"item_price" json:"itemPrice"`
+		ItemCreatedAt     time.Time  `db:"item_created_at" json:"itemCreatedAt"`
+		MerchantCreatedAt time.Time  `db:"merchant_created_at" json:"merchantCreatedAt"`
+		OrderStatus       string     `db:"order_status" json:"orderStatus"`
+		OrderCreatedAt    time.Time  `db:"order_created_at" json:"orderCreatedAt"`
+		OrderUpdatedAt    time.Time  `db:"order_updated_at" json:"orderUpdatedAt"`
+		OrderScheduledFor *time.Time `db:"order_scheduled_for" json:"orderScheduledFor"`
 	}
 
 	MerchantNearbyFilter struct {
@@ -58,6 +99,14 @@ type (
 		Lon              float64
 		MerchantID       string
 		MerchantCategory string
+		ProductCategory  string
+		MinPrice         int
+		MaxPrice         int
+		MaxDistance      float64
+		OpenNow          bool
+		OpenAt           time.Time
+		FavoritesOnly    bool
+		SortBy           string
 		Limit            int
 	}
 
@@ -65,4 +114,70 @@ type (
 		Merchant
 		Items []MercItem
 	}
+
+	// Quote is the snapshot of how an estimate was worked out: the request as
+	// received, the route that was chosen, the ETA parts and which versions of
+	// the algorithms produced them.
+	Quote struct {
+		Input            json.RawMessage
+		Route            []QuoteStop
+		ETA              QuoteETA
+		AlgorithmVersion string
+	}
+
+	QuoteStop struct {
+		Sequence        int     `json:"sequence"`
+		MerchantID      string  `json:"merchantId,omitempty"`
+		IsDestination   bool    `json:"isDestination"`
+		Lat             float64 `json:"lat"`
+		Lon             float64 `json:"lon"`
+		DistanceKm      float64 `json:"distanceKm"`
+		DurationMinutes float64 `json:"durationMinutes"`
+	}
+
+	QuoteETA struct {
+		ModelID       int64   `json:"modelId"`
+		PrepMinutes   float64 `json:"prepMinutes"`
+		TravelMinutes float64 `json:"travelMinutes"`
+		DwellMinutes  float64 `json:"dwellMinutes"`
+	}
+
+	// ReorderLine is one item of a past order as it stands today.
+	ReorderLine struct {
+		UserID           string
+		MerchantID       string
+		MerchantLocation Location
+		ItemID           string
+		ItemName         string
+		Quantity         int
+		IsAvailable      bool
+		IsStartingPoint  bool
+	}
+)
+
+type (
+	MerchantSubtotal struct {
+		MerchantID string `json:"merchantId"`
+		Subtotal   int    `json:"subtotal"`
+	}
+
+	// PriceBreakdown is stored as JSON with the estimate, so the order charges
+	// exactly what was quoted even if prices or fee rules change afterwards.
+	PriceBreakdown struct {
+		City             string             `json:"city"`
+		Merchants        []MerchantSubtotal `json:"merchants"`
+		ItemsSubtotal    int                `json:"itemsSubtotal"`
+		DeliveryFee      int                `json:"deliveryFee"`
+		StopSurcharge    int                `json:"stopSurcharge"`
+		ServiceFee       int                `json:"serviceFee"`
+		PromoCode        string             `json:"promoCode,omitempty"`
+		Discount         int                `json:"discount"`
+		DeliveryDiscount int                `json:"deliveryDiscount"`
+		TaxName          string             `json:"taxName"`
+		TaxRate          float64            `json:"taxRate"`
+		Taxable          int                `json:"taxable"`
+		Tax              int                `json:"tax"`
+		Rounding         int                `json:"rounding"`
+		Total            int                `json:"total"`
+	}
 )
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+type (
+	// Invoice numbers the part of an order sold by one merchant. Sequence
+	// counts up without gaps per merchant.
+	Invoice struct {
+		ID         string    `db:"id"`
+		OrderID    string    `db:"order_id"`
+		MerchantID string    `db:"merchant_id"`
+		Sequence   int64     `db:"sequence"`
+		Number     string    `db:"number"`
+		IssuedAt   time.Time `db:"issued_at"`
+	}
+
+	// Receipt is everything printed on an order's receipt: the buyer, one
+	// invoice per merchant and the price breakdown stored with the estimate.
+	Receipt struct {
+		OrderID        string
+		UserID         string
+		Username       string
+		Email          string
+		Status         string
+		OrderedAt      time.Time
+		Delivery       Delivery
+		PriceBreakdown PriceBreakdown
+		Merchants      []ReceiptMerchant
+	}
+
+	ReceiptMerchant struct {
+		MerchantID string
+		Name       string
+		Location   Location
+		Invoice    Invoice
+		Lines      []ReceiptLine
+		// sum of the lines, or the quoted subtotal when a line price is unknown
+		Subtotal int
+		// the merchant's share of the order's taxable amount and tax
+		TaxBase int
+		Tax     int
+	}
+
+	// ReceiptLine is one item of a receipt. UnitPrice and Amount are nil for
+	// lines ordered before unit prices were stored with the order.
+	ReceiptLine struct {
+		ItemID    string
+		Name      string
+		Category  string
+		Quantity  int
+		UnitPrice *int
+		Amount    *int
+	}
+)
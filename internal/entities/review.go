@@ -0,0 +1,74 @@
+package entities
+
+import "time"
+
+const (
+	ReviewPublished = "Published"
+	ReviewHidden    = "Hidden"
+)
+
+type (
+	// Review is a user's rating of one merchant of a delivered order, with
+	// optional ratings of the items they had from it.
+	Review struct {
+		ID               string       `db:"id"`
+		OrderID          string       `db:"order_id"`
+		MerchantID       string       `db:"merchant_id"`
+		UserID           string       `db:"user_id"`
+		Rating           int          `db:"rating"`
+		Comment          string       `db:"comment"`
+		PhotoKeys        []string     `db:"photo_keys"`
+		Items            []ItemRating `db:"items"`
+		Status           string       `db:"status"`
+		ModerationReason string       `db:"moderation_reason"`
+		ModeratedBy      *string      `db:"moderated_by"`
+		ModeratedAt      *time.Time   `db:"moderated_at"`
+		CreatedAt        time.Time    `db:"created_at"`
+	}
+
+	ItemRating struct {
+		ItemID string `json:"itemId"`
+		Rating int    `json:"rating"`
+	}
+
+	CourierReview struct {
+		ID               string     `db:"id"`
+		OrderID          string     `db:"order_id"`
+		CourierID        string     `db:"courier_id"`
+		UserID           string     `db:"user_id"`
+		Rating           int        `db:"rating"`
+		Comment          string     `db:"comment"`
+		Status           string     `db:"status"`
+		ModerationReason string     `db:"moderation_reason"`
+		ModeratedBy      *string    `db:"moderated_by"`
+		ModeratedAt      *time.Time `db:"moderated_at"`
+		CreatedAt        time.Time  `db:"created_at"`
+	}
+
+	// ReviewableOrder is what is needed to decide whether an order can be
+	// reviewed: who placed it, how far it got, who delivered it and which items
+	// it had from each merchant.
+	ReviewableOrder struct {
+		UserID    string
+		Status    string
+		CourierID *string
+		Items     map[string][]string
+	}
+
+	// ReviewFilter selects reviews of a merchant or a courier, all of them when
+	// both are empty.
+	ReviewFilter struct {
+		MerchantID string
+		CourierID  string
+		Status     string
+		Limit      int
+		Offset     int
+	}
+
+	ReviewModeration struct {
+		ReviewID    string
+		Status      string
+		Reason      string
+		ModeratedBy string
+	}
+)
@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+type (
+	CourierLocation struct {
+		CourierID  string    `db:"courier_id"`
+		OrderID    *string   `db:"order_id"`
+		RecordedAt time.Time `db:"recorded_at"`
+		Lat        float64   `db:"lat"`
+		Lon        float64   `db:"lon"`
+		AccuracyM  *float32  `db:"accuracy_m"`
+		SpeedKmh   *float32  `db:"speed_kmh"`
+	}
+
+	// TripSource is a delivered order whose trip has not been built yet.
+	TripSource struct {
+		OrderID          string
+		CourierID        string
+		EstimatedMinutes *int
+		PickedUpAt       *time.Time
+		DeliveredAt      time.Time
+	}
+
+	Trip struct {
+		OrderID          string     `db:"order_id"`
+		CourierID        *string    `db:"courier_id"`
+		Polyline         string     `db:"polyline"`
+		RawPoints        int        `db:"raw_points"`
+		PolylinePoints   int        `db:"polyline_points"`
+		DistanceKm       float64    `db:"distance_km"`
+		DurationSeconds  int        `db:"duration_seconds"`
+		EstimatedMinutes *int       `db:"estimated_minutes"`
+		StartedAt        *time.Time `db:"started_at"`
+		EndedAt          *time.Time `db:"ended_at"`
+		CreatedAt        time.Time  `db:"created_at"`
+	}
+
+	ETAAccuracyFilter struct {
+		From *time.Time
+		To   *time.Time
+	}
+
+	// ETAAccuracy compares trip durations with the travel time the estimate
+	// quoted. Errors are actual minus estimated minutes, so positive means late.
+	ETAAccuracy struct {
+		Trips               int
+		MeanErrorMinutes    float64
+		MeanAbsErrorMinutes float64
+		MeanAbsPctError     float64
+		P50AbsErrorMinutes  float64
+		P90AbsErrorMinutes  float64
+		WithinFiveMinutes   float64
+	}
+)
@@ -0,0 +1,99 @@
+package eta
+
+import (
+	"belimang/internal/entities"
+	"sort"
+	"time"
+)
+
+// Calibrate learns a model from hourly speed buckets and delivered trips.
+// Hours with fewer than minSamples speed segments keep no speed, and the stop
+// dwell stays at its default until minSamples trips could be explained.
+func Calibrate(buckets []entities.SpeedBucket, trips []entities.TripSample, loc *time.Location, minSamples int) entities.ETAModel {
+	m := Default()
+	m.SpeedKmh, m.SegmentSamples = SpeedProfile(buckets, loc, minSamples)
+
+	if dwell, n := StopDwell(trips, m.SpeedKmh, loc); n >= minSamples {
+		m.StopDwellMinutes = dwell
+		m.TripSamples = n
+	}
+
+	return m
+}
+
+// SpeedProfile folds hourly buckets into one speed per local hour of day, the
+// median of the bucket medians weighted by their segment counts. It also
+// returns how many segments went into the hours that got a speed.
+func SpeedProfile(buckets []entities.SpeedBucket, loc *time.Location, minSamples int) ([]float64, int) {
+	byHour := make([][]weighted, 24)
+	for _, b := range buckets {
+		h := b.Hour.In(loc).Hour()
+		byHour[h] = append(byHour[h], weighted{value: b.MedianKmh, weight: b.Samples})
+	}
+
+	speeds := make([]float64, 24)
+	total := 0
+	for h, values := range byHour {
+		n := 0
+		for _, v := range values {
+			n += v.weight
+		}
+		if n < minSamples {
+			continue
+		}
+
+		speeds[h] = weightedMedian(values)
+		total += n
+	}
+
+	return speeds, total
+}
+
+// StopDwell estimates the minutes spent per pickup: whatever a trip took beyond
+// driving its distance at the speed of its hour, spread over its stops. Trips
+// in hours without a speed are skipped. It returns the median and how many
+// trips it was taken over.
+func StopDwell(trips []entities.TripSample, speeds []float64, loc *time.Location) (float64, int) {
+	perStop := make([]weighted, 0, len(trips))
+	for _, t := range trips {
+		h := t.StartedAt.In(loc).Hour()
+		if t.Stops <= 0 || h >= len(speeds) || speeds[h] <= 0 {
+			continue
+		}
+
+		driving := t.DistanceKm / speeds[h] * 60
+		excess := max(float64(t.DurationSeconds)/60-driving, 0)
+		perStop = append(perStop, weighted{value: excess / float64(t.Stops), weight: 1})
+	}
+
+	if len(perStop) == 0 {
+		 return 0, 0
+	}
+
+	return weightedMedian(perStop), len(perStop)
+}
+
+type weighted struct {
+	value  float64
+	weight int
+}
+
+func weightedMedian(values []weighted) float64 {
+	sorted := append([]weighted(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].value < sorted[j].value })
+
+	total := 0
+	for _, v := range sorted {
+		total += v.weight
+	}
+
+	seen := 0
+	for _, v := range sorted {
+		seen += v.weight
+		if seen*2 >= total {
+			return v.value
+		}
+	}
+
+	return 0
+}
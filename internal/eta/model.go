@@ -0,0 +1,103 @@
+// Package eta predicts how long a delivery takes from order to door: the
+// slowest kitchen on the route, the drive at the speed couriers actually make
+// at that hour, and the time spent at each pickup.
+package eta
+
+import (
+	"belimang/internal/entities"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultStopDwellMinutes is used until a calibration has seen enough trips.
+const DefaultStopDwellMinutes = 2.0
+
+// Default is the model used before the first recalibration: routed durations
+// as they are plus a fixed dwell per stop.
+func Default() entities.ETAModel {
+	return entities.ETAModel{
+		StopDwellMinutes: DefaultStopDwellMinutes,
+		SpeedKmh:         make([]float64, 24),
+	}
+}
+
+// Input describes one quote.
+type Input struct {
+	// expected preparation time of each merchant on the route
+	PrepMinutes   []float64
+	Stops         int
+	DistanceKm    float64
+	RoutedMinutes float64
+	// when the kitchen starts, picks the speed profile hour
+	At time.Time
+}
+
+// Estimate is a prediction split into its parts, in minutes, with the model
+// that made it. ModelID is 0 for the default model.
+type Estimate struct {
+	ModelID       int64
+	PrepMinutes   float64
+	TravelMinutes float64
+	DwellMinutes  float64
+}
+
+// Minutes is the whole prediction, rounded up.
+func (e Estimate) Minutes() int {
+	return int(math.Ceil(e.PrepMinutes + e.TravelMinutes + e.DwellMinutes))
+}
+
+// AfterPickupMinutes is the part of the prediction from the first pickup to
+// the door, which is what a courier trip measures.
+func (e Estimate) AfterPickupMinutes() int {
+	return int(math.Ceil(e.TravelMinutes + e.DwellMinutes))
+}
+
+// Predict applies m to in. Kitchens cook in parallel while the courier heads
+// to the first pickup, so only the slowest one counts. Travel uses the learned
+// speed for the hour when there is one and the routed duration otherwise.
+func Predict(m entities.ETAModel, in Input) Estimate {
+	e := Estimate{
+		ModelID:       m.ID,
+		TravelMinutes: in.RoutedMinutes,
+		DwellMinutes:  m.StopDwellMinutes * float64(in.Stops),
+	}
+
+	for _, p := range in.PrepMinutes {
+		e.PrepMinutes = max(e.PrepMinutes, p)
+	}
+
+	if speed := speedAt(m, in.At); speed > 0 && in.DistanceKm > 0 {
+		 e.TravelMinutes = in.DistanceKm / speed * 60
+	}
+
+	return e
+}
+
+func speedAt(m entities.ETAModel, t time.Time) float64 {
+	h := t.Local().Hour()
+	if h >= len(m.SpeedKmh) {
+		 return 0
+	}
+
+	return m.SpeedKmh[h]
+}
+
+// Store holds the model in use and lets it be swapped while estimates read it.
+type Store struct {
+	model atomic.Pointer[entities.ETAModel]
+}
+
+func NewStore(m entities.ETAModel) *Store {
+	s := &Store{}
+	s.Set(m)
+	return s
+}
+
+func (s *Store) Load() entities.ETAModel {
+	return *s.model.Load()
+}
+
+func (s *Store) Set(m entities.ETAModel) {
+	s.model.Store(&m)
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	TypeStatus          = "status"
+	TypeCourierAssigned = "courier_assigned"
+	TypeCourierLocation = "courier_location"
+)
+
+// subscriberBuffer bounds how far a slow subscriber may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 16
+
+// Event is a change on a single order pushed to everyone following it.
+type Event struct {
+	Type    string          `json:"type"`
+	OrderID string          `json:"orderId"`
+	Data    json.RawMessage `json:"data"`
+	At      time.Time       `json:"at"`
+}
+
+func NewEvent(typ, orderID string, data any) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		 return Event{}, err
+	}
+
+	return Event{Type: typ, OrderID: orderID, Data: raw, At: time.Now().UTC()}, nil
+}
+
+// Bus delivers order events to subscribers.
+type Bus interface {
+	Publish(ctx context.Context, e Event) error
+	Subscribe(orderID string) (<-chan Event, func())
+}
+
+// Hub is an in-process Bus. Events only reach subscribers of the same process.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan Event]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string]map[chan Event]struct{})}
+}
+
+func (h *Hub) Publish(_ context.Context, e Event) error {
+	h.dispatch(e)
+	return nil
+}
+
+// Subscribe returns a channel of events for orderID and a function that must
+// be called to release it.
+func (h *Hub) Subscribe(orderID string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[orderID] == nil {
+		 h.subs[orderID] = make(map[chan Event]struct{})
+	}
+	h.subs[orderID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[orderID], ch)
+			if len(h.subs[orderID]) == 0 {
+				 delete(h.subs, orderID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (h *Hub) dispatch(e Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subs[e.OrderID] {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// WriteSSE writes v as a single Server-Sent Events message named name.
+func WriteSSE(w io.Writer, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		 return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+	return err
+}
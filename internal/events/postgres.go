@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+)
+
+const notifyChannel = "order_events"
+
+// PostgresBus fans events out through Postgres LISTEN/NOTIFY so every API
+// instance connected to the same database delivers them to its own subscribers.
+type PostgresBus struct {
+	db  *pgxpool.Pool
+	hub *Hub
+}
+
+func NewPostgresBus(db *pgxpool.Pool) *PostgresBus {
+	return &PostgresBus{db: db, hub: NewHub()}
+}
+
+// Publish sends e to all instances, including this one, once it comes back
+// through Listen.
+func (b *PostgresBus) Publish(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		 return err
+	}
+
+	_, err = b.db.Exec(ctx, "SELECT pg_notify($1, $2)", notifyChannel, string(payload))
+	return err
+}
+
+func (b *PostgresBus) Subscribe(orderID string) (<-chan Event, func()) {
+	return b.hub.Subscribe(orderID)
+}
+
+// Listen holds a dedicated connection listening for notifications until ctx
+// is cancelled, reconnecting with a backoff when the connection drops.
+func (b *PostgresBus) Listen(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		if err := b.listen(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Dur("retry", backoff).Msg("order events listener disconnected")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(backoff*2, 30*time.Second)
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+func (b *PostgresBus) listen(ctx context.Context) error {
+	conn, err := b.db.Acquire(ctx)
+	if err != nil {
+		 return err
+	}
+	// the connection stays subscribed to the channel, so it must not go back to the pool
+	defer conn.Hijack().Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		 return err
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			 return err
+		}
+
+		e := Event{}
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			log.Warn().Err(err).Msg("dropping malformed order event")
+			continue
+		}
+
+		b.hub.dispatch(e)
+	}
+}
@@ -0,0 +1,192 @@
+package events
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The handful of RFC 6455 pieces needed to push JSON events to a browser.
+// Fragmented messages, extensions and subprotocols are not supported.
+
+const (
+	wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+
+	wsWriteTimeout   = 10 * time.Second
+	wsMaxFrameLength = 4096
+)
+
+var errFrameTooLarge = errors.New("websocket: frame too large")
+
+type WSConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+	mu   sync.Mutex
+}
+
+// IsWebSocketRequest reports whether r asks for a WebSocket upgrade.
+func IsWebSocketRequest(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		headerContainsToken(r.Header.Get("Connection"), "upgrade")
+}
+
+func headerContainsToken(header, token string) bool {
+	for _, part := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			 return true
+		}
+	}
+	return false
+}
+
+// UpgradeWebSocket completes the opening handshake and takes over the
+// underlying connection. Nothing may be written to w afterwards.
+func UpgradeWebSocket(w http.ResponseWriter, r *http.Request) (*WSConn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		 return nil, errors.New("websocket: bad handshake")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		 return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + wsGUID))
+	accept := base64.StdEncoding.EncodeToString(sum[:])
+
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	rw.WriteString("Upgrade: websocket\r\nConnection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + accept + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &WSConn{conn: conn, rw: rw}, nil
+}
+
+func (c *WSConn) WriteJSON(v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		 return err
+	}
+	return c.writeFrame(opText, payload)
+}
+
+func (c *WSConn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	header := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126)
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if _, err := c.rw.Write(header); err != nil {
+		 return err
+	}
+	if _, err := c.rw.Write(payload); err != nil {
+		 return err
+	}
+	return c.rw.Flush()
+}
+
+// ReadLoop answers pings and returns once the client closes the connection or
+// it fails. Messages sent by the client are ignored.
+func (c *WSConn) ReadLoop() error {
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			 return err
+		}
+
+		switch op {
+		case opClose:
+			c.writeFrame(opClose, nil)
+			return nil
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				 return err
+			}
+		}
+	}
+}
+
+func (c *WSConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.rw, head[:]); err != nil {
+		 return 0, nil, err
+	}
+
+	op := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			 return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			 return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if length > wsMaxFrameLength {
+		 return 0, nil, errFrameTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+			 return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		 return 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			 payload[i] ^= mask[i%4]
+		}
+	}
+
+	return op, payload, nil
+}
+
+func (c *WSConn) Close() error {
+	c.writeFrame(opClose, nil)
+	return c.conn.Close()
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type AddressHandler struct {
+	service    services.AddressService
+	validation *validator.Validate
+}
+
+func NewAddressHandler(service services.AddressService, validation *validator.Validate) AddressHandler {
+	return AddressHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h AddressHandler) GetAddresses(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.service.GetAddresses(ctx, authCtx.ID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h AddressHandler) GetAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	addressId := chi.URLParam(r, "addressId")
+
+	resp, err := h.service.GetAddress(ctx, authCtx.ID, addressId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h AddressHandler) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.AddressRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.CreateAddress(ctx, authCtx.ID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, resp)
+}
+
+func (h AddressHandler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.AddressRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	addressId := chi.URLParam(r, "addressId")
+
+	resp, err := h.service.UpdateAddress(ctx, authCtx.ID, addressId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h AddressHandler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	addressId := chi.URLParam(r, "addressId")
+
+	if err := h.service.DeleteAddress(ctx, authCtx.ID, addressId); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, map[string]string{"addressId": addressId})
+}
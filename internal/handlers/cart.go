@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type CartHandler struct {
+	service    services.CartService
+	validation *validator.Validate
+}
+
+func NewCartHandler(service services.CartService, validation *validator.Validate) CartHandler {
+	return CartHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.service.GetCart(ctx, authCtx.ID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.AddCartItemRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.AddItem(ctx, authCtx.ID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.UpdateCartItemRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	itemId := chi.URLParam(r, "itemId")
+
+	resp, err := h.service.UpdateItem(ctx, authCtx.ID, itemId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	itemId := chi.URLParam(r, "itemId")
+
+	resp, err := h.service.RemoveItem(ctx, authCtx.ID, itemId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.service.ClearCart(ctx, authCtx.ID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CartHandler) CreateEstimate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.CartEstimateRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.CreateEstimate(ctx, authCtx.ID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
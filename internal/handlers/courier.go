@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type CourierHandler struct {
+	service    services.CourierService
+	validation *validator.Validate
+}
+
+func NewCourierHandler(service services.CourierService, validation *validator.Validate) CourierHandler {
+	return CourierHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h CourierHandler) CreateCourier(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.CreateCourierRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.CreateCourier(ctx, req.UserID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, resp)
+}
+
+func (h CourierHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.service.GetMe(ctx, authCtx.ID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CourierHandler) SetAvailability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.SetAvailabilityRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.SetAvailability(ctx, authCtx.ID, *req.Available)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CourierHandler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.HeartbeatRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.Heartbeat(ctx, authCtx.ID, req.Location)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CourierHandler) GetOffers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.service.GetOffers(ctx, authCtx.ID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CourierHandler) AcceptOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	offerId := chi.URLParam(r, "offerId")
+
+	resp, err := h.service.AcceptOffer(ctx, authCtx.ID, offerId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h CourierHandler) DeclineOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	offerId := chi.URLParam(r, "offerId")
+
+	resp, err := h.service.DeclineOffer(ctx, authCtx.ID, offerId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
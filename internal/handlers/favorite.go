@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type FavoriteHandler struct {
+	service    services.FavoriteService
+	validation *validator.Validate
+}
+
+func NewFavoriteHandler(service services.FavoriteService, validation *validator.Validate) FavoriteHandler {
+	return FavoriteHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h FavoriteHandler) GetFavorites(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	resp, err := h.service.GetFavorites(ctx, authCtx.ID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h FavoriteHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.FavoriteRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.AddFavorite(ctx, authCtx.ID, req); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, req)
+}
+
+// RemoveFavorite takes the merchantId or itemId from the query string.
+func (h FavoriteHandler) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	req := dto.FavoriteRequest{
+		MerchantID: q.Get("merchantId"),
+		ItemID:     q.Get("itemId"),
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.service.RemoveFavorite(ctx, authCtx.ID, req); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, req)
+}
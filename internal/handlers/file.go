@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"belimang/internal/middleware"
 	"belimang/internal/services"
 	"belimang/internal/utils"
+	"fmt"
+	"mime/multipart"
 	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
 type FileHandler struct {
@@ -17,6 +24,37 @@ func NewFileHandler(service services.FileService) FileHandler {
 }
 
 func (h FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
+	file, handler, ok := readImage(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	h.upload(w, r, file, handler, handler.Filename)
+}
+
+// UploadReviewPhoto stores a photo for a review under a fresh name in the
+// user's own folder, so users cannot overwrite each other's or merchants' images.
+func (h FileHandler) UploadReviewPhoto(w http.ResponseWriter, r *http.Request) {
+	authCtx, ok := middleware.GetAuthContext(r.Context())
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	file, handler, ok := readImage(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	objectName := fmt.Sprintf("reviews/%s/%s%s", authCtx.ID, uuid.NewString(), strings.ToLower(filepath.Ext(handler.Filename)))
+	h.upload(w, r, file, handler, objectName)
+}
+
+// readImage reads the "file" form field and checks its size and type. When it
+// returns false the error response has already been sent.
+func readImage(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, bool) {
 	const (
 		MinFileSize = 1024 * 10       // 10 KB
 		MaxFileSize = 1024 * 1024 * 2 // 2 MB
@@ -24,7 +62,7 @@ func (h FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 
 	if r.ContentLength > 0 && r.ContentLength > (MaxFileSize+1024) {
 		utils.SendErrorResponse(w, http.StatusBadRequest, "file size is upper maximum file size")
-		return
+		return nil, nil, false
 	}
 
 	// Limit the maximum bytes that can be read from the body.1024*10 is the overhead tolerance
@@ -33,26 +71,32 @@ func (h FileHandler) UploadFile(w http.ResponseWriter, r *http.Request) {
 	file, handler, err := r.FormFile("file")
 	if err != nil {
 		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
-		return
+		return nil, nil, false
 	}
-	defer file.Close()
 
 	if handler.Size < MinFileSize {
+		file.Close()
 		utils.SendErrorResponse(w, http.StatusBadRequest, "file size is too small (minimum 10KB required)")
-		return
+		return nil, nil, false
 	}
 
 	if handler.Size > MaxFileSize {
+		file.Close()
 		utils.SendErrorResponse(w, http.StatusBadRequest, "file size exceeds the maximum limit of 2MB")
-		return
+		return nil, nil, false
 	}
 
 	if !services.IsAllowedFileType(handler.Filename, handler.Header.Get("Content-Type")) {
+		file.Close()
 		utils.SendErrorResponse(w, http.StatusBadRequest, "file type is not allowed")
-		return
+		return nil, nil, false
 	}
 
-	uploadedFile, err := h.service.UploadImage(r.Context(), file, handler, handler.Filename)
+	return file, handler, true
+}
+
+func (h FileHandler) upload(w http.ResponseWriter, r *http.Request, file multipart.File, handler *multipart.FileHeader, objectName string) {
+	uploadedFile, err := h.service.UploadImage(r.Context(), file, handler, objectName)
 	if err != nil {
 		if appErr, ok := err.(utils.AppError); ok {
 			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type GeoHandler struct {
+	service services.GeoService
+}
+
+func NewGeoHandler(service services.GeoService) GeoHandler {
+	return GeoHandler{
+		service: service,
+	}
+}
+
+func parseBBox(raw string) (entities.BoundingBox, bool) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		 return entities.BoundingBox{}, false
+	}
+
+	vals := [4]float64{}
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			 return entities.BoundingBox{}, false
+		}
+		vals[i] = v
+	}
+
+	bbox := entities.BoundingBox{MinLon: vals[0], MinLat: vals[1], MaxLon: vals[2], MaxLat: vals[3]}
+	if bbox.MinLon < -180 || bbox.MaxLon > 180 || bbox.MinLat < -90 || bbox.MaxLat > 90 {
+		 return entities.BoundingBox{}, false
+	}
+
+	if bbox.MinLon >= bbox.MaxLon || bbox.MinLat >= bbox.MaxLat {
+		 return entities.BoundingBox{}, false
+	}
+
+	return bbox, true
+}
+
+func (h GeoHandler) GetMerchantMap(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	bbox, ok := parseBBox(q.Get("bbox"))
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "bbox must be minLon,minLat,maxLon,maxLat")
+		return
+	}
+
+	zoom, err := strconv.Atoi(q.Get("zoom"))
+	if err != nil || zoom < 0 || zoom > 22 {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "zoom must be between 0 and 22")
+		return
+	}
+
+	filter := entities.MapFilter{
+		BBox:             bbox,
+		Zoom:             zoom,
+		MerchantCategory: q.Get("merchantCategory"),
+	}
+
+	resp, err := h.service.GetMerchantMap(r.Context(), filter)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if q.Get("format") == "geojson" || strings.Contains(r.Header.Get("Accept"), "application/geo+json") {
+		utils.SendGeoJSON(w, http.StatusOK, resp.GeoJSON())
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h GeoHandler) ExportMerchantsGeoJSON(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.service.ExportMerchantsGeoJSON(r.Context(), r.URL.Query().Get("merchantCategory"))
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendGeoJSON(w, http.StatusOK, resp)
+}
+
+func (h GeoHandler) GetMerchantTile(w http.ResponseWriter, r *http.Request) {
+	z, errZ := strconv.Atoi(chi.URLParam(r, "z"))
+	x, errX := strconv.Atoi(chi.URLParam(r, "x"))
+	y, errY := strconv.Atoi(chi.URLParam(r, "y"))
+	if errZ != nil || errX != nil || errY != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "z, x or y is not valid")
+		return
+	}
+
+	tile, err := h.service.GetMerchantTile(r.Context(), z, x, y)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=60")
+	utils.SendBinary(w, http.StatusOK, "application/vnd.mapbox-vector-tile", tile)
+}
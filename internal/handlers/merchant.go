@@ -189,3 +189,98 @@ func (h MerchantHandler) CreateMercItem(w http.ResponseWriter, r *http.Request)
 
 	utils.SendResponse(w, http.StatusCreated, merchantItemId)
 }
+
+func (h MerchantHandler) SetOpeningHours(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.SetOpeningHoursRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchantId := chi.URLParam(r, "merchantId")
+
+	hours := make([]entities.OpeningHour, 0, len(req.OpeningHours))
+	for _, oh := range req.OpeningHours {
+		hours = append(hours, entities.OpeningHour{
+			MerchantID: merchantId,
+			DayOfWeek:  oh.DayOfWeek,
+			OpensAt:    oh.OpensAt,
+			ClosesAt:   oh.ClosesAt,
+		})
+	}
+
+	if err := h.service.SetOpeningHours(ctx, merchantId, hours); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, req)
+}
+
+func (h MerchantHandler) SetOwner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.SetMerchantOwnerRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchantId := chi.URLParam(r, "merchantId")
+
+	if err := h.service.SetOwner(ctx, merchantId, req.UserID); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, req)
+}
+
+func (h MerchantHandler) SetItemAvailability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.SetItemAvailabilityRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchantId := chi.URLParam(r, "merchantId")
+	itemId := chi.URLParam(r, "itemId")
+
+	if err := h.service.SetItemAvailability(ctx, merchantId, itemId, *req.IsAvailable); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, req)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type MerchantOrderHandler struct {
+	service    services.MerchantOrderService
+	validation *validator.Validate
+}
+
+func NewMerchantOrderHandler(service services.MerchantOrderService, validation *validator.Validate) MerchantOrderHandler {
+	return MerchantOrderHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h MerchantOrderHandler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit := 20
+	if limStr := q.Get("limit"); limStr != "" {
+		if limVal, err := strconv.Atoi(limStr); err == nil && limVal > 0 {
+			 limit = min(limVal, 100)
+		}
+	}
+
+	offset := 0
+	if offStr := q.Get("offset"); offStr != "" {
+		if offVal, err := strconv.Atoi(offStr); err == nil && offVal >= 0 {
+			 offset = offVal
+		}
+	}
+
+	filter := entities.MerchantOrderFilter{
+		MerchantID: chi.URLParam(r, "merchantId"),
+		Status:     q.Get("status"),
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	resp, err := h.service.GetQueue(ctx, authCtx.ID, filter)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h MerchantOrderHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.AcceptMerchantOrderRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchantId := chi.URLParam(r, "merchantId")
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.Accept(ctx, authCtx.ID, merchantId, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h MerchantOrderHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.RejectMerchantOrderRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	merchantId := chi.URLParam(r, "merchantId")
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.Reject(ctx, authCtx.ID, merchantId, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h MerchantOrderHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	merchantId := chi.URLParam(r, "merchantId")
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.Ready(ctx, authCtx.ID, merchantId, orderId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
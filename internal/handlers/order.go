@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/events"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+)
+
+// sseHeartbeat keeps idle event streams from being closed by proxies.
+const sseHeartbeat = 15 * time.Second
+
+type OrderHandler struct {
+	service    services.OrderService
+	validation *validator.Validate
+}
+
+func NewOrderHandler(service services.OrderService, validation *validator.Validate) OrderHandler {
+	return OrderHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.UpdateOrderStatusRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.UpdateStatus(ctx, orderId, authCtx.ID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h OrderHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.GetStatus(ctx, authCtx.ID, orderId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h OrderHandler) CancelByUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.CancelOrderRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	// the body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.CancelByUser(ctx, authCtx.ID, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h OrderHandler) CancelByOperator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.OperatorCancelOrderRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.CancelByOperator(ctx, authCtx.ID, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+// Events streams the order's status changes and courier positions. It speaks
+// Server-Sent Events unless the client asks for a WebSocket upgrade. Both start
+// with a snapshot of the current status.
+func (h OrderHandler) Events(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	snapshot, ch, unsubscribe, err := h.service.Watch(ctx, authCtx.ID, orderId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer unsubscribe()
+
+	if events.IsWebSocketRequest(r) {
+		h.streamWebSocket(w, r, snapshot, ch)
+		return
+	}
+
+	h.streamSSE(w, r, snapshot, ch)
+}
+
+func (h OrderHandler) streamSSE(w http.ResponseWriter, r *http.Request, snapshot dto.OrderStatusResponse, ch <-chan events.Event) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := events.WriteSSE(w, "snapshot", snapshot); err != nil {
+		 return
+	}
+	if err := rc.Flush(); err != nil {
+		 return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := w.Write([]byte(": ping\n\n")); err != nil {
+				 return
+			}
+		case e, ok := <-ch:
+			if !ok {
+				 return
+			}
+			if err := events.WriteSSE(w, e.Type, e); err != nil {
+				 return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			 return
+		}
+	}
+}
+
+func (h OrderHandler) streamWebSocket(w http.ResponseWriter, r *http.Request, snapshot dto.OrderStatusResponse, ch <-chan events.Event) {
+	conn, err := events.UpgradeWebSocket(w, r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer conn.Close()
+
+	// the hijacked connection is no longer tied to the request context
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		if err := conn.ReadLoop(); err != nil {
+			 log.Debug().Err(err).Msg("order events websocket closed")
+		}
+	}()
+
+	if err := conn.WriteJSON(map[string]any{"type": "snapshot", "data": snapshot}); err != nil {
+		 return
+	}
+
+	for {
+		select {
+		case <-closed:
+			return
+		case e, ok := <-ch:
+			if !ok {
+				 return
+			}
+			if err := conn.WriteJSON(e); err != nil {
+				 return
+			}
+		}
+	}
+}
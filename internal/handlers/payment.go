@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxWebhookBody caps how much of a webhook delivery is read.
+const maxWebhookBody = 1 << 20
+
+type PaymentHandler struct {
+	service services.PaymentService
+}
+
+func NewPaymentHandler(service services.PaymentService) PaymentHandler {
+	return PaymentHandler{service: service}
+}
+
+func (h PaymentHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusRequestEntityTooLarge, "webhook body too large")
+		return
+	}
+
+	provider := chi.URLParam(r, "provider")
+
+	if err := h.service.HandleWebhook(ctx, provider, r.Header, body); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, map[string]bool{"received": true})
+}
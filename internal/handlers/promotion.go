@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type PromotionHandler struct {
+	service    services.PromotionService
+	validation *validator.Validate
+}
+
+func NewPromotionHandler(service services.PromotionService, validation *validator.Validate) PromotionHandler {
+	return PromotionHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h PromotionHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.CreatePromotionRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promo := entities.Promotion{
+		Code:               strings.ToUpper(req.Code),
+		Description:        req.Description,
+		Type:               req.Type,
+		Value:              req.Value,
+		MaxDiscount:        req.MaxDiscount,
+		MinBasket:          req.MinBasket,
+		GlobalLimit:        req.GlobalLimit,
+		PerUserLimit:       req.PerUserLimit,
+		MerchantIDs:        req.MerchantIDs,
+		MerchantCategories: req.MerchantCategories,
+		ProductCategories:  req.ProductCategories,
+		StartsAt:           req.StartsAt.UTC(),
+		EndsAt:             req.EndsAt.UTC(),
+	}
+
+	if promo.MerchantIDs == nil {
+		promo.MerchantIDs = []string{}
+	}
+	if promo.MerchantCategories == nil {
+		promo.MerchantCategories = []string{}
+	}
+	if promo.ProductCategories == nil {
+		promo.ProductCategories = []string{}
+	}
+
+	resp, err := h.service.CreatePromotion(ctx, promo)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, resp)
+}
+
+func (h PromotionHandler) GetAllPromotion(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	limit := 5
+	if limStr := q.Get("limit"); limStr != "" {
+		if limVal, err := strconv.Atoi(limStr); err == nil && limVal > 0 {
+			 limit = limVal
+		}
+	}
+
+	offset := 0
+	if offStr := q.Get("offset"); offStr != "" {
+		if offVal, err := strconv.Atoi(offStr); err == nil && offVal > 0 {
+			 offset = offVal
+		}
+	}
+
+	filter := entities.PromotionFilter{
+		Limit:  limit,
+		Offset: offset,
+		Code:   q.Get("code"),
+	}
+
+	if activeStr := q.Get("isActive"); activeStr != "" {
+		if active, err := strconv.ParseBool(activeStr); err == nil {
+			 filter.IsActive = &active
+		}
+	}
+
+	resp, err := h.service.GetAllPromotion(r.Context(), filter)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h PromotionHandler) SetPromotionActive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.SetPromotionActiveRequest{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	promotionId := chi.URLParam(r, "promotionId")
+
+	if err := h.service.SetPromotionActive(ctx, promotionId, *req.IsActive); err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, req)
+}
@@ -60,11 +60,54 @@ func (h PurchaseHandler) GetNearbyMerchants(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	minPrice := 0
+	if minStr := q.Get("minPrice"); minStr != "" {
+		if minVal, err := strconv.Atoi(minStr); err == nil && minVal > 0 {
+			minPrice = minVal
+		}
+	}
+
+	maxPrice := 0
+	if maxStr := q.Get("maxPrice"); maxStr != "" {
+		if maxVal, err := strconv.Atoi(maxStr); err == nil && maxVal > 0 {
+			maxPrice = maxVal
+		}
+	}
+
+	if minPrice > 0 && maxPrice > 0 && minPrice > maxPrice {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "minPrice must not be greater than maxPrice")
+		return
+	}
+
+	maxDistance := 0.0
+	if distStr := q.Get("maxDistance"); distStr != "" {
+		if distVal, err := strconv.ParseFloat(distStr, 64); err == nil && distVal > 0 {
+			maxDistance = distVal
+		}
+	}
+
+	sortBy := q.Get("sortBy")
+	switch sortBy {
+	case "distance", "rating", "popularity", "newest", "eta":
+	default:
+		sortBy = "distance"
+	}
+
+	openNow, _ := strconv.ParseBool(q.Get("openNow"))
+	favoritesOnly, _ := strconv.ParseBool(q.Get("favoritesOnly"))
+
 	filter := entities.MerchantNearbyFilter{
 		Limit:            limit,
 		Name:             q.Get("name"),
 		MerchantID:       q.Get("merchantId"),
 		MerchantCategory: q.Get("merchantCategory"),
+		ProductCategory:  q.Get("productCategory"),
+		MinPrice:         minPrice,
+		MaxPrice:         maxPrice,
+		MaxDistance:      maxDistance,
+		OpenNow:          openNow,
+		FavoritesOnly:    favoritesOnly,
+		SortBy:           sortBy,
 		Offset:           offset,
 		UserID:           authCtx.ID,
 		Lat:              lat,
@@ -94,13 +137,13 @@ func (h PurchaseHandler) CreateEstimate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	req.UserID = authCtx.ID
-
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	req.UserID = authCtx.ID
+
 	if err := h.validation.Struct(req); err != nil {
 		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -124,15 +167,47 @@ func (h PurchaseHandler) CreateEstimate(w http.ResponseWriter, r *http.Request)
 	utils.SendResponse(w, http.StatusOK, response)
 }
 
+func (h PurchaseHandler) GetEstimate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	estimateId := chi.URLParam(r, "estimateId")
+
+	response, err := h.service.GetEstimate(ctx, authCtx.ID, estimateId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, response)
+}
+
 func (h PurchaseHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req := dto.CreateOrderRequest{}
 
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	req.UserID = authCtx.ID
+
 	if err := h.validation.Struct(req); err != nil {
 		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -176,12 +251,14 @@ func (h PurchaseHandler) GetAllOrder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	filter := entities.OrderFilter{
-		Limit:            limit,
-		Name:             q.Get("name"),
-		MerchantID:       q.Get("merchantId"),
-		MerchantCategory: q.Get("merchantCategory"),
-		UserID:           authCtx.ID,
-		Offset:           offset,
+		Limit:             limit,
+		Name:              q.Get("name"),
+		MerchantID:        q.Get("merchantId"),
+		MerchantCategory:  q.Get("merchantCategory"),
+		Status:            q.Get("status"),
+		UpcomingScheduled: q.Get("scheduled") == "upcoming",
+		UserID:            authCtx.ID,
+		Offset:            offset,
 	}
 
 	response, err := h.service.GetAllOrder(ctx, filter)
@@ -196,3 +273,38 @@ func (h PurchaseHandler) GetAllOrder(w http.ResponseWriter, r *http.Request) {
 
 	utils.SendResponse(w, http.StatusOK, response)
 }
+
+func (h PurchaseHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.ReorderRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	response, err := h.service.Reorder(ctx, authCtx.ID, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, response)
+}
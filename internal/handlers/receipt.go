@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ReceiptHandler struct {
+	service services.ReceiptService
+}
+
+func NewReceiptHandler(service services.ReceiptService) ReceiptHandler {
+	return ReceiptHandler{
+		service: service,
+	}
+}
+
+// GetReceipt answers with the PDF receipt, or with the e-invoice JSON when
+// format=json is asked for.
+func (h ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	switch r.URL.Query().Get("format") {
+	case "", "pdf":
+		pdf, err := h.service.PDF(ctx, authCtx.ID, orderId)
+		if err != nil {
+			if appErr, ok := err.(utils.AppError); ok {
+				utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+			} else {
+				utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="receipt-%s.pdf"`, orderId))
+		utils.SendBinary(w, http.StatusOK, "application/pdf", pdf)
+	case "json":
+		invoice, err := h.service.EInvoice(ctx, authCtx.ID, orderId)
+		if err != nil {
+			if appErr, ok := err.(utils.AppError); ok {
+				utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+			} else {
+				utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		utils.SendResponse(w, http.StatusOK, invoice)
+	default:
+		utils.SendErrorResponse(w, http.StatusBadRequest, "format must be pdf or json")
+	}
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type ReviewHandler struct {
+	service    services.ReviewService
+	validation *validator.Validate
+}
+
+func NewReviewHandler(service services.ReviewService, validation *validator.Validate) ReviewHandler {
+	return ReviewHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+// reviewPage reads the limit and offset query parameters of the review listings.
+func reviewPage(q url.Values) (int, int) {
+	limit := 5
+	if limStr := q.Get("limit"); limStr != "" {
+		if limVal, err := strconv.Atoi(limStr); err == nil && limVal > 0 {
+			 limit = limVal
+		}
+	}
+
+	offset := 0
+	if offStr := q.Get("offset"); offStr != "" {
+		if offVal, err := strconv.Atoi(offStr); err == nil && offVal > 0 {
+			 offset = offVal
+		}
+	}
+
+	return limit, offset
+}
+
+func (h ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.CreateReviewRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.CreateReview(ctx, authCtx.ID, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, resp)
+}
+
+func (h ReviewHandler) CreateCourierReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.CreateCourierReviewRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.CreateCourierReview(ctx, authCtx.ID, orderId, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusCreated, resp)
+}
+
+func (h ReviewHandler) GetMerchantReviews(w http.ResponseWriter, r *http.Request) {
+	limit, offset := reviewPage(r.URL.Query())
+	merchantId := chi.URLParam(r, "merchantId")
+
+	resp, err := h.service.GetMerchantReviews(r.Context(), merchantId, limit, offset)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h ReviewHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset := reviewPage(q)
+
+	resp, err := h.service.GetReviews(r.Context(), entities.ReviewFilter{
+		MerchantID: q.Get("merchantId"),
+		Status:     q.Get("status"),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h ReviewHandler) GetCourierReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset := reviewPage(q)
+
+	resp, err := h.service.GetCourierReviews(r.Context(), entities.ReviewFilter{
+		CourierID: q.Get("courierId"),
+		Status:    q.Get("status"),
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.ModerateReviewRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.ModerateReview(ctx, entities.ReviewModeration{
+		ReviewID:    chi.URLParam(r, "reviewId"),
+		Status:      req.Status,
+		Reason:      req.Reason,
+		ModeratedBy: authCtx.ID,
+	})
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h ReviewHandler) ModerateCourierReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.ModerateReviewRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.ModerateCourierReview(ctx, entities.ReviewModeration{
+		ReviewID:    chi.URLParam(r, "reviewId"),
+		Status:      req.Status,
+		Reason:      req.Reason,
+		ModeratedBy: authCtx.ID,
+	})
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
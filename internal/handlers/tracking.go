@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/middleware"
+	"belimang/internal/services"
+	"belimang/internal/utils"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type TrackingHandler struct {
+	service    services.TrackingService
+	validation *validator.Validate
+}
+
+func NewTrackingHandler(service services.TrackingService, validation *validator.Validate) TrackingHandler {
+	return TrackingHandler{
+		service:    service,
+		validation: validation,
+	}
+}
+
+func (h TrackingHandler) RecordLocations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := dto.LocationBatchRequest{}
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.validation.Struct(req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.RecordLocations(ctx, authCtx.ID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusAccepted, resp)
+}
+
+func (h TrackingHandler) GetTrip(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	authCtx, ok := middleware.GetAuthContext(ctx)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	orderId := chi.URLParam(r, "orderId")
+
+	resp, err := h.service.GetTrip(ctx, authCtx.ID, orderId)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
+
+func (h TrackingHandler) GetETAAccuracy(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := entities.ETAAccuracyFilter{}
+
+	if fromStr := q.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "from must be an RFC 3339 timestamp")
+			return
+		}
+		filter.From = &from
+	}
+
+	if toStr := q.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "to must be an RFC 3339 timestamp")
+			return
+		}
+		filter.To = &to
+	}
+
+	resp, err := h.service.GetETAAccuracy(r.Context(), filter)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok {
+			utils.SendErrorResponse(w, appErr.StatusCode, appErr.Message)
+		} else {
+			utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.SendResponse(w, http.StatusOK, resp)
+}
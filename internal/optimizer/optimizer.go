@@ -0,0 +1,233 @@
+package optimizer
+
+import (
+	"math"
+	"time"
+)
+
+// Stops up to this many intermediate points are solved exactly with Held-Karp,
+// larger problems fall back to local search within the time budget.
+const MaxExactStops = 12
+
+// Result is an open path that starts at the requested start point and ends at the
+// last point of the matrix, visiting every other point exactly once.
+type Result struct {
+	Order []int
+	Legs  []float64
+	Total float64
+	Exact bool
+}
+
+// Solve orders the points of dist so the path from start to the last point is as
+// short as possible. dist[i][j] is the cost of going from i to j and may be asymmetric.
+func Solve(start int, dist [][]float64, budget time.Duration) Result {
+	n := len(dist)
+	switch {
+	case n == 0:
+		return Result{Exact: true}
+	case n == 1 || start == n-1:
+		return newResult([]int{n - 1}, dist, true)
+	}
+
+	stops := make([]int, 0, n-2)
+	for i := 0; i < n-1; i++ {
+		if i != start {
+			stops = append(stops, i)
+		}
+	}
+
+	if len(stops) <= MaxExactStops {
+		return newResult(heldKarp(start, n-1, stops, dist), dist, true)
+	}
+
+	deadline := time.Now().Add(budget)
+	order := nearestNeighbor(start, n-1, stops, dist)
+	improve(order, dist, deadline)
+
+	return newResult(order, dist, false)
+}
+
+func newResult(order []int, dist [][]float64, exact bool) Result {
+	legs := make([]float64, 0, len(order))
+	total := 0.0
+	for i := 1; i < len(order); i++ {
+		d := dist[order[i-1]][order[i]]
+		legs = append(legs, d)
+		total += d
+	}
+
+	return Result{Order: order, Legs: legs, Total: total, Exact: exact}
+}
+
+// heldKarp solves the fixed start, fixed end path over stops with the classic
+// O(2^k * k^2) dynamic program over subsets of visited stops.
+func heldKarp(start, end int, stops []int, dist [][]float64) []int {
+	k := len(stops)
+	if k == 0 {
+		 return []int{start, end}
+	}
+
+	full := 1 << k
+	cost := make([]float64, full*k)
+	parent := make([]int, full*k)
+	for i := range cost {
+		cost[i] = math.Inf(1)
+		parent[i] = -1
+	}
+
+	for j := 0; j < k; j++ {
+		cost[(1<<j)*k+j] = dist[start][stops[j]]
+	}
+
+	for mask := 1; mask < full; mask++ {
+		for last := 0; last < k; last++ {
+			if mask&(1<<last) == 0 {
+				continue
+			}
+
+			cur := cost[mask*k+last]
+			if math.IsInf(cur, 1) {
+				continue
+			}
+
+			for next := 0; next < k; next++ {
+				if mask&(1<<next) != 0 {
+					continue
+				}
+
+				nmask := mask | 1<<next
+				if c := cur + dist[stops[last]][stops[next]]; c < cost[nmask*k+next] {
+					cost[nmask*k+next] = c
+					parent[nmask*k+next] = last
+				}
+			}
+		}
+	}
+
+	best, bestLast := math.Inf(1), 0
+	for last := 0; last < k; last++ {
+		if c := cost[(full-1)*k+last] + dist[stops[last]][end]; c < best {
+			best, bestLast = c, last
+		}
+	}
+
+	order := make([]int, k+2)
+	order[0], order[k+1] = start, end
+	mask, last := full-1, bestLast
+	for pos := k; pos >= 1; pos-- {
+		order[pos] = stops[last]
+		prev := parent[mask*k+last]
+		mask &^= 1 << last
+		last = prev
+	}
+
+	return order
+}
+
+func nearestNeighbor(start, end int, stops []int, dist [][]float64) []int {
+	order := make([]int, 0, len(stops)+2)
+	order = append(order, start)
+
+	visited := make(map[int]bool, len(stops))
+	current := start
+	for range stops {
+		next, best := -1, math.Inf(1)
+		for _, s := range stops {
+			if !visited[s] && dist[current][s] < best {
+				next, best = s, dist[current][s]
+			}
+		}
+
+		visited[next] = true
+		order = append(order, next)
+		current = next
+	}
+
+	return append(order, end)
+}
+
+// improve applies 2-opt and Or-opt moves until no move shortens the path or the
+// deadline passes. The first and last points of order never move.
+func improve(order []int, dist [][]float64, deadline time.Time) {
+	for time.Now().Before(deadline) {
+		if twoOpt(order, dist) {
+			continue
+		}
+
+		if !orOpt(order, dist) {
+			return
+		}
+	}
+}
+
+// twoOpt reverses the first segment whose reversal shortens the path. Prefix sums in
+// both directions keep the check O(1) even when the matrix is asymmetric.
+func twoOpt(order []int, dist [][]float64) bool {
+	n := len(order)
+	fwd := make([]float64, n)
+	bwd := make([]float64, n)
+	for i := 1; i < n; i++ {
+		fwd[i] = fwd[i-1] + dist[order[i-1]][order[i]]
+		bwd[i] = bwd[i-1] + dist[order[i]][order[i-1]]
+	}
+
+	for i := 1; i < n-2; i++ {
+		for j := i + 1; j < n-1; j++ {
+			a, b, c, e := order[i-1], order[i], order[j], order[j+1]
+			before := dist[a][b] + (fwd[j] - fwd[i]) + dist[c][e]
+			after := dist[a][c] + (bwd[j] - bwd[i]) + dist[b][e]
+
+			if after < before-1e-9 {
+				for l, r := i, j; l < r; l, r = l+1, r-1 {
+					order[l], order[r] = order[r], order[l]
+				}
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// orOpt moves the first chain of one to three consecutive stops whose relocation
+// elsewhere in the path shortens it, keeping the chain's direction.
+func orOpt(order []int, dist [][]float64) bool {
+	n := len(order)
+	for size := 1; size <= 3; size++ {
+		for i := 1; i+size < n; i++ {
+			first, last := order[i], order[i+size-1]
+			prev, next := order[i-1], order[i+size]
+			removed := dist[prev][first] + dist[last][next] - dist[prev][next]
+
+			for p := 0; p < n-1; p++ {
+				if p >= i-1 && p < i+size {
+					continue
+				}
+
+				x, y := order[p], order[p+1]
+				added := dist[x][first] + dist[last][y] - dist[x][y]
+				if added < removed-1e-9 {
+					moveChain(order, i, size, p)
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// moveChain relocates order[i:i+size] so it sits between the points that were at
+// positions p and p+1 before the move.
+func moveChain(order []int, i, size, p int) {
+	chain := append([]int(nil), order[i:i+size]...)
+	rest := append(append([]int(nil), order[:i]...), order[i+size:]...)
+
+	at := p + 1
+	if p >= i+size {
+		at = p + 1 - size
+	}
+
+	out := append(append(append(make([]int, 0, len(order)), rest[:at]...), chain...), rest[at:]...)
+	copy(order, out)
+}
@@ -0,0 +1,156 @@
+package optimizer
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func randomMatrix(r *rand.Rand, n int) [][]float64 {
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			if i != j {
+				dist[i][j] = 1 + r.Float64()*99
+			}
+		}
+	}
+
+	return dist
+}
+
+// bruteForce tries every order of the stops between start and the last point.
+func bruteForce(start int, dist [][]float64) float64 {
+	n := len(dist)
+	stops := make([]int, 0, n-2)
+	for i := 0; i < n-1; i++ {
+		if i != start {
+			stops = append(stops, i)
+		}
+	}
+
+	best := math.Inf(1)
+	var permute func(k int)
+	permute = func(k int) {
+		if k == len(stops) {
+			order := append(append([]int{start}, stops...), n-1)
+			best = math.Min(best, newResult(order, dist, true).Total)
+			return
+		}
+
+		for i := k; i < len(stops); i++ {
+			stops[k], stops[i] = stops[i], stops[k]
+			permute(k + 1)
+			stops[k], stops[i] = stops[i], stops[k]
+		}
+	}
+	permute(0)
+
+	return best
+}
+
+// checkPath fails unless order starts at start, ends at the last point and
+// visits every point exactly once.
+func checkPath(t *testing.T, start int, order []int, n int) {
+	t.Helper()
+
+	if len(order) != n || order[0] != start || order[n-1] != n-1 {
+		 t.Fatalf("order = %v, want a path from %d to %d over %d points", order, start, n-1, n)
+	}
+
+	seen := make(map[int]bool, n)
+	for _, p := range order {
+		if seen[p] || p < 0 || p >= n {
+			 t.Fatalf("order = %v visits %d twice or out of range", order, p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestSolveExactMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for n := 2; n <= 8; n++ {
+		for round := 0; round < 20; round++ {
+			dist := randomMatrix(r, n)
+			start := r.IntN(n - 1)
+
+			got := Solve(start, dist, time.Second)
+			if !got.Exact {
+				 t.Fatalf("n=%d: Exact = false, want true", n)
+			}
+			checkPath(t, start, got.Order, n)
+
+			if want := bruteForce(start, dist); math.Abs(got.Total-want) > 1e-9 {
+				 t.Fatalf("n=%d start=%d: Total = %v, want %v (order %v)", n, start, got.Total, want, got.Order)
+			}
+		}
+	}
+}
+
+func TestSolveTrivial(t *testing.T) {
+	if got := Solve(0, nil, time.Second); !got.Exact || len(got.Order) != 0 {
+		 t.Errorf("empty matrix: %+v", got)
+	}
+
+	dist := [][]float64{{0, 5}, {7, 0}}
+	if got := Solve(1, dist, time.Second); len(got.Order) != 1 || got.Order[0] != 1 || got.Total != 0 {
+		 t.Errorf("start at the end: %+v", got)
+	}
+}
+
+func TestSolveFallsBackToLocalSearch(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	if got := Solve(0, randomMatrix(r, MaxExactStops+2), 0); !got.Exact {
+		 t.Fatalf("Exact = false for %d stops, want the exact solver", MaxExactStops)
+	}
+
+	n := MaxExactStops + 3
+	dist := randomMatrix(r, n)
+
+	stops := make([]int, 0, n-2)
+	for i := 1; i < n-1; i++ {
+		stops = append(stops, i)
+	}
+	greedy := newResult(nearestNeighbor(0, n-1, stops, dist), dist, false)
+
+	// without any budget the nearest neighbor path is returned as is
+	began := time.Now()
+	got := Solve(0, dist, 0)
+	if elapsed := time.Since(began); elapsed > 100*time.Millisecond {
+		 t.Errorf("Solve with no budget took %v", elapsed)
+	}
+	if got.Exact {
+		 t.Fatalf("Exact = true for %d stops, want local search", n-2)
+	}
+	checkPath(t, 0, got.Order, n)
+	if got.Total != greedy.Total {
+		 t.Errorf("Total = %v, want the nearest neighbor path %v", got.Total, greedy.Total)
+	}
+
+	got = Solve(0, dist, time.Second)
+	if got.Exact {
+		 t.Fatalf("Exact = true for %d stops, want local search", n-2)
+	}
+	checkPath(t, 0, got.Order, n)
+	if got.Total > greedy.Total {
+		 t.Errorf("Total = %v, want no worse than the nearest neighbor path %v", got.Total, greedy.Total)
+	}
+}
+
+func TestImproveNeverLengthensPath(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	for round := 0; round < 50; round++ {
+		dist := randomMatrix(r, 5)
+
+		order := nearestNeighbor(0, 4, []int{1, 2, 3}, dist)
+		improve(order, dist, time.Now().Add(time.Second))
+		checkPath(t, 0, order, 5)
+
+		greedy := newResult(nearestNeighbor(0, 4, []int{1, 2, 3}, dist), dist, false)
+		if got := newResult(order, dist, false); got.Total > greedy.Total+1e-9 {
+			 t.Fatalf("improve made the path longer: %v > %v", got.Total, greedy.Total)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package payments
+
+import (
+	"context"
+	"net/http"
+)
+
+// CODProvider is cash on delivery. The payment stays pending until the order is
+// delivered and the courier has collected the cash.
+type CODProvider struct{}
+
+func NewCODProvider() CODProvider {
+	return CODProvider{}
+}
+
+func (p CODProvider) Name() string {
+	return MethodCOD
+}
+
+func (p CODProvider) CreateIntent(_ context.Context, req IntentRequest) (Intent, error) {
+	return Intent{Reference: "cod_" + req.PaymentID, Status: StatusPending}, nil
+}
+
+// Refund has nothing to send back electronically; the cash is returned by hand.
+func (p CODProvider) Refund(_ context.Context, req RefundRequest) (string, error) {
+	return "cod_re_" + req.RefundID, nil
+}
+
+func (p CODProvider) ParseWebhook(http.Header, []byte) (WebhookEvent, error) {
+	return WebhookEvent{}, ErrNoWebhooks
+}
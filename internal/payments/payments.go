@@ -0,0 +1,86 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	MethodCOD    = "cod"
+	MethodFake   = "fake"
+	MethodWallet = "wallet"
+)
+
+const (
+	StatusPending        = "Pending"
+	StatusRequiresAction = "RequiresAction"
+	StatusSucceeded      = "Succeeded"
+	StatusFailed         = "Failed"
+	StatusCancelled      = "Cancelled"
+	StatusRefunded       = "Refunded"
+)
+
+var (
+	ErrInvalidSignature = errors.New("payments: invalid webhook signature")
+	ErrNoWebhooks       = errors.New("payments: provider does not send webhooks")
+)
+
+// IntentRequest asks a provider to collect Amount for an order. PaymentID is our
+// own id and doubles as the idempotency key on the provider side.
+type IntentRequest struct {
+	PaymentID string
+	OrderID   string
+	UserID    string
+	Amount    int
+}
+
+// Intent is the provider's view of a payment right after it was created.
+// ClientSecret, when set, is handed to the client to finish the payment.
+type Intent struct {
+	Reference    string
+	Status       string
+	ClientSecret string
+}
+
+// RefundRequest returns Amount of the succeeded payment Reference to UserID.
+// RefundID is our own id and doubles as the idempotency key.
+type RefundRequest struct {
+	RefundID  string
+	Reference string
+	UserID    string
+	Amount    int
+}
+
+// WebhookEvent is a verified notification about one payment.
+type WebhookEvent struct {
+	ID        string
+	Reference string
+	Status    string
+	Reason    string
+}
+
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (Intent, error)
+	// Refund sends money back and returns the provider's reference for it.
+	Refund(ctx context.Context, req RefundRequest) (string, error)
+	// ParseWebhook verifies the signature of a webhook delivery and decodes it.
+	ParseWebhook(header http.Header, body []byte) (WebhookEvent, error)
+}
+
+// Registry holds the providers customers can pay with, by name.
+type Registry map[string]PaymentProvider
+
+func NewRegistry(providers ...PaymentProvider) Registry {
+	r := make(Registry, len(providers))
+	for _, p := range providers {
+		r[p.Name()] = p
+	}
+	return r
+}
+
+func (r Registry) Get(name string) (PaymentProvider, bool) {
+	p, ok := r[name]
+	return p, ok
+}
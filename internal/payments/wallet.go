@@ -0,0 +1,45 @@
+package payments
+
+import (
+	"context"
+	"net/http"
+)
+
+// Wallet moves money in and out of a customer's stored balance. reference makes
+// each movement idempotent.
+type Wallet interface {
+	Debit(ctx context.Context, userID string, amount int, reference string) (string, error)
+	Refund(ctx context.Context, userID string, amount int, reference string) (string, error)
+}
+
+// WalletProvider pays from the customer's wallet balance. Payments settle
+// immediately, so no webhook is involved.
+type WalletProvider struct {
+	wallet Wallet
+}
+
+func NewWalletProvider(wallet Wallet) WalletProvider {
+	return WalletProvider{wallet: wallet}
+}
+
+func (p WalletProvider) Name() string {
+	return MethodWallet
+}
+
+func (p WalletProvider) CreateIntent(ctx context.Context, req IntentRequest) (Intent, error) {
+	ref, err := p.wallet.Debit(ctx, req.UserID, req.Amount, "order:"+req.OrderID)
+	if err != nil {
+		 return Intent{}, err
+	}
+
+	return Intent{Reference: ref, Status: StatusSucceeded}, nil
+}
+
+// Refund credits the wallet of the user who paid.
+func (p WalletProvider) Refund(ctx context.Context, req RefundRequest) (string, error) {
+	return p.wallet.Refund(ctx, req.UserID, req.Amount, "refund:"+req.RefundID)
+}
+
+func (p WalletProvider) ParseWebhook(http.Header, []byte) (WebhookEvent, error) {
+	return WebhookEvent{}, ErrNoWebhooks
+}
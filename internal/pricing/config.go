@@ -0,0 +1,111 @@
+package pricing
+
+import (
+	"belimang/internal/utils"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CityConfig holds the fee and tax rules of one city. Amounts are in rupiah.
+type CityConfig struct {
+	Name     string  `json:"name"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"long"`
+	RadiusKm float64 `json:"radiusKm"`
+
+	DeliveryBaseFee   int     `json:"deliveryBaseFee"`
+	DeliveryIncludeKm float64 `json:"deliveryIncludedKm"`
+	DeliveryPerKmFee  int     `json:"deliveryPerKmFee"`
+	PerStopSurcharge  int     `json:"perStopSurcharge"`
+
+	ServiceFeeFlat    int     `json:"serviceFeeFlat"`
+	ServiceFeePercent float64 `json:"serviceFeePercent"`
+
+	TaxName    string  `json:"taxName"`
+	TaxPercent float64 `json:"taxPercent"`
+	TaxOnItems bool    `json:"taxOnItems"`
+	TaxOnFees  bool    `json:"taxOnFees"`
+
+	RoundingUnit int    `json:"roundingUnit"`
+	RoundingMode string `json:"roundingMode"`
+}
+
+type Config struct {
+	Default CityConfig   `json:"default"`
+	Cities  []CityConfig `json:"cities"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Default: CityConfig{
+			Name:              "default",
+			DeliveryBaseFee:   8000,
+			DeliveryIncludeKm: 2,
+			DeliveryPerKmFee:  2500,
+			PerStopSurcharge:  3000,
+			ServiceFeeFlat:    2000,
+			TaxName:           "PPN",
+			TaxPercent:        11,
+			TaxOnItems:        true,
+			TaxOnFees:         true,
+			RoundingUnit:      100,
+			RoundingMode:      "nearest",
+		},
+	}
+}
+
+// LoadConfig reads the pricing rules from a JSON file, or returns the built-in
+// defaults when path is empty.
+func LoadConfig(path string) (Config, error) {
+	if path == "" {
+		 return DefaultConfig(), nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		 return Config{}, fmt.Errorf("failed to read pricing config: %w", err)
+	}
+
+	cfg := Config{}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		 return Config{}, fmt.Errorf("failed to parse pricing config: %w", err)
+	}
+
+	for _, c := range append([]CityConfig{cfg.Default}, cfg.Cities...) {
+		if err := c.validate(); err != nil {
+			 return Config{}, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c CityConfig) validate() error {
+	switch c.RoundingMode {
+	case "", "nearest", "up", "down":
+	default:
+		return fmt.Errorf("pricing config %q: unknown rounding mode %q", c.Name, c.RoundingMode)
+	}
+
+	if c.DeliveryBaseFee < 0 || c.DeliveryPerKmFee < 0 || c.PerStopSurcharge < 0 || c.ServiceFeeFlat < 0 {
+		 return fmt.Errorf("pricing config %q: fees must not be negative", c.Name)
+	}
+
+	if c.ServiceFeePercent < 0 || c.TaxPercent < 0 || c.RoundingUnit < 0 {
+		 return fmt.Errorf("pricing config %q: rates must not be negative", c.Name)
+	}
+
+	return nil
+}
+
+// CityFor returns the first city whose radius contains p, or the default rules.
+func (c Config) CityFor(p utils.Point) CityConfig {
+	for _, city := range c.Cities {
+		if utils.Haversine(p, utils.Point{Lat: city.Lat, Lon: city.Lon}) <= city.RadiusKm {
+			return city
+		}
+	}
+
+	return c.Default
+}
@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"math"
+)
+
+type Engine struct {
+	cfg Config
+}
+
+func NewEngine(cfg Config) Engine {
+	return Engine{cfg: cfg}
+}
+
+// Quote is everything the engine needs to price one estimate.
+type Quote struct {
+	Destination utils.Point
+	Merchants   []entities.MerchantSubtotal
+	DistanceKm  float64
+	Stops       int
+}
+
+// Price builds the itemized breakdown of a quote. Fees and tax are rounded to the
+// rupiah individually and the grand total is rounded with the city rounding rule,
+// the difference being reported as the rounding line.
+func (e Engine) Price(q Quote) entities.PriceBreakdown {
+	city := e.cfg.CityFor(q.Destination)
+
+	b := entities.PriceBreakdown{
+		City:      city.Name,
+		Merchants: q.Merchants,
+		TaxName:   city.TaxName,
+		TaxRate:   city.TaxPercent,
+	}
+
+	for _, m := range q.Merchants {
+		b.ItemsSubtotal += m.Subtotal
+	}
+
+	extraKm := math.Max(0, q.DistanceKm-city.DeliveryIncludeKm)
+	b.DeliveryFee = city.DeliveryBaseFee + int(math.Ceil(extraKm*float64(city.DeliveryPerKmFee)))
+	if q.Stops > 1 {
+		b.StopSurcharge = (q.Stops - 1) * city.PerStopSurcharge
+	}
+
+	b.ServiceFee = city.ServiceFeeFlat + int(math.Round(float64(b.ItemsSubtotal)*city.ServiceFeePercent/100))
+
+	finalize(&b, city)
+	return b
+}
+
+// ApplyPromotion subtracts the promotion discounts from b and recomputes tax and
+// rounding for the destination's city. Discounts never exceed the lines they reduce.
+func (e Engine) ApplyPromotion(b *entities.PriceBreakdown, destination utils.Point, promo entities.AppliedPromotion) {
+	city := e.cfg.CityFor(destination)
+
+	b.PromoCode = promo.Code
+	b.Discount = min(promo.Discount, b.ItemsSubtotal)
+	b.DeliveryDiscount = min(promo.DeliveryDiscount, b.DeliveryFee+b.StopSurcharge)
+
+	finalize(b, city)
+}
+
+// finalize derives tax, rounding and total from the other lines of the breakdown.
+func finalize(b *entities.PriceBreakdown, city CityConfig) {
+	items := b.ItemsSubtotal - b.Discount
+	fees := b.DeliveryFee + b.StopSurcharge - b.DeliveryDiscount + b.ServiceFee
+
+	taxable := 0
+	if city.TaxOnItems {
+		taxable += items
+	}
+	if city.TaxOnFees {
+		taxable += fees
+	}
+
+	b.Taxable = taxable
+	b.Tax = int(math.Round(float64(taxable) * city.TaxPercent / 100))
+
+	raw := items + fees + b.Tax
+	b.Total = roundAmount(raw, city.RoundingUnit, city.RoundingMode)
+	b.Rounding = b.Total - raw
+}
+
+func roundAmount(amount, unit int, mode string) int {
+	if unit <= 1 {
+		 return amount
+	}
+
+	v := float64(amount) / float64(unit)
+	switch mode {
+	case "up":
+		return int(math.Ceil(v)) * unit
+	case "down":
+		return int(math.Floor(v)) * unit
+	default:
+		return int(math.Round(v)) * unit
+	}
+}
@@ -0,0 +1,155 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 in points, and the margin kept free around the text.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+
+	// Courier is monospaced, every glyph is 600/1000 of the font size wide,
+	// which makes right alignment exact without font metrics.
+	charWidth = 0.6
+)
+
+// Column is one piece of text on a row. Right aligned columns end at X,
+// the others start there.
+type Column struct {
+	X     float64
+	Text  string
+	Right bool
+}
+
+// Document is a minimal PDF writer: A4 pages of rows of Courier text and
+// horizontal rules, which is all a receipt needs. It only uses the fonts every
+// PDF reader has built in, so nothing is embedded.
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func NewDocument() *Document {
+	d := &Document{}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// reserve moves to a new page when height does not fit on the current one.
+func (d *Document) reserve(height float64) {
+	if d.y-height < margin {
+		 d.newPage()
+	}
+}
+
+// Row writes cols on one line in the given size and advances below it.
+func (d *Document) Row(size float64, bold bool, cols ...Column) {
+	height := size * 1.4
+	d.reserve(height)
+	d.y -= height
+
+	font := "F1"
+	if bold {
+		 font = "F2"
+	}
+
+	page := d.pages[len(d.pages)-1]
+	for _, c := range cols {
+		text := encode(c.Text)
+		x := c.X
+		if c.Right {
+			 x -= float64(len(text)) * size * charWidth
+		}
+		fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.y, escape(text))
+	}
+}
+
+// Rule draws a thin line across the page below the last row.
+func (d *Document) Rule() {
+	d.reserve(8)
+	d.y -= 4
+	fmt.Fprintf(d.pages[len(d.pages)-1], "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, d.y, pageWidth-margin, d.y)
+	d.y -= 4
+}
+
+// Space leaves height points empty.
+func (d *Document) Space(height float64) {
+	d.y -= height
+}
+
+// Bytes assembles the document. Objects 1 to 4 are the catalog, the page
+// tree and the two fonts, then every page is followed by its content stream.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	offsets := []int{}
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+i*2,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// encode maps s to single byte Latin-1, which the fonts' WinAnsi encoding
+// shows as is. Anything outside it becomes a question mark.
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			out = append(out, ' ')
+		case r < 0x20 || r >= 0x7f && r < 0xa0 || r > 0xff:
+			out = append(out, '?')
+		default:
+			out = append(out, byte(r))
+		}
+	}
+	return out
+}
+
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c == '\\' || c == '(' || c == ')' {
+			 sb.WriteByte('\\')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
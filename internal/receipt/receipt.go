@@ -0,0 +1,162 @@
+// Package receipt renders order receipts as PDF in plain Go, so receipts can
+// be produced without any external service or PDF library.
+package receipt
+
+import (
+	"belimang/internal/entities"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// columns of the item table
+const (
+	colItem   = margin
+	colQty    = 370.0
+	colPrice  = 460.0
+	colAmount = pageWidth - margin
+
+	itemChars = 44
+)
+
+// Render lays r out as a PDF: the buyer and delivery first, then one section
+// per merchant with its invoice number and lines, then the price breakdown
+// exactly as it was quoted.
+func Render(r entities.Receipt, loc *time.Location) []byte {
+	d := NewDocument()
+
+	d.Row(16, true, Column{X: margin, Text: "RECEIPT"})
+	d.Space(4)
+	d.Row(9, false, Column{X: margin, Text: "Order " + r.OrderID})
+	d.Row(9, false, Column{X: margin, Text: "Ordered " + r.OrderedAt.In(loc).Format("02 Jan 2006 15:04 MST")})
+	d.Row(9, false, Column{X: margin, Text: fmt.Sprintf("Billed to %s <%s>", r.Username, r.Email)})
+	d.Row(9, false, Column{X: margin, Text: "Deliver to " + deliveryLine(r.Delivery)})
+	d.Rule()
+
+	for _, m := range r.Merchants {
+		d.Space(6)
+		d.Row(12, true, Column{X: margin, Text: m.Name})
+		d.Row(9, false,
+			Column{X: margin, Text: "Invoice " + m.Invoice.Number},
+			Column{X: colAmount, Text: "Issued " + m.Invoice.IssuedAt.In(loc).Format("02 Jan 2006"), Right: true},
+		)
+		d.Space(4)
+		d.Row(9, true,
+			Column{X: colItem, Text: "Item"},
+			Column{X: colQty, Text: "Qty", Right: true},
+			Column{X: colPrice, Text: "Price", Right: true},
+			Column{X: colAmount, Text: "Amount", Right: true},
+		)
+		for _, l := range m.Lines {
+			d.Row(9, false,
+				Column{X: colItem, Text: truncate(l.Name, itemChars)},
+				Column{X: colQty, Text: strconv.Itoa(l.Quantity), Right: true},
+				Column{X: colPrice, Text: knownRupiah(l.UnitPrice), Right: true},
+				Column{X: colAmount, Text: knownRupiah(l.Amount), Right: true},
+			)
+		}
+		d.Row(9, true,
+			Column{X: colPrice, Text: "Subtotal", Right: true},
+			Column{X: colAmount, Text: Rupiah(m.Subtotal), Right: true},
+		)
+		d.Row(9, false,
+			Column{X: colPrice, Text: fmt.Sprintf("%s on %s", taxLabel(r.PriceBreakdown), Rupiah(m.TaxBase)), Right: true},
+			Column{X: colAmount, Text: Rupiah(m.Tax), Right: true},
+		)
+		d.Rule()
+	}
+
+	b := r.PriceBreakdown
+	d.Space(6)
+	total := func(label string, amount int) {
+		d.Row(10, false,
+			Column{X: colPrice, Text: label, Right: true},
+			Column{X: colAmount, Text: Rupiah(amount), Right: true},
+		)
+	}
+
+	total("Items", b.ItemsSubtotal)
+	if b.Discount > 0 {
+		 total(discountLabel("Discount", b.PromoCode), -b.Discount)
+	}
+	total("Delivery fee", b.DeliveryFee)
+	if b.StopSurcharge > 0 {
+		 total("Extra stops", b.StopSurcharge)
+	}
+	if b.DeliveryDiscount > 0 {
+		 total(discountLabel("Delivery discount", b.PromoCode), -b.DeliveryDiscount)
+	}
+	total("Service fee", b.ServiceFee)
+	total(taxLabel(b), b.Tax)
+	if b.Rounding != 0 {
+		 total("Rounding", b.Rounding)
+	}
+	d.Rule()
+	d.Row(12, true,
+		Column{X: colPrice, Text: "Total", Right: true},
+		Column{X: colAmount, Text: Rupiah(b.Total), Right: true},
+	)
+
+	return d.Bytes()
+}
+
+// Rupiah formats amount with dots between thousands, e.g. Rp 12.500.
+func Rupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.Itoa(amount)
+	var sb strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			 sb.WriteByte('.')
+		}
+		sb.WriteRune(c)
+	}
+
+	return sign + "Rp " + sb.String()
+}
+
+// knownRupiah is Rupiah for amounts that may be unknown, shown as a dash.
+func knownRupiah(amount *int) string {
+	if amount == nil {
+		 return "-"
+	}
+	return Rupiah(*amount)
+}
+
+func taxLabel(b entities.PriceBreakdown) string {
+	return fmt.Sprintf("%s %s%%", b.TaxName, strconv.FormatFloat(b.TaxRate, 'f', -1, 64))
+}
+
+func discountLabel(label, code string) string {
+	if code == "" {
+		 return label
+	}
+	return fmt.Sprintf("%s (%s)", label, code)
+}
+
+func deliveryLine(d entities.Delivery) string {
+	parts := []string{}
+	if d.Building != "" {
+		 parts = append(parts, d.Building)
+	}
+	if d.Floor != "" {
+		 parts = append(parts, "floor "+d.Floor)
+	}
+	parts = append(parts, fmt.Sprintf("%.6f, %.6f", d.Location.Lat, d.Location.Lon))
+
+	return strings.Join(parts, ", ")
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		 return s
+	}
+	return string(r[:n-3]) + "..."
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AddressRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAddressRepository(db *pgxpool.Pool) AddressRepository {
+	return AddressRepository{db: db}
+}
+
+const addressColumns = `
+	id, user_id, label, ST_Y(location::geometry), ST_X(location::geometry),
+	building, floor, notes, created_at, updated_at
+`
+
+func scanAddress(row pgx.Row) (entities.Address, error) {
+	a := entities.Address{}
+	err := row.Scan(
+		&a.ID,
+		&a.UserID,
+		&a.Label,
+		&a.Location.Lat,
+		&a.Location.Lon,
+		&a.Building,
+		&a.Floor,
+		&a.Notes,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	return a, err
+}
+
+func (r AddressRepository) GetAddresses(ctx context.Context, userID string) ([]entities.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+addressColumns+`
+		FROM user_addresses
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query addresses")
+	}
+	defer rows.Close()
+
+	addresses := make([]entities.Address, 0)
+	for rows.Next() {
+		a, err := scanAddress(rows)
+		if err != nil {
+			 return nil, utils.NewInternal("failed to scan address row")
+		}
+		addresses = append(addresses, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating address rows")
+	}
+
+	return addresses, nil
+}
+
+func (r AddressRepository) GetAddress(ctx context.Context, id string) (entities.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Address{}, err
+	}
+
+	a, err := scanAddress(r.db.QueryRow(ctx, `
+		SELECT `+addressColumns+`
+		FROM user_addresses
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Address{}, utils.NewNotFound("address does not exist")
+		}
+		return entities.Address{}, utils.NewInternal("failed get address")
+	}
+
+	return a, nil
+}
+
+func (r AddressRepository) CreateAddress(ctx context.Context, tx pgx.Tx, a entities.Address) (entities.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Address{}, err
+	}
+
+	a, err := scanAddress(tx.QueryRow(ctx, `
+		INSERT INTO user_addresses (user_id, label, location, building, floor, notes)
+		VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5, $6, $7)
+		RETURNING `+addressColumns,
+		a.UserID, a.Label, a.Location.Lon, a.Location.Lat, a.Building, a.Floor, a.Notes,
+	))
+	if err != nil {
+		 return entities.Address{}, utils.NewInternal("failed create address")
+	}
+
+	return a, nil
+}
+
+// UpdateAddress replaces every field of the address a.ID owned by a.UserID.
+func (r AddressRepository) UpdateAddress(ctx context.Context, tx pgx.Tx, a entities.Address) (entities.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Address{}, err
+	}
+
+	a, err := scanAddress(tx.QueryRow(ctx, `
+		UPDATE user_addresses SET
+			label = $3,
+			location = ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography,
+			building = $6,
+			floor = $7,
+			notes = $8,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+addressColumns,
+		a.ID, a.UserID, a.Label, a.Location.Lon, a.Location.Lat, a.Building, a.Floor, a.Notes,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Address{}, utils.NewNotFound("address does not exist")
+		}
+		return entities.Address{}, utils.NewInternal("failed update address")
+	}
+
+	return a, nil
+}
+
+// DeleteAddress removes the address. Estimates and orders keep their copy of it.
+func (r AddressRepository) DeleteAddress(ctx context.Context, tx pgx.Tx, id, userID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM user_addresses WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		 return utils.NewInternal("failed delete address")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("address does not exist")
+	}
+
+	return nil
+}
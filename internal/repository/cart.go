@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CartRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCartRepository(db *pgxpool.Pool) CartRepository {
+	return CartRepository{db: db}
+}
+
+// GetCart returns the user's cart lines with current item and merchant data,
+// merchants in the order they were first added and items in the order they
+// were added.
+func (r CartRepository) GetCart(ctx context.Context, userID string) ([]entities.CartLine, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			c.item_id, c.merchant_id, c.quantity, c.price_at_add, c.created_at,
+			it.name, it.category, it.imageurl, it.price, it.is_available,
+			m.name, m.category, m.imageurl,
+			ST_Y(m.location::geometry), ST_X(m.location::geometry),
+			m.created_at
+		FROM cart_items c
+		JOIN items it ON it.id = c.item_id
+		JOIN merchants m ON m.id = c.merchant_id
+		WHERE c.user_id = $1
+		ORDER BY MIN(c.created_at) OVER (PARTITION BY c.merchant_id), c.merchant_id, c.created_at, c.item_id
+	`, userID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query cart")
+	}
+	defer rows.Close()
+
+	lines := make([]entities.CartLine, 0)
+	for rows.Next() {
+		l := entities.CartLine{}
+		if err := rows.Scan(
+			&l.ItemID,
+			&l.MerchantID,
+			&l.Quantity,
+			&l.PriceAtAdd,
+			&l.CreatedAt,
+			&l.Item.Name,
+			&l.Item.Category,
+			&l.Item.ImageURL,
+			&l.Item.Price,
+			&l.Item.IsAvailable,
+			&l.Merchant.Name,
+			&l.Merchant.Category,
+			&l.Merchant.ImageURL,
+			&l.Merchant.Location.Lat,
+			&l.Merchant.Location.Lon,
+			&l.Merchant.CreatedAt,
+		); err != nil {
+			 return nil, utils.NewInternal("failed to scan cart row")
+		}
+		l.Item.ID, l.Item.MerchantID = l.ItemID, l.MerchantID
+		l.Merchant.ID = l.MerchantID
+		lines = append(lines, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating cart rows")
+	}
+
+	return lines, nil
+}
+
+// AddItem puts quantity of itemID in the cart at its current price, or adds
+// to the quantity already there, capped at maxQuantity.
+func (r CartRepository) AddItem(ctx context.Context, tx pgx.Tx, userID, itemID string, quantity, maxQuantity int) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	var available bool
+	err := tx.QueryRow(ctx, `SELECT is_available FROM items WHERE id = $1`, itemID).Scan(&available)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return utils.NewNotFound("item does not exist")
+		}
+		return utils.NewInternal("failed get item")
+	}
+	if !available {
+		 return utils.NewBadRequest("item is not available")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO cart_items (user_id, item_id, merchant_id, quantity, price_at_add)
+		SELECT $1, it.id, it.merchant_id, LEAST($3::int, $4::int), it.price
+		FROM items it WHERE it.id = $2
+		ON CONFLICT (user_id, item_id) DO UPDATE SET
+			quantity = LEAST(cart_items.quantity + $3::int, $4::int),
+			price_at_add = EXCLUDED.price_at_add,
+			updated_at = CURRENT_TIMESTAMP
+	`, userID, itemID, quantity, maxQuantity)
+	if err != nil {
+		 return utils.NewInternal("failed add cart item")
+	}
+
+	return nil
+}
+
+func (r CartRepository) UpdateQuantity(ctx context.Context, tx pgx.Tx, userID, itemID string, quantity int) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE cart_items SET quantity = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND item_id = $2
+	`, userID, itemID, quantity)
+	if err != nil {
+		 return utils.NewInternal("failed update cart item")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("cart item does not exist")
+	}
+
+	return nil
+}
+
+func (r CartRepository) RemoveItem(ctx context.Context, tx pgx.Tx, userID, itemID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM cart_items WHERE user_id = $1 AND item_id = $2`, userID, itemID)
+	if err != nil {
+		 return utils.NewInternal("failed remove cart item")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("cart item does not exist")
+	}
+
+	return nil
+}
+
+func (r CartRepository) ClearCart(ctx context.Context, tx pgx.Tx, userID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM cart_items WHERE user_id = $1`, userID); err != nil {
+		 return utils.NewInternal("failed clear cart")
+	}
+
+	return nil
+}
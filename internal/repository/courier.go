@@ -0,0 +1,426 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CourierRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCourierRepository(db *pgxpool.Pool) CourierRepository {
+	return CourierRepository{db: db}
+}
+
+const courierColumns = `
+	id, user_id, status,
+	ST_Y(location::geometry) AS lat,
+	ST_X(location::geometry) AS lon,
+	last_seen_at, created_at, updated_at
+`
+
+func scanCourier(row pgx.Row) (entities.Courier, error) {
+	c := entities.Courier{}
+	var lat, lon *float64
+	err := row.Scan(&c.ID, &c.UserID, &c.Status, &lat, &lon, &c.LastSeenAt, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		 return entities.Courier{}, err
+	}
+
+	if lat != nil && lon != nil {
+		 c.Location = &entities.Location{Lat: *lat, Lon: *lon}
+	}
+	return c, nil
+}
+
+func (r CourierRepository) CreateCourier(ctx context.Context, tx pgx.Tx, userID string) (entities.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Courier{}, err
+	}
+
+	c, err := scanCourier(tx.QueryRow(ctx, `
+		INSERT INTO couriers (user_id) VALUES ($1)
+		RETURNING `+courierColumns, userID))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entities.Courier{}, utils.NewConflict("user is already a courier")
+		}
+		if isForeignKeyViolation(err) {
+			return entities.Courier{}, utils.NewNotFound("user does not exist")
+		}
+		return entities.Courier{}, utils.NewInternal("failed create courier")
+	}
+
+	return c, nil
+}
+
+func (r CourierRepository) GetCourierByUser(ctx context.Context, userID string) (entities.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Courier{}, err
+	}
+
+	c, err := scanCourier(r.db.QueryRow(ctx, `SELECT `+courierColumns+` FROM couriers WHERE user_id = $1`, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Courier{}, utils.NewNotFound("courier does not exist")
+		}
+		return entities.Courier{}, utils.NewInternal("failed get courier")
+	}
+
+	return c, nil
+}
+
+// SetCourierAvailability switches a courier between Available and Offline. A
+// Busy courier keeps its status until the delivery ends.
+func (r CourierRepository) SetCourierAvailability(ctx context.Context, courierID, status string) (entities.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Courier{}, err
+	}
+
+	c, err := scanCourier(r.db.QueryRow(ctx, `
+		UPDATE couriers SET status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status <> 'Busy'
+		RETURNING `+courierColumns, courierID, status))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Courier{}, utils.NewConflict("courier is on a delivery")
+		}
+		return entities.Courier{}, utils.NewInternal("failed update courier availability")
+	}
+
+	return c, nil
+}
+
+// RecordHeartbeat stores the courier's latest position and marks it as seen now.
+func (r CourierRepository) RecordHeartbeat(ctx context.Context, courierID string, loc entities.Location) (entities.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Courier{}, err
+	}
+
+	c, err := scanCourier(r.db.QueryRow(ctx, `
+		UPDATE couriers SET
+			location = ST_SetSRID(ST_MakePoint($2, $3), 4326)::GEOGRAPHY,
+			last_seen_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+courierColumns, courierID, loc.Lon, loc.Lat))
+	if err != nil {
+		 return entities.Courier{}, utils.NewInternal("failed record courier heartbeat")
+	}
+
+	return c, nil
+}
+
+const offerColumns = `
+	id, order_id, courier_id, status, score, approach_km, route_km,
+	expires_at, responded_at, created_at,
+	expires_at <= CURRENT_TIMESTAMP AS expired
+`
+
+func scanOffer(row pgx.Row) (entities.DispatchOffer, error) {
+	o := entities.DispatchOffer{}
+	err := row.Scan(
+		&o.ID,
+		&o.OrderID,
+		&o.CourierID,
+		&o.Status,
+		&o.Score,
+		&o.ApproachKm,
+		&o.RouteKm,
+		&o.ExpiresAt,
+		&o.RespondedAt,
+		&o.CreatedAt,
+		&o.Expired,
+	)
+	return o, err
+}
+
+// GetPendingOffers returns the courier's open offers that have not timed out.
+func (r CourierRepository) GetPendingOffers(ctx context.Context, courierID string) ([]entities.DispatchOffer, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+offerColumns+`
+		FROM dispatch_offers
+		WHERE courier_id = $1 AND status = 'Pending' AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY created_at
+	`, courierID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query dispatch offers")
+	}
+	defer rows.Close()
+
+	offers := make([]entities.DispatchOffer, 0)
+	for rows.Next() {
+		o, err := scanOffer(rows)
+		if err != nil {
+			 return nil, utils.NewInternal("failed to scan dispatch offer row")
+		}
+		offers = append(offers, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating dispatch offer rows")
+	}
+
+	return offers, nil
+}
+
+// GetOfferForUpdate loads an offer made to courierID and locks it until tx ends.
+func (r CourierRepository) GetOfferForUpdate(ctx context.Context, tx pgx.Tx, courierID, offerID string) (entities.DispatchOffer, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	o, err := scanOffer(tx.QueryRow(ctx, `
+		SELECT `+offerColumns+`
+		FROM dispatch_offers
+		WHERE id = $1 AND courier_id = $2
+		FOR UPDATE
+	`, offerID, courierID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.DispatchOffer{}, utils.NewNotFound("offer does not exist")
+		}
+		return entities.DispatchOffer{}, err
+	}
+
+	return o, nil
+}
+
+func (r CourierRepository) RespondToOffer(ctx context.Context, tx pgx.Tx, offerID, status string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE dispatch_offers SET status = $2, responded_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, offerID, status)
+	if err != nil {
+		 return utils.NewInternal("failed update dispatch offer")
+	}
+
+	return nil
+}
+
+// AssignCourier hands an order that has no courier yet to courierID and marks
+// the courier Busy.
+func (r CourierRepository) AssignCourier(ctx context.Context, tx pgx.Tx, orderID, courierID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE orders SET courier_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND courier_id IS NULL AND status IN ('Accepted', 'Preparing')
+	`, orderID, courierID)
+	if err != nil {
+		 return utils.NewInternal("failed assign courier")
+	}
+	if tag.RowsAffected() == 0 {
+		 return utils.NewConflict("order is no longer waiting for a courier")
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE couriers SET status = 'Busy', updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, courierID); err != nil {
+		 return utils.NewInternal("failed update courier status")
+	}
+
+	return nil
+}
+
+// ExpireOffers closes pending offers that ran out of time, and those for orders
+// that got a courier some other way or no longer need one.
+func (r CourierRepository) ExpireOffers(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		UPDATE dispatch_offers d SET
+			status = CASE WHEN d.expires_at <= CURRENT_TIMESTAMP THEN 'Expired' ELSE 'Cancelled' END::dispatch_offer_statuses_enum,
+			responded_at = CURRENT_TIMESTAMP
+		FROM orders o
+		WHERE o.id = d.order_id AND d.status = 'Pending'
+		AND (
+			d.expires_at <= CURRENT_TIMESTAMP
+			OR o.courier_id IS NOT NULL
+			OR o.status NOT IN ('Accepted', 'Preparing')
+		)
+	`)
+	if err != nil {
+		 return 0, utils.NewInternal("failed expire dispatch offers")
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ReleaseCouriers makes Busy couriers Available again once none of their
+// orders is still on the way.
+func (r CourierRepository) ReleaseCouriers(ctx context.Context) (int, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		UPDATE couriers c SET status = 'Available', updated_at = CURRENT_TIMESTAMP
+		WHERE c.status = 'Busy' AND NOT EXISTS (
+			SELECT 1 FROM orders o
+			WHERE o.courier_id = c.id AND o.status IN ('Accepted', 'Preparing', 'PickedUp')
+		)
+	`)
+	if err != nil {
+		 return 0, utils.NewInternal("failed release couriers")
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDispatchableOrders locks up to limit orders that merchants have accepted
+// but that have neither a courier nor an open offer. Rows locked by another
+// dispatcher are skipped. The pickup is the first stop of the quoted route, or
+// any merchant on the order for estimates made before routes were stored.
+func (r CourierRepository) ClaimDispatchableOrders(ctx context.Context, tx pgx.Tx, limit int) ([]entities.DispatchOrder, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			o.id, m.id,
+			ST_Y(m.location::geometry), ST_X(m.location::geometry),
+			COALESCE(e.route_distance_km, 0)
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		JOIN merchants m ON m.id = COALESCE(
+			e.start_merchant_id,
+			(SELECT oi.merchant_id FROM orders_items oi WHERE oi.estimate_id = e.id LIMIT 1)
+		)
+		WHERE o.status IN ('Accepted', 'Preparing')
+		AND o.courier_id IS NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM dispatch_offers d WHERE d.order_id = o.id AND d.status = 'Pending'
+		)
+		ORDER BY o.updated_at
+		LIMIT $1
+		FOR UPDATE OF o SKIP LOCKED
+	`, limit)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query dispatchable orders")
+	}
+	defer rows.Close()
+
+	orders := make([]entities.DispatchOrder, 0, limit)
+	for rows.Next() {
+		o := entities.DispatchOrder{}
+		if err := rows.Scan(&o.OrderID, &o.PickupMerchantID, &o.Pickup.Lat, &o.Pickup.Lon, &o.RouteKm); err != nil {
+			 return nil, utils.NewInternal("failed to scan dispatchable order row")
+		}
+		orders = append(orders, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating dispatchable order rows")
+	}
+
+	return orders, nil
+}
+
+// FindCandidates returns up to limit Available couriers within radiusKm of the
+// order's pickup, closest first, with how long each has gone without an offer.
+// Couriers that went quiet for longer than staleAfter, that are weighing
+// another offer, or whose offer of this order ended within cooldown are left
+// out. Candidates are locked so a parallel dispatcher cannot offer them
+// something else meanwhile.
+func (r CourierRepository) FindCandidates(ctx context.Context, tx pgx.Tx, order entities.DispatchOrder, radiusKm float64, staleAfter, cooldown time.Duration, limit int) ([]entities.CourierCandidate, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH pickup AS (
+			SELECT ST_SetSRID(ST_MakePoint($1, $2), 4326)::GEOGRAPHY AS p
+		)
+		SELECT
+			c.id,
+			ST_Y(c.location::geometry), ST_X(c.location::geometry),
+			ST_Distance(c.location, pickup.p) / 1000.0,
+			EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - COALESCE(
+				(SELECT MAX(d.created_at) FROM dispatch_offers d WHERE d.courier_id = c.id),
+				c.created_at
+			))::float8
+		FROM couriers c, pickup
+		WHERE c.status = 'Available'
+		AND c.location IS NOT NULL
+		AND c.last_seen_at > CURRENT_TIMESTAMP - make_interval(secs => $3)
+		AND ST_DWithin(c.location, pickup.p, $4)
+		AND NOT EXISTS (
+			SELECT 1 FROM dispatch_offers d
+			WHERE d.courier_id = c.id
+			AND (
+				d.status = 'Pending'
+				OR (d.order_id = $5 AND COALESCE(d.responded_at, d.expires_at) > CURRENT_TIMESTAMP - make_interval(secs => $7))
+			)
+		)
+		ORDER BY c.location <-> pickup.p
+		LIMIT $6
+		FOR UPDATE OF c SKIP LOCKED
+	`, order.Pickup.Lon, order.Pickup.Lat, staleAfter.Seconds(), radiusKm*1000, order.OrderID, limit, cooldown.Seconds())
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query courier candidates")
+	}
+	defer rows.Close()
+
+	candidates := make([]entities.CourierCandidate, 0, limit)
+	for rows.Next() {
+		c := entities.CourierCandidate{}
+		var idleSeconds float64
+		if err := rows.Scan(&c.CourierID, &c.Location.Lat, &c.Location.Lon, &c.StraightKm, &idleSeconds); err != nil {
+			 return nil, utils.NewInternal("failed to scan courier candidate row")
+		}
+		c.IdleFor = time.Duration(idleSeconds * float64(time.Second))
+		candidates = append(candidates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating courier candidate rows")
+	}
+
+	return candidates, nil
+}
+
+func (r CourierRepository) InsertOffer(ctx context.Context, tx pgx.Tx, offer entities.DispatchOffer, timeout time.Duration) (entities.DispatchOffer, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	o, err := scanOffer(tx.QueryRow(ctx, `
+		INSERT INTO dispatch_offers (order_id, courier_id, score, approach_km, route_km, expires_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + make_interval(secs => $6))
+		RETURNING `+offerColumns,
+		offer.OrderID,
+		offer.CourierID,
+		offer.Score,
+		offer.ApproachKm,
+		offer.RouteKm,
+		timeout.Seconds(),
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entities.DispatchOffer{}, utils.NewConflict("order or courier already has an open offer")
+		}
+		return entities.DispatchOffer{}, utils.NewInternal("failed create dispatch offer")
+	}
+
+	return o, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ETARepository struct {
+	db *pgxpool.Pool
+}
+
+func NewETARepository(db *pgxpool.Pool) ETARepository {
+	return ETARepository{db: db}
+}
+
+func (r ETARepository) GetLatestModel(ctx context.Context) (entities.ETAModel, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.ETAModel{}, err
+	}
+
+	m := entities.ETAModel{}
+	err := r.db.QueryRow(ctx, `
+		SELECT id, stop_dwell_minutes, speed_kmh, segment_samples, trip_samples, created_at
+		FROM eta_models
+		ORDER BY id DESC
+		LIMIT 1
+	`).Scan(&m.ID, &m.StopDwellMinutes, &m.SpeedKmh, &m.SegmentSamples, &m.TripSamples, &m.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.ETAModel{}, utils.NewNotFound("eta model does not exist")
+		}
+		return entities.ETAModel{}, utils.NewInternal("failed get eta model")
+	}
+
+	return m, nil
+}
+
+func (r ETARepository) InsertModel(ctx context.Context, tx pgx.Tx, m entities.ETAModel) (entities.ETAModel, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.ETAModel{}, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO eta_models (stop_dwell_minutes, speed_kmh, segment_samples, trip_samples)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, m.StopDwellMinutes, m.SpeedKmh, m.SegmentSamples, m.TripSamples).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		 return entities.ETAModel{}, utils.NewInternal("failed create eta model")
+	}
+
+	return m, nil
+}
+
+// GetMerchantPrepStats returns the learned preparation times of the merchants
+// in ids that have any, keyed by merchant id.
+func (r ETARepository) GetMerchantPrepStats(ctx context.Context, ids []string) (map[string]entities.MerchantPrepStat, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT merchant_id, median_minutes, p90_minutes, samples, updated_at
+		FROM merchant_prep_stats
+		WHERE merchant_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query merchant prep stats")
+	}
+	defer rows.Close()
+
+	stats := make(map[string]entities.MerchantPrepStat, len(ids))
+	for rows.Next() {
+		s := entities.MerchantPrepStat{}
+		if err := rows.Scan(&s.MerchantID, &s.MedianMinutes, &s.P90Minutes, &s.Samples, &s.UpdatedAt); err != nil {
+			 return nil, utils.NewInternal("failed to scan merchant prep stat row")
+		}
+		stats[s.MerchantID] = s
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating merchant prep stat rows")
+	}
+
+	return stats, nil
+}
+
+// RefreshMerchantPrepStats recomputes preparation times from sub-orders made
+// ready since since, measured from when the merchant accepted to when they
+// marked it ready. Merchants with fewer than minSamples such sub-orders keep
+// their previous stats, if any.
+func (r ETARepository) RefreshMerchantPrepStats(ctx context.Context, tx pgx.Tx, since time.Time, minSamples int) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO merchant_prep_stats (merchant_id, median_minutes, p90_minutes, samples, updated_at)
+		SELECT
+			merchant_id,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY minutes),
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY minutes),
+			COUNT(*),
+			CURRENT_TIMESTAMP
+		FROM (
+			SELECT
+				merchant_id,
+				EXTRACT(EPOCH FROM ready_at - accepted_at) / 60 AS minutes
+			FROM merchant_orders
+			WHERE ready_at >= $1
+			AND accepted_at IS NOT NULL
+		) prep
+		WHERE minutes > 0
+		GROUP BY merchant_id
+		HAVING COUNT(*) >= $2
+		ON CONFLICT (merchant_id) DO UPDATE SET
+			median_minutes = EXCLUDED.median_minutes,
+			p90_minutes = EXCLUDED.p90_minutes,
+			samples = EXCLUDED.samples,
+			updated_at = EXCLUDED.updated_at
+	`, since, minSamples)
+	if err != nil {
+		 return 0, utils.NewInternal("failed to refresh merchant prep stats")
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetSpeedBuckets measures how fast couriers moved while delivering, per hour
+// since since. Speeds come from consecutive pings of the same delivery; pings
+// less precise than maxAccuracyM are left out, as are pairs too far apart in
+// time to say much and pairs where the courier was standing still.
+func (r ETARepository) GetSpeedBuckets(ctx context.Context, since time.Time, maxAccuracyM float64) ([]entities.SpeedBucket, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		WITH pairs AS (
+			SELECT
+				recorded_at,
+				ST_Distance(
+					ST_SetSRID(ST_MakePoint(lon, lat), 4326)::geography,
+					ST_SetSRID(ST_MakePoint(LAG(lon) OVER w, LAG(lat) OVER w), 4326)::geography
+				) AS meters,
+				EXTRACT(EPOCH FROM recorded_at - LAG(recorded_at) OVER w) AS seconds
+			FROM courier_locations
+			WHERE order_id IS NOT NULL
+			AND recorded_at >= $1
+			AND (accuracy_m IS NULL OR accuracy_m <= $2)
+			WINDOW w AS (PARTITION BY courier_id, order_id ORDER BY recorded_at)
+		),
+		segments AS (
+			SELECT recorded_at, meters / NULLIF(seconds, 0) * 3.6 AS kmh
+			FROM pairs
+			WHERE seconds BETWEEN 2 AND 120
+		)
+		SELECT
+			date_trunc('hour', recorded_at),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY kmh),
+			COUNT(*)
+		FROM segments
+		WHERE kmh BETWEEN 3 AND 120
+		GROUP BY 1
+		ORDER BY 1
+	`, since, maxAccuracyM)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query courier speeds")
+	}
+	defer rows.Close()
+
+	buckets := make([]entities.SpeedBucket, 0)
+	for rows.Next() {
+		b := entities.SpeedBucket{}
+		if err := rows.Scan(&b.Hour, &b.MedianKmh, &b.Samples); err != nil {
+			 return nil, utils.NewInternal("failed to scan courier speed row")
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating courier speed rows")
+	}
+
+	return buckets, nil
+}
+
+// GetTripSamples returns trips that ended since since and had enough pings to
+// measure, with how many merchants each picked up from.
+func (r ETARepository) GetTripSamples(ctx context.Context, since time.Time) ([]entities.TripSample, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			t.started_at, t.duration_seconds, t.distance_km,
+			(SELECT COUNT(*) FROM merchant_orders mo WHERE mo.order_id = t.order_id)
+		FROM order_trips t
+		WHERE t.ended_at >= $1
+		AND t.started_at IS NOT NULL
+		AND t.raw_points >= 2
+	`, since)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query trip samples")
+	}
+	defer rows.Close()
+
+	trips := make([]entities.TripSample, 0)
+	for rows.Next() {
+		t := entities.TripSample{}
+		if err := rows.Scan(&t.StartedAt, &t.DurationSeconds, &t.DistanceKm, &t.Stops); err != nil {
+			 return nil, utils.NewInternal("failed to scan trip sample row")
+		}
+		trips = append(trips, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating trip sample rows")
+	}
+
+	return trips, nil
+}
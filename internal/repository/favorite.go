@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FavoriteRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewFavoriteRepository(db *pgxpool.Pool) FavoriteRepository {
+	return FavoriteRepository{db: db}
+}
+
+func (r FavoriteRepository) GetFavoriteMerchants(ctx context.Context, userID string) ([]entities.Merchant, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			m.id, m.name, m.category, m.imageurl,
+			ST_Y(m.location::geometry), ST_X(m.location::geometry),
+			m.created_at
+		FROM favorite_merchants f
+		JOIN merchants m ON m.id = f.merchant_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC, m.id
+	`, userID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query favorite merchants")
+	}
+	defer rows.Close()
+
+	merchants := make([]entities.Merchant, 0)
+	for rows.Next() {
+		m := entities.Merchant{}
+		if err := rows.Scan(&m.ID, &m.Name, &m.Category, &m.ImageURL, &m.Location.Lat, &m.Location.Lon, &m.CreatedAt); err != nil {
+			 return nil, utils.NewInternal("failed to scan favorite merchant row")
+		}
+		merchants = append(merchants, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating favorite merchant rows")
+	}
+
+	return merchants, nil
+}
+
+func (r FavoriteRepository) GetFavoriteItems(ctx context.Context, userID string) ([]entities.MercItem, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT it.id, it.merchant_id, it.name, it.category, it.price, it.imageurl, it.created_at, it.is_available
+		FROM favorite_items f
+		JOIN items it ON it.id = f.item_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC, it.id
+	`, userID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query favorite items")
+	}
+	defer rows.Close()
+
+	items := make([]entities.MercItem, 0)
+	for rows.Next() {
+		it := entities.MercItem{}
+		if err := rows.Scan(&it.ID, &it.MerchantID, &it.Name, &it.Category, &it.Price, &it.ImageURL, &it.CreatedAt, &it.IsAvailable); err != nil {
+			 return nil, utils.NewInternal("failed to scan favorite item row")
+		}
+		items = append(items, it)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating favorite item rows")
+	}
+
+	return items, nil
+}
+
+// AddFavoriteMerchant is idempotent, favoriting twice keeps the first one.
+func (r FavoriteRepository) AddFavoriteMerchant(ctx context.Context, tx pgx.Tx, userID, merchantID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO favorite_merchants (user_id, merchant_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, userID, merchantID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return utils.NewNotFound("merchant does not exist")
+		}
+		return utils.NewInternal("failed add favorite merchant")
+	}
+
+	return nil
+}
+
+// AddFavoriteItem is idempotent, favoriting twice keeps the first one.
+func (r FavoriteRepository) AddFavoriteItem(ctx context.Context, tx pgx.Tx, userID, itemID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO favorite_items (user_id, item_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, userID, itemID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return utils.NewNotFound("item does not exist")
+		}
+		return utils.NewInternal("failed add favorite item")
+	}
+
+	return nil
+}
+
+func (r FavoriteRepository) RemoveFavoriteMerchant(ctx context.Context, tx pgx.Tx, userID, merchantID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM favorite_merchants WHERE user_id = $1 AND merchant_id = $2`, userID, merchantID)
+	if err != nil {
+		 return utils.NewInternal("failed remove favorite merchant")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("favorite does not exist")
+	}
+
+	return nil
+}
+
+func (r FavoriteRepository) RemoveFavoriteItem(ctx context.Context, tx pgx.Tx, userID, itemID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM favorite_items WHERE user_id = $1 AND item_id = $2`, userID, itemID)
+	if err != nil {
+		 return utils.NewInternal("failed remove favorite item")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("favorite does not exist")
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GeoRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewGeoRepository(db *pgxpool.Pool) GeoRepository {
+	return GeoRepository{db: db}
+}
+
+func mapConditions(f entities.MapFilter) (string, []any) {
+	conds := []string{"m.location::geometry && ST_MakeEnvelope($1, $2, $3, $4, 4326)"}
+	args := []any{f.BBox.MinLon, f.BBox.MinLat, f.BBox.MaxLon, f.BBox.MaxLat}
+
+	if f.MerchantCategory != "" {
+		conds = append(conds, "m.category::text = $5")
+		args = append(args, f.MerchantCategory)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+func (r GeoRepository) GetMerchantsInBBox(ctx context.Context, f entities.MapFilter, limit int) ([]entities.Merchant, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	where, args := mapConditions(f)
+	query := fmt.Sprintf(`
+		SELECT
+			m.id, m.name, m.category, m.imageurl,
+			ST_Y(m.location::geometry) AS lat,
+			ST_X(m.location::geometry) AS lon
+		FROM merchants m
+		WHERE %s
+		ORDER BY m.id
+		LIMIT %d
+	`, where, limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query merchants in viewport")
+	}
+	defer rows.Close()
+
+	merchants := make([]entities.Merchant, 0)
+	for rows.Next() {
+		m := entities.Merchant{}
+		if err := rows.Scan(&m.ID, &m.Name, &m.Category, &m.ImageURL, &m.Location.Lat, &m.Location.Lon); err != nil {
+			 return nil, utils.NewInternal("failed to scan merchant")
+		}
+		merchants = append(merchants, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating merchant rows")
+	}
+
+	return merchants, nil
+}
+
+// GetMerchantClustersInBBox groups the merchants inside the viewport by geohash cell,
+// so the same merchants always fall into the same cluster while the map is panned.
+func (r GeoRepository) GetMerchantClustersInBBox(ctx context.Context, f entities.MapFilter, precision int) ([]entities.MerchantCluster, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	where, args := mapConditions(f)
+	query := fmt.Sprintf(`
+		SELECT
+			cell,
+			COUNT(*) AS total,
+			ST_Y(ST_Centroid(ST_Collect(geom))) AS lat,
+			ST_X(ST_Centroid(ST_Collect(geom))) AS lon,
+			ST_XMin(ST_Extent(geom)) AS min_lon,
+			ST_YMin(ST_Extent(geom)) AS min_lat,
+			ST_XMax(ST_Extent(geom)) AS max_lon,
+			ST_YMax(ST_Extent(geom)) AS max_lat
+		FROM (
+			SELECT m.location::geometry AS geom, ST_GeoHash(m.location::geometry, %d) AS cell
+			FROM merchants m
+			WHERE %s
+		) g
+		GROUP BY cell
+		ORDER BY cell
+	`, precision, where)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query merchant clusters")
+	}
+	defer rows.Close()
+
+	clusters := make([]entities.MerchantCluster, 0)
+	for rows.Next() {
+		c := entities.MerchantCluster{}
+		err := rows.Scan(
+			&c.Cell,
+			&c.Count,
+			&c.Location.Lat,
+			&c.Location.Lon,
+			&c.Bounds.MinLon,
+			&c.Bounds.MinLat,
+			&c.Bounds.MaxLon,
+			&c.Bounds.MaxLat,
+		)
+
+		if err != nil {
+			 return nil, utils.NewInternal("failed to scan merchant cluster")
+		}
+		clusters = append(clusters, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating merchant cluster rows")
+	}
+
+	return clusters, nil
+}
+
+func (r GeoRepository) GetAllMerchantLocations(ctx context.Context, category string) ([]entities.Merchant, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	conditions := []string{"1=1"}
+	args := []any{}
+
+	if category != "" {
+		conditions = append(conditions, "category::text = $1")
+		args = append(args, category)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id, name, category, imageurl,
+			ST_Y(location::geometry) AS lat,
+			ST_X(location::geometry) AS lon,
+			created_at
+		FROM merchants
+		WHERE %s
+		ORDER BY created_at, id
+	`, strings.Join(conditions, " AND "))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query merchant locations")
+	}
+	defer rows.Close()
+
+	merchants := make([]entities.Merchant, 0)
+	for rows.Next() {
+		m := entities.Merchant{}
+		if err := rows.Scan(&m.ID, &m.Name, &m.Category, &m.ImageURL, &m.Location.Lat, &m.Location.Lon, &m.CreatedAt); err != nil {
+			 return nil, utils.NewInternal("failed to scan merchant location")
+		}
+		merchants = append(merchants, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating merchant location rows")
+	}
+
+	return merchants, nil
+}
+
+// GetMerchantTile renders the merchants inside tile z/x/y as a Mapbox Vector Tile
+// with a single "merchants" layer in web mercator tile coordinates. The merchant
+// id is a feature property, MVT feature ids can only be integers.
+func (r GeoRepository) GetMerchantTile(ctx context.Context, z, x, y int) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	query := `
+		WITH bounds AS (
+			SELECT ST_TileEnvelope($1, $2, $3) AS geom
+		),
+		tile AS (
+			SELECT
+				ST_AsMVTGeom(ST_Transform(m.location::geometry, 3857), bounds.geom) AS geom,
+				m.id::text AS id,
+				m.name,
+				m.category::text AS category,
+				m.rating_avg::float8 AS rating
+			FROM merchants m, bounds
+			WHERE m.location::geometry && ST_Transform(bounds.geom, 4326)
+		)
+		SELECT COALESCE(ST_AsMVT(tile, 'merchants', 4096, 'geom'), '')
+		FROM tile
+	`
+
+	var mvt []byte
+	if err := r.db.QueryRow(ctx, query, z, x, y).Scan(&mvt); err != nil {
+		 return nil, utils.NewInternal("failed to render merchant tile")
+	}
+
+	return mvt, nil
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LedgerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLedgerRepository(db *pgxpool.Pool) LedgerRepository {
+	return LedgerRepository{db: db}
+}
+
+func scanLedgerAccount(row pgx.Row) (entities.LedgerAccount, error) {
+	a := entities.LedgerAccount{}
+	err := row.Scan(&a.ID, &a.OwnerID, &a.Kind, &a.Balance, &a.CreatedAt, &a.UpdatedAt)
+	return a, err
+}
+
+// GetWallet returns the wallet of userID, or not found if it was never used.
+func (r LedgerRepository) GetWallet(ctx context.Context, userID string) (entities.LedgerAccount, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.LedgerAccount{}, err
+	}
+
+	a, err := scanLedgerAccount(r.db.QueryRow(ctx, `
+		SELECT id, owner_id, kind, balance, created_at, updated_at
+		FROM ledger_accounts
+		WHERE owner_id = $1 AND kind = 'CustomerWallet'
+	`, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.LedgerAccount{}, utils.NewNotFound("wallet not found")
+		}
+		return entities.LedgerAccount{}, utils.NewInternal("failed get wallet")
+	}
+
+	return a, nil
+}
+
+// EnsureWallet returns the id of the wallet of userID, opening it if needed.
+func (r LedgerRepository) EnsureWallet(ctx context.Context, tx pgx.Tx, userID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		 return "", err
+	}
+
+	var id string
+	err := tx.QueryRow(ctx, `
+		WITH created AS (
+			INSERT INTO ledger_accounts (owner_id, kind)
+			VALUES ($1, 'CustomerWallet')
+			ON CONFLICT (owner_id, kind) WHERE owner_id IS NOT NULL DO NOTHING
+			RETURNING id
+		)
+		SELECT id FROM created
+		UNION ALL
+		SELECT id FROM ledger_accounts WHERE owner_id = $1 AND kind = 'CustomerWallet'
+		LIMIT 1
+	`, userID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		// opened by a concurrent transaction after this statement's snapshot
+		err = tx.QueryRow(ctx, `
+			SELECT id FROM ledger_accounts WHERE owner_id = $1 AND kind = 'CustomerWallet'
+		`, userID).Scan(&id)
+	}
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return "", utils.NewNotFound("user not found")
+		}
+		return "", utils.NewInternal("failed open wallet")
+	}
+
+	return id, nil
+}
+
+func (r LedgerRepository) GetSystemAccountID(ctx context.Context, tx pgx.Tx, kind string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		 return "", err
+	}
+
+	var id string
+	err := tx.QueryRow(ctx, `
+		SELECT id FROM ledger_accounts WHERE owner_id IS NULL AND kind = $1
+	`, kind).Scan(&id)
+	if err != nil {
+		 return "", utils.NewInternal("ledger system account missing: " + kind)
+	}
+
+	return id, nil
+}
+
+// InsertTransaction records the header of a ledger transaction. It reports
+// false with the existing transaction when reference was already posted.
+func (r LedgerRepository) InsertTransaction(ctx context.Context, tx pgx.Tx, t entities.LedgerTransaction) (entities.LedgerTransaction, bool, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.LedgerTransaction{}, false, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO ledger_transactions (kind, reference, description, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (reference) DO NOTHING
+		RETURNING id, created_at
+	`, t.Kind, t.Reference, t.Description, t.CreatedBy).Scan(&t.ID, &t.CreatedAt)
+	if err == nil {
+		 return t, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		 return entities.LedgerTransaction{}, false, utils.NewInternal("failed create ledger transaction")
+	}
+
+	existing := entities.LedgerTransaction{}
+	err = tx.QueryRow(ctx, `
+		SELECT id, kind, reference, description, created_by, created_at
+		FROM ledger_transactions
+		WHERE reference = $1
+	`, t.Reference).Scan(&existing.ID, &existing.Kind, &existing.Reference, &existing.Description, &existing.CreatedBy, &existing.CreatedAt)
+	if err != nil {
+		 return entities.LedgerTransaction{}, false, utils.NewInternal("failed get ledger transaction")
+	}
+
+	return existing, false, nil
+}
+
+// GetEntryAmount returns what transactionID moved on accountID, or not found
+// when it did not touch that account.
+func (r LedgerRepository) GetEntryAmount(ctx context.Context, tx pgx.Tx, transactionID, accountID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	var amount int
+	err := tx.QueryRow(ctx, `
+		SELECT amount FROM ledger_entries WHERE transaction_id = $1 AND account_id = $2
+	`, transactionID, accountID).Scan(&amount)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, utils.NewNotFound("ledger entry not found")
+		}
+		return 0, utils.NewInternal("failed get ledger entry")
+	}
+
+	return amount, nil
+}
+
+// PostEntries applies legs to their accounts and writes one entry per leg.
+// Customer wallets are locked in id order so concurrent postings cannot
+// deadlock, and each of their entries keeps the balance it left behind. System
+// accounts take part in every payment, so their rows are never locked or
+// updated; their balance is the sum of their entries.
+func (r LedgerRepository) PostEntries(ctx context.Context, tx pgx.Tx, transactionID string, legs []entities.LedgerLeg) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	ids := make([]string, 0, len(legs))
+	for _, leg := range legs {
+		ids = append(ids, leg.AccountID)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		SELECT id FROM ledger_accounts WHERE id = ANY($1::uuid[]) AND owner_id IS NOT NULL ORDER BY id FOR UPDATE
+	`, ids); err != nil {
+		 return utils.NewInternal("failed lock ledger accounts")
+	}
+
+	for _, leg := range legs {
+		var balance *int
+		err := tx.QueryRow(ctx, `
+			UPDATE ledger_accounts
+			SET balance = balance + $2, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND owner_id IS NOT NULL
+			RETURNING balance
+		`, leg.AccountID, leg.Amount).Scan(&balance)
+		if err != nil && err != pgx.ErrNoRows {
+			if isCheckViolation(err) {
+				return utils.NewBadRequest("insufficient wallet balance")
+			}
+			return utils.NewInternal("failed update ledger account")
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO ledger_entries (transaction_id, account_id, amount, balance_after)
+			VALUES ($1, $2, $3, $4)
+		`, transactionID, leg.AccountID, leg.Amount, balance)
+		if err != nil {
+			 return utils.NewInternal("failed create ledger entry")
+		}
+	}
+
+	return nil
+}
+
+func (r LedgerRepository) GetWalletEntries(ctx context.Context, userID string, limit, offset int) ([]entities.WalletEntry, int, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, 0, err
+	}
+
+	if limit <= 0 {
+		 limit = 5
+	}
+
+	if offset < 0 {
+		 offset = 0
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			t.id, t.kind, t.reference, t.description, e.amount, e.balance_after, e.created_at,
+			COUNT(*) OVER() AS total
+		FROM ledger_entries e
+		JOIN ledger_accounts a ON a.id = e.account_id
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE a.owner_id = $1 AND a.kind = 'CustomerWallet'
+		ORDER BY e.created_at DESC, e.id
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		 return nil, 0, utils.NewInternal("failed to query wallet entries")
+	}
+	defer rows.Close()
+
+	total := 0
+	entries := make([]entities.WalletEntry, 0, limit)
+	for rows.Next() {
+		e := entities.WalletEntry{}
+		err := rows.Scan(
+			&e.TransactionID,
+			&e.Kind,
+			&e.Reference,
+			&e.Description,
+			&e.Amount,
+			&e.BalanceAfter,
+			&e.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			 return nil, 0, utils.NewInternal("failed to scan wallet entry")
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, 0, utils.NewInternal("error iterating wallet entry rows")
+	}
+
+	return entries, total, nil
+}
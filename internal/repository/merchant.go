@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -155,7 +156,7 @@ func (r MerchantRepository) GetAllMercItem(ctx context.Context, merchantId strin
 	
 	query := fmt.Sprintf(`
 		SELECT 
-			id, name, price, imageurl, category, created_at, 
+			id, name, price, imageurl, category, created_at, is_available,
 			COUNT(*) OVER() AS total
 		FROM items 
 		WHERE %s
@@ -181,6 +182,7 @@ func (r MerchantRepository) GetAllMercItem(ctx context.Context, merchantId strin
 			&item.ImageURL,
 			&item.Category,
 			&item.CreateAt,
+			&item.IsAvailable,
 			&total,
 		)
 
@@ -222,6 +224,45 @@ func (r MerchantRepository) GetMerchantById(ctx context.Context, merchantId stri
 	return m, nil
 }
 
+// GetMerchantOwner returns the id of the user who runs the merchant, or nil
+// when nobody has been assigned yet.
+func (r MerchantRepository) GetMerchantOwner(ctx context.Context, merchantId string) (*string, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	var ownerID *string
+	err := r.db.QueryRow(ctx, `SELECT owner_id FROM merchants WHERE id = $1`, merchantId).Scan(&ownerID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, utils.NewNotFound("merchant does not exist")
+		}
+		return nil, utils.NewInternal("failed get merchant owner")
+	}
+
+	return ownerID, nil
+}
+
+func (r MerchantRepository) SetMerchantOwner(ctx context.Context, tx pgx.Tx, merchantId, userId string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE merchants SET owner_id = $2 WHERE id = $1`, merchantId, userId)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return utils.NewNotFound("user does not exist")
+		}
+		return utils.NewInternal("failed set merchant owner")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("merchant does not exist")
+	}
+
+	return nil
+}
+
 func (r MerchantRepository) CreateMerchant(ctx context.Context, tx pgx.Tx, req entities.Merchant) (entities.Merchant, error) {
 	if err := ctx.Err(); err != nil {
 		 return entities.Merchant{}, err
@@ -277,3 +318,77 @@ func (r MerchantRepository) CreateMercItem(ctx context.Context, tx pgx.Tx, req e
 
 	return res, nil
 }
+
+// SetItemAvailability marks an item of merchantId as orderable or not.
+func (r MerchantRepository) SetItemAvailability(ctx context.Context, tx pgx.Tx, merchantId, itemId string, available bool) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE items SET is_available = $3
+		WHERE id = $2 AND merchant_id = $1
+	`, merchantId, itemId, available)
+	if err != nil {
+		 return utils.NewInternal("failed set item availability")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("item does not exist")
+	}
+
+	return nil
+}
+
+func (r MerchantRepository) ReplaceOpeningHours(ctx context.Context, tx pgx.Tx, merchantId string, hours []entities.OpeningHour) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM merchants_opening_hours WHERE merchant_id = $1`, merchantId); err != nil {
+		 return utils.NewInternal("failed to clear opening hours")
+	}
+
+	if len(hours) == 0 {
+		 return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, h := range hours {
+		batch.Queue(`
+			INSERT INTO merchants_opening_hours (merchant_id, day_of_week, opens_at, closes_at)
+			VALUES ($1, $2, $3::time, $4::time)
+		`, merchantId, h.DayOfWeek, h.OpensAt, h.ClosesAt)
+	}
+
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		 return utils.NewBadRequest("invalid opening hours")
+	}
+
+	return nil
+}
+
+// openAtCondition matches merchants that are open on the given day and time of day.
+// Merchants without any opening hours are treated as always open, and a slot whose
+// closing time is not after its opening time runs past midnight into the next day.
+func openAtCondition(merchantCol string, dayIdx, prevDayIdx, timeIdx int) string {
+	return fmt.Sprintf(`(
+		NOT EXISTS (
+			SELECT 1 FROM merchants_opening_hours oh
+			WHERE oh.merchant_id = %[1]s
+		) OR EXISTS (
+			SELECT 1 FROM merchants_opening_hours oh
+			WHERE oh.merchant_id = %[1]s
+			AND (
+				(oh.day_of_week = $%[2]d AND oh.opens_at <= $%[4]d::time AND (oh.closes_at > $%[4]d::time OR oh.closes_at <= oh.opens_at))
+				OR (oh.day_of_week = $%[3]d AND oh.closes_at <= oh.opens_at AND oh.closes_at > $%[4]d::time)
+			)
+		)
+	)`, merchantCol, dayIdx, prevDayIdx, timeIdx)
+}
+
+// openAtArgs returns the day of week, previous day of week and time of day used by openAtCondition.
+func openAtArgs(t time.Time) (int, int, string) {
+	day := int(t.Weekday())
+	return day, (day + 6) % 7, t.Format("15:04:05")
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// activeMerchantOrderStatuses are the sub-orders still waiting on the kitchen.
+var activeMerchantOrderStatuses = []string{
+	entities.MerchantOrderPending,
+	entities.MerchantOrderAccepted,
+	entities.MerchantOrderReady,
+}
+
+type MerchantOrderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMerchantOrderRepository(db *pgxpool.Pool) MerchantOrderRepository {
+	return MerchantOrderRepository{db: db}
+}
+
+// CreateForOrder opens one Pending sub-order for every merchant on the order's estimate.
+func (r MerchantOrderRepository) CreateForOrder(ctx context.Context, tx pgx.Tx, orderID, estimateID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO merchant_orders (order_id, merchant_id)
+		SELECT DISTINCT $1::uuid, merchant_id FROM orders_items WHERE estimate_id = $2
+		ON CONFLICT (order_id, merchant_id) DO NOTHING
+	`, orderID, estimateID)
+	if err != nil {
+		 return utils.NewInternal("failed create merchant orders")
+	}
+
+	return nil
+}
+
+// GetMerchantOrders lists a merchant's queue, oldest first. Sub-orders of
+// scheduled orders stay hidden until the order is released.
+func (r MerchantOrderRepository) GetMerchantOrders(ctx context.Context, filter entities.MerchantOrderFilter) ([]entities.MerchantOrder, int, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, 0, err
+	}
+
+	conditions := []string{"mo.merchant_id = $1", "o.status <> 'Scheduled'"}
+	args := []any{filter.MerchantID}
+	i := 2
+
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("mo.status::text = $%d", i))
+		args = append(args, filter.Status)
+	} else {
+		conditions = append(conditions, fmt.Sprintf("mo.status::text = ANY($%d)", i))
+		args = append(args, activeMerchantOrderStatuses)
+	}
+	i++
+
+	query := fmt.Sprintf(`
+		SELECT
+			mo.id, mo.order_id, mo.merchant_id, mo.status, mo.prep_minutes, mo.accepted_at, mo.ready_by, mo.ready_at,
+			mo.reason, o.scheduled_for, mo.created_at, mo.updated_at,
+			COUNT(*) OVER() AS total
+		FROM merchant_orders mo
+		JOIN orders o ON o.id = mo.order_id
+		WHERE %s
+		ORDER BY mo.created_at ASC
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(conditions, " AND "), i, i+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		 return nil, 0, utils.NewInternal("failed to query merchant orders")
+	}
+	defer rows.Close()
+
+	total := 0
+	orders := make([]entities.MerchantOrder, 0, filter.Limit)
+	index := make(map[string]int, filter.Limit)
+	ids := make([]string, 0, filter.Limit)
+	for rows.Next() {
+		mo := entities.MerchantOrder{Items: make([]entities.MerchantOrderItem, 0)}
+		err := rows.Scan(
+			&mo.ID,
+			&mo.OrderID,
+			&mo.MerchantID,
+			&mo.Status,
+			&mo.PrepMinutes,
+			&mo.AcceptedAt,
+			&mo.ReadyBy,
+			&mo.ReadyAt,
+			&mo.Reason,
+			&mo.ScheduledFor,
+			&mo.CreatedAt,
+			&mo.UpdatedAt,
+			&total,
+		)
+		if err != nil {
+			 return nil, 0, utils.NewInternal("failed to scan merchant order row")
+		}
+
+		index[mo.ID] = len(orders)
+		ids = append(ids, mo.ID)
+		orders = append(orders, mo)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, 0, utils.NewInternal("error iterating merchant order rows")
+	}
+
+	if len(ids) == 0 {
+		 return orders, total, nil
+	}
+
+	itemRows, err := r.db.Query(ctx, `
+		SELECT mo.id, it.id, it.name, oi.quantity, it.price
+		FROM merchant_orders mo
+		JOIN orders o ON o.id = mo.order_id
+		JOIN orders_items oi ON oi.estimate_id = o.estimate_id AND oi.merchant_id = mo.merchant_id
+		JOIN items it ON it.id = oi.merchant_item_id
+		WHERE mo.id = ANY($1)
+		ORDER BY it.name
+	`, ids)
+	if err != nil {
+		 return nil, 0, utils.NewInternal("failed to query merchant order items")
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var moID string
+		var item entities.MerchantOrderItem
+		if err := itemRows.Scan(&moID, &item.ItemID, &item.Name, &item.Quantity, &item.Price); err != nil {
+			 return nil, 0, utils.NewInternal("failed to scan merchant order item row")
+		}
+		orders[index[moID]].Items = append(orders[index[moID]].Items, item)
+	}
+
+	if err := itemRows.Err(); err != nil {
+		 return nil, 0, utils.NewInternal("error iterating merchant order item rows")
+	}
+
+	return orders, total, nil
+}
+
+// GetMerchantOrderForUpdate loads merchantID's share of orderID and locks it
+// until tx ends. Callers lock the parent order first.
+func (r MerchantOrderRepository) GetMerchantOrderForUpdate(ctx context.Context, tx pgx.Tx, orderID, merchantID string) (entities.MerchantOrder, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.MerchantOrder{}, err
+	}
+
+	mo := entities.MerchantOrder{Items: make([]entities.MerchantOrderItem, 0)}
+	err := tx.QueryRow(ctx, `
+		SELECT id, order_id, merchant_id, status, prep_minutes, accepted_at, ready_by, ready_at, reason, created_at, updated_at
+		FROM merchant_orders
+		WHERE order_id = $1 AND merchant_id = $2
+		FOR UPDATE
+	`, orderID, merchantID).Scan(
+		&mo.ID,
+		&mo.OrderID,
+		&mo.MerchantID,
+		&mo.Status,
+		&mo.PrepMinutes,
+		&mo.AcceptedAt,
+		&mo.ReadyBy,
+		&mo.ReadyAt,
+		&mo.Reason,
+		&mo.CreatedAt,
+		&mo.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.MerchantOrder{}, utils.NewNotFound("order does not exist")
+		}
+		return entities.MerchantOrder{}, err
+	}
+
+	return mo, nil
+}
+
+// UpdateMerchantOrder stores mo.Status and mo.Reason. Moving to Accepted stamps
+// accepted_at and starts the ready-by countdown of mo.PrepMinutes from then, and
+// moving to Ready stamps ready_at, all on the database clock. Other moves leave both as they are.
+func (r MerchantOrderRepository) UpdateMerchantOrder(ctx context.Context, tx pgx.Tx, mo entities.MerchantOrder) (entities.MerchantOrder, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.MerchantOrder{}, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		UPDATE merchant_orders SET
+			status = $2,
+			reason = $3,
+			prep_minutes = CASE WHEN $2 = 'Accepted' THEN $4::int ELSE prep_minutes END,
+			accepted_at = CASE WHEN $2 = 'Accepted' THEN CURRENT_TIMESTAMP ELSE accepted_at END,
+			ready_by = CASE WHEN $2 = 'Accepted' THEN CURRENT_TIMESTAMP + make_interval(mins => $4::int) ELSE ready_by END,
+			ready_at = CASE WHEN $2 = 'Ready' THEN CURRENT_TIMESTAMP ELSE ready_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING prep_minutes, accepted_at, ready_by, ready_at, updated_at
+	`, mo.ID, mo.Status, mo.Reason, mo.PrepMinutes).Scan(&mo.PrepMinutes, &mo.AcceptedAt, &mo.ReadyBy, &mo.ReadyAt, &mo.UpdatedAt)
+	if err != nil {
+		 return entities.MerchantOrder{}, utils.NewInternal("failed update merchant order")
+	}
+
+	return mo, nil
+}
+
+// CountMerchantOrders counts the sub-orders of orderID that are in status.
+func (r MerchantOrderRepository) CountMerchantOrders(ctx context.Context, tx pgx.Tx, orderID, status string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	var n int
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM merchant_orders WHERE order_id = $1 AND status::text = $2
+	`, orderID, status).Scan(&n)
+	if err != nil {
+		 return 0, utils.NewInternal("failed count merchant orders")
+	}
+
+	return n, nil
+}
+
+// MoveMerchantOrders moves every sub-order of orderID currently in one of from
+// to status to, keeping the sub-orders in step with their parent order.
+func (r MerchantOrderRepository) MoveMerchantOrders(ctx context.Context, tx pgx.Tx, orderID, to string, from []string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE merchant_orders SET status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE order_id = $1 AND status::text = ANY($3)
+	`, orderID, to, from)
+	if err != nil {
+		 return utils.NewInternal("failed update merchant orders")
+	}
+
+	return nil
+}
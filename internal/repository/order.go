@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OrderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOrderRepository(db *pgxpool.Pool) OrderRepository {
+	return OrderRepository{db: db}
+}
+
+// GetOrderOwner returns the id of the user who placed the order.
+func (r OrderRepository) GetOrderOwner(ctx context.Context, orderID string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		 return "", err
+	}
+
+	var userID string
+	err := r.db.QueryRow(ctx, `
+		SELECT e.user_id
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		WHERE o.id = $1
+	`, orderID).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", utils.NewNotFound("order does not exist")
+		}
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// GetOrderForUpdate loads the order and locks its row until tx ends, so
+// concurrent status changes are applied one after another.
+func (r OrderRepository) GetOrderForUpdate(ctx context.Context, tx pgx.Tx, orderID string) (entities.Order, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Order{}, err
+	}
+
+	ord := entities.Order{}
+	var ageSeconds float64
+	err := tx.QueryRow(ctx, `
+		SELECT
+			o.id, e.user_id, o.estimate_id, o.total_price, o.status, o.scheduled_for, o.created_at, o.updated_at,
+			EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - o.created_at)::float8
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		WHERE o.id = $1
+		FOR UPDATE OF o
+	`, orderID).Scan(
+		&ord.ID,
+		&ord.UserID,
+		&ord.EstimateID,
+		&ord.TotalPrice,
+		&ord.Status,
+		&ord.ScheduledFor,
+		&ord.CreatedAt,
+		&ord.UpdatedAt,
+		&ageSeconds,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Order{}, utils.NewNotFound("order does not exist")
+		}
+		return entities.Order{}, err
+	}
+
+	ord.Age = time.Duration(ageSeconds * float64(time.Second))
+	return ord, nil
+}
+
+// ClaimDueScheduledOrder locks one Scheduled order whose release time has
+// passed. Rows locked by another instance are skipped, so several schedulers can
+// run side by side. ok is false when nothing is due.
+func (r OrderRepository) ClaimDueScheduledOrder(ctx context.Context, tx pgx.Tx) (entities.Order, bool, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Order{}, false, err
+	}
+
+	ord := entities.Order{}
+	err := tx.QueryRow(ctx, `
+		SELECT o.id, e.user_id, o.estimate_id, o.total_price, o.status, o.scheduled_for, o.created_at, o.updated_at
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		WHERE o.status = 'Scheduled' AND o.release_at <= CURRENT_TIMESTAMP
+		ORDER BY o.release_at
+		LIMIT 1
+		FOR UPDATE OF o SKIP LOCKED
+	`).Scan(
+		&ord.ID,
+		&ord.UserID,
+		&ord.EstimateID,
+		&ord.TotalPrice,
+		&ord.Status,
+		&ord.ScheduledFor,
+		&ord.CreatedAt,
+		&ord.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Order{}, false, nil
+		}
+		return entities.Order{}, false, err
+	}
+
+	return ord, true, nil
+}
+
+// UpdateOrderStatus moves the order to change.ToStatus and records the change.
+func (r OrderRepository) UpdateOrderStatus(ctx context.Context, tx pgx.Tx, change entities.OrderStatusChange) (entities.OrderStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE orders SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1
+	`, change.OrderID, change.ToStatus)
+	if err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	return r.InsertStatusHistory(ctx, tx, change)
+}
+
+func (r OrderRepository) InsertStatusHistory(ctx context.Context, tx pgx.Tx, change entities.OrderStatusChange) (entities.OrderStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO order_status_history (order_id, from_status, to_status, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`,
+		change.OrderID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Reason,
+		change.ChangedBy,
+	).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	return change, nil
+}
+
+func (r OrderRepository) GetOrderStatusHistory(ctx context.Context, orderID string) ([]entities.OrderStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, order_id, from_status, to_status, reason, changed_by, created_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY created_at, id
+	`, orderID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query order status history")
+	}
+	defer rows.Close()
+
+	history := make([]entities.OrderStatusChange, 0, 8)
+	for rows.Next() {
+		c := entities.OrderStatusChange{}
+		err := rows.Scan(
+			&c.ID,
+			&c.OrderID,
+			&c.FromStatus,
+			&c.ToStatus,
+			&c.Reason,
+			&c.ChangedBy,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			 return nil, utils.NewInternal("failed to scan order status history row")
+		}
+		history = append(history, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating order status history rows")
+	}
+
+	return history, nil
+}
+
+func (r OrderRepository) InsertCancellation(ctx context.Context, tx pgx.Tx, c entities.OrderCancellation) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO order_cancellations (order_id, reason_code, note, initiated_by, actor_id, previous_status, fee)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		c.OrderID,
+		c.ReasonCode,
+		c.Note,
+		c.InitiatedBy,
+		c.ActorID,
+		c.PreviousStatus,
+		c.Fee,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return utils.NewConflict("order has already been cancelled")
+		}
+		return utils.NewInternal("failed record order cancellation")
+	}
+
+	return nil
+}
+
+func (r OrderRepository) InsertRefund(ctx context.Context, tx pgx.Tx, ref entities.Refund) (entities.Refund, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Refund{}, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO refunds (order_id, payment_id, amount)
+		VALUES ($1, $2, $3)
+		RETURNING id, status, created_at, updated_at
+	`, ref.OrderID, ref.PaymentID, ref.Amount).Scan(&ref.ID, &ref.Status, &ref.CreatedAt, &ref.UpdatedAt)
+	if err != nil {
+		 return entities.Refund{}, utils.NewInternal("failed create refund")
+	}
+
+	return ref, nil
+}
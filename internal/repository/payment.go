@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PaymentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPaymentRepository(db *pgxpool.Pool) PaymentRepository {
+	return PaymentRepository{db: db}
+}
+
+const paymentColumns = `
+	id, order_id, user_id, provider, provider_ref, amount, status, failure_reason, created_at, updated_at
+`
+
+func scanPayment(row pgx.Row) (entities.Payment, error) {
+	p := entities.Payment{}
+	err := row.Scan(
+		&p.ID,
+		&p.OrderID,
+		&p.UserID,
+		&p.Provider,
+		&p.ProviderRef,
+		&p.Amount,
+		&p.Status,
+		&p.FailureReason,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+
+	return p, err
+}
+
+func (r PaymentRepository) CreatePayment(ctx context.Context, tx pgx.Tx, p entities.Payment) (entities.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Payment{}, err
+	}
+
+	res, err := scanPayment(tx.QueryRow(ctx, `
+		INSERT INTO payments (order_id, user_id, provider, provider_ref, amount, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+paymentColumns,
+		p.OrderID, p.UserID, p.Provider, p.ProviderRef, p.Amount, p.Status,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entities.Payment{}, utils.NewConflict("order already has a payment")
+		}
+		return entities.Payment{}, utils.NewInternal("failed create payment")
+	}
+
+	return res, nil
+}
+
+// GetPaymentByOrderForUpdate locks the payment of an order until tx ends.
+func (r PaymentRepository) GetPaymentByOrderForUpdate(ctx context.Context, tx pgx.Tx, orderID string) (entities.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Payment{}, err
+	}
+
+	p, err := scanPayment(tx.QueryRow(ctx, `
+		SELECT `+paymentColumns+` FROM payments WHERE order_id = $1 FOR UPDATE
+	`, orderID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Payment{}, utils.NewNotFound("payment not found")
+		}
+		return entities.Payment{}, utils.NewInternal("failed get payment")
+	}
+
+	return p, nil
+}
+
+// GetPaymentByRef finds the payment a provider knows as ref. It takes no lock;
+// lock the order and then the payment before changing it.
+func (r PaymentRepository) GetPaymentByRef(ctx context.Context, tx pgx.Tx, provider, ref string) (entities.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Payment{}, err
+	}
+
+	p, err := scanPayment(tx.QueryRow(ctx, `
+		SELECT `+paymentColumns+` FROM payments WHERE provider = $1 AND provider_ref = $2
+	`, provider, ref))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Payment{}, utils.NewNotFound("payment not found")
+		}
+		return entities.Payment{}, utils.NewInternal("failed get payment")
+	}
+
+	return p, nil
+}
+
+// UpdatePayment stores the provider reference, status and failure reason of p.
+func (r PaymentRepository) UpdatePayment(ctx context.Context, tx pgx.Tx, p entities.Payment) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		UPDATE payments
+		SET provider_ref = COALESCE($2, provider_ref), status = $3, failure_reason = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, p.ID, p.ProviderRef, p.Status, p.FailureReason)
+	if err != nil {
+		 return utils.NewInternal("failed update payment")
+	}
+
+	return nil
+}
+
+// InsertWebhookEvent records a webhook delivery. It reports false when the
+// delivery was already recorded, in which case it must not be applied again.
+func (r PaymentRepository) InsertWebhookEvent(ctx context.Context, tx pgx.Tx, e entities.WebhookEvent) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		 return false, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO webhook_events (provider, event_id, payload)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, event_id) DO NOTHING
+	`, e.Provider, e.EventID, e.Payload)
+	if err != nil {
+		 return false, utils.NewInternal("failed record webhook event")
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// ClaimRefunds takes up to limit refunds of succeeded payments that are due to
+// be sent to the provider, oldest first: new ones and failed ones whose retry
+// is due. Each is leased by moving its next_attempt_at that far ahead, so other
+// processors skip it until then; a processor that dies mid-way leaves it to be
+// picked up again once the lease runs out.
+func (r PaymentRepository) ClaimRefunds(ctx context.Context, limit int, lease time.Duration) ([]entities.PendingRefund, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		WITH due AS (
+			SELECT rf.id
+			FROM refunds rf
+			JOIN payments p ON p.id = rf.payment_id
+			WHERE (
+				(rf.status = 'Pending' AND (rf.next_attempt_at IS NULL OR rf.next_attempt_at <= CURRENT_TIMESTAMP))
+				OR (rf.status = 'Failed' AND rf.next_attempt_at <= CURRENT_TIMESTAMP)
+			)
+			AND p.status = 'Succeeded'
+			ORDER BY rf.created_at
+			LIMIT $1
+			FOR UPDATE OF rf SKIP LOCKED
+		), claimed AS (
+			UPDATE refunds rf
+			SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+			FROM due
+			WHERE rf.id = due.id
+			RETURNING rf.id, rf.payment_id, rf.amount, rf.attempts, rf.created_at
+		)
+		SELECT
+			c.id, p.id, p.user_id, p.provider, COALESCE(p.provider_ref, ''), c.amount, c.attempts,
+			c.amount + COALESCE((
+				SELECT SUM(done.amount) FROM refunds done
+				WHERE done.payment_id = p.id AND done.status = 'Completed'
+			), 0) >= p.amount
+		FROM claimed c
+		JOIN payments p ON p.id = c.payment_id
+		ORDER BY c.created_at
+	`, limit, lease.Seconds())
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query pending refunds")
+	}
+	defer rows.Close()
+
+	refunds := make([]entities.PendingRefund, 0, limit)
+	for rows.Next() {
+		rf := entities.PendingRefund{}
+		err := rows.Scan(
+			&rf.RefundID,
+			&rf.PaymentID,
+			&rf.UserID,
+			&rf.Provider,
+			&rf.ProviderRef,
+			&rf.Amount,
+			&rf.Attempts,
+			&rf.FullRefund,
+		)
+		if err != nil {
+			 return nil, utils.NewInternal("failed to scan pending refund")
+		}
+		refunds = append(refunds, rf)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating pending refund rows")
+	}
+
+	return refunds, nil
+}
+
+// CompleteRefund stores the provider's answer for a refund and, when it went
+// through and covers the rest of the payment, marks the payment refunded. A
+// failed refund is sent again after retryIn, or never when retryIn is zero.
+// A refund that was completed in the meantime is a conflict and left as is.
+func (r PaymentRepository) CompleteRefund(ctx context.Context, tx pgx.Tx, rf entities.Refund, fullRefund bool, retryIn time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE refunds
+		SET status = $2, provider_ref = $3, failure_reason = $4, attempts = attempts + 1,
+			next_attempt_at = CASE WHEN $2 = 'Failed' AND $5::float8 > 0 THEN CURRENT_TIMESTAMP + make_interval(secs => $5) END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('Pending', 'Failed')
+	`, rf.ID, rf.Status, rf.ProviderRef, rf.FailureReason, retryIn.Seconds())
+	if err != nil {
+		 return utils.NewInternal("failed update refund")
+	}
+	if tag.RowsAffected() == 0 {
+		 return utils.NewConflict("refund has already been completed")
+	}
+
+	if rf.Status == entities.RefundCompleted && fullRefund {
+		_, err := tx.Exec(ctx, `
+			UPDATE payments SET status = 'Refunded', updated_at = CURRENT_TIMESTAMP WHERE id = $1
+		`, rf.PaymentID)
+		if err != nil {
+			 return utils.NewInternal("failed update payment")
+		}
+	}
+
+	return nil
+}
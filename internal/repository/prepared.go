@@ -58,17 +58,51 @@ func PrepareStatements(ctx context.Context, pool *pgxpool.Pool) error {
 			SELECT id, name, imageurl, category,
 		       ST_X(location::geometry) AS lon,
 		       ST_Y(location::geometry) AS lat,
-		       created_at
+		       created_at, prep_minutes
       FROM merchants WHERE id = ANY($1)
     `,
 		"getAllMercItemByIDs": `
-			SELECT id, merchant_id, name, price, imageurl, category, created_at
+			SELECT id, merchant_id, name, price, imageurl, category, created_at, is_available
       FROM items
       WHERE id = ANY($1)
     `,
-		"getEstimateDataByID": `SELECT id, user_id, created_at FROM estimates WHERE id = $1`,
-		"createEstimateBatch": `INSERT INTO estimates (user_id) VALUES ($1) RETURNING id`,
-		"createOrderFromEsID": `INSERT INTO orders (estimate_id) VALUES ($1)`,
+		"getEstimateDataByID": `
+			SELECT
+				e.id, e.user_id, e.total_price, COALESCE(e.price_breakdown, '{}'::jsonb),
+				e.promotion_id, e.discount, e.created_at, e.expires_at,
+				e.scheduled_for, e.release_at,
+				COALESCE(e.start_merchant_id::text, ''), COALESCE(e.route_distance_km, 0),
+				COALESCE(e.estimated_delivery_minutes, 0), COALESCE(e.travel_minutes, e.estimated_delivery_minutes, 0),
+				e.delivery_address_id, COALESCE(ST_Y(e.delivery_location::geometry), 0), COALESCE(ST_X(e.delivery_location::geometry), 0),
+				e.delivery_building, e.delivery_floor, e.delivery_notes,
+				COALESCE(e.quote_input, 'null'::jsonb), COALESCE(e.quote_route, '[]'::jsonb),
+				COALESCE(e.quote_eta, '{}'::jsonb), COALESCE(e.algorithm_version, ''),
+				e.expires_at <= CURRENT_TIMESTAMP AS expired,
+				EXISTS (SELECT 1 FROM orders o WHERE o.estimate_id = e.id) AS used
+			FROM estimates e WHERE e.id = $1
+		`,
+		"createEstimateBatch": `
+			INSERT INTO estimates (user_id, total_price, price_breakdown, promotion_id, discount, expires_at, scheduled_for, release_at, start_merchant_id, route_distance_km, estimated_delivery_minutes, travel_minutes,
+			delivery_address_id, delivery_location, delivery_building, delivery_floor, delivery_notes,
+			quote_input, quote_route, quote_eta, algorithm_version)
+			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + make_interval(secs => $6), $7, $8, $9, $10, $11, $12,
+			$13, ST_SetSRID(ST_MakePoint($14, $15), 4326)::geography, $16, $17, $18,
+			$19, $20, $21, $22)
+			RETURNING id
+		`,
+		"createOrderFromEsID": `
+			INSERT INTO orders (
+				estimate_id, total_price, status, scheduled_for, release_at,
+				delivery_address_id, delivery_location, delivery_building, delivery_floor, delivery_notes
+			)
+			SELECT
+				id, total_price,
+				CASE WHEN scheduled_for IS NULL THEN 'Placed' ELSE 'Scheduled' END::order_statuses_enum,
+				scheduled_for, release_at,
+				delivery_address_id, delivery_location, delivery_building, delivery_floor, delivery_notes
+			FROM estimates WHERE id = $1 AND user_id = $2
+			RETURNING id, total_price, status, scheduled_for
+		`,
 	}
 
 	for name, sql := range statements {
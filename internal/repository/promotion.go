@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PromotionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPromotionRepository(db *pgxpool.Pool) PromotionRepository {
+	return PromotionRepository{db: db}
+}
+
+const promotionColumns = `
+	id, code, description, type, value, max_discount, min_basket,
+	global_limit, per_user_limit, used_count,
+	merchant_ids::text[], merchant_categories, product_categories,
+	starts_at, ends_at, is_active, created_at,
+	starts_at <= CURRENT_TIMESTAMP AS started,
+	ends_at <= CURRENT_TIMESTAMP AS ended
+`
+
+func scanPromotion(row pgx.Row) (entities.Promotion, error) {
+	p := entities.Promotion{}
+	err := row.Scan(
+		&p.ID,
+		&p.Code,
+		&p.Description,
+		&p.Type,
+		&p.Value,
+		&p.MaxDiscount,
+		&p.MinBasket,
+		&p.GlobalLimit,
+		&p.PerUserLimit,
+		&p.UsedCount,
+		&p.MerchantIDs,
+		&p.MerchantCategories,
+		&p.ProductCategories,
+		&p.StartsAt,
+		&p.EndsAt,
+		&p.IsActive,
+		&p.CreatedAt,
+		&p.Started,
+		&p.Ended,
+	)
+
+	return p, err
+}
+
+func (r PromotionRepository) CreatePromotion(ctx context.Context, tx pgx.Tx, p entities.Promotion) (entities.Promotion, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Promotion{}, err
+	}
+
+	query := `
+		INSERT INTO promotions (
+			code, description, type, value, max_discount, min_basket,
+			global_limit, per_user_limit,
+			merchant_ids, merchant_categories, product_categories,
+			starts_at, ends_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::uuid[], $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	res := entities.Promotion{}
+	err := tx.QueryRow(ctx, query,
+		p.Code,
+		p.Description,
+		p.Type,
+		p.Value,
+		p.MaxDiscount,
+		p.MinBasket,
+		p.GlobalLimit,
+		p.PerUserLimit,
+		p.MerchantIDs,
+		p.MerchantCategories,
+		p.ProductCategories,
+		p.StartsAt,
+		p.EndsAt,
+	).Scan(&res.ID)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entities.Promotion{}, utils.NewConflict("promo code already exists")
+		}
+		return entities.Promotion{}, utils.NewInternal("failed create promotion")
+	}
+
+	return res, nil
+}
+
+func (r PromotionRepository) GetAllPromotion(ctx context.Context, filter entities.PromotionFilter) ([]entities.Promotion, int, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, 0, err
+	}
+
+	conditions := []string{"1=1"}
+	args := []any{}
+	i := 1
+
+	if filter.Code != "" {
+		conditions = append(conditions, fmt.Sprintf("code ILIKE $%d", i))
+		args = append(args, "%"+filter.Code+"%")
+		i++
+	}
+
+	if filter.IsActive != nil {
+		conditions = append(conditions, fmt.Sprintf("is_active = $%d", i))
+		args = append(args, *filter.IsActive)
+		i++
+	}
+
+	limit, offset := filter.Limit, filter.Offset
+	if limit <= 0 {
+		 limit = 5
+	}
+
+	if offset < 0 {
+		 offset = 0
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER() AS total
+		FROM promotions
+		WHERE %s
+		ORDER BY created_at DESC, id
+		LIMIT %d OFFSET %d
+	`, promotionColumns, strings.Join(conditions, " AND "), limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		 return nil, 0, utils.NewInternal("failed to query promotions")
+	}
+	defer rows.Close()
+
+	total := 0
+	promotions := make([]entities.Promotion, 0)
+	for rows.Next() {
+		p := entities.Promotion{}
+		err := rows.Scan(
+			&p.ID, &p.Code, &p.Description, &p.Type, &p.Value, &p.MaxDiscount, &p.MinBasket,
+			&p.GlobalLimit, &p.PerUserLimit, &p.UsedCount,
+			&p.MerchantIDs, &p.MerchantCategories, &p.ProductCategories,
+			&p.StartsAt, &p.EndsAt, &p.IsActive, &p.CreatedAt, &p.Started, &p.Ended,
+			&total,
+		)
+
+		if err != nil {
+			 return nil, 0, utils.NewInternal("failed to scan promotion")
+		}
+
+		promotions = append(promotions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, 0, utils.NewInternal("error iterating promotion rows")
+	}
+
+	return promotions, total, nil
+}
+
+func (r PromotionRepository) SetPromotionActive(ctx context.Context, tx pgx.Tx, id string, active bool) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	tag, err := tx.Exec(ctx, `UPDATE promotions SET is_active = $2 WHERE id = $1`, id, active)
+	if err != nil {
+		 return utils.NewInternal("failed update promotion")
+	}
+
+	if tag.RowsAffected() == 0 {
+		 return utils.NewNotFound("promotion not found")
+	}
+
+	return nil
+}
+
+func (r PromotionRepository) GetPromotionByCode(ctx context.Context, code string) (entities.Promotion, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Promotion{}, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM promotions WHERE UPPER(code) = UPPER($1)`, promotionColumns)
+
+	p, err := scanPromotion(r.db.QueryRow(ctx, query, code))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Promotion{}, utils.NewNotFound("promo code not found")
+		}
+		return entities.Promotion{}, utils.NewInternal("failed get promotion")
+	}
+
+	return p, nil
+}
+
+func (r PromotionRepository) CountUserRedemptions(ctx context.Context, promotionID, userID string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM promotion_redemptions
+		WHERE promotion_id = $1 AND user_id = $2
+	`, promotionID, userID).Scan(&count)
+
+	if err != nil {
+		 return 0, utils.NewInternal("failed count promotion redemptions")
+	}
+
+	return count, nil
+}
+
+// RedeemPromotion consumes one use of the promotion inside tx. Incrementing
+// used_count locks the promotion row, so concurrent redemptions of the same code
+// are serialized and the per-user count below cannot race.
+func (r PromotionRepository) RedeemPromotion(ctx context.Context, tx pgx.Tx, red entities.Redemption) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	var perUserLimit int
+	err := tx.QueryRow(ctx, `
+		UPDATE promotions
+		SET used_count = used_count + 1
+		WHERE id = $1
+			AND is_active
+			AND starts_at <= CURRENT_TIMESTAMP
+			AND ends_at > CURRENT_TIMESTAMP
+			AND (global_limit = 0 OR used_count < global_limit)
+		RETURNING per_user_limit
+	`, red.PromotionID).Scan(&perUserLimit)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return utils.NewConflict("promo code is no longer available")
+		}
+		return utils.NewInternal("failed redeem promotion")
+	}
+
+	if perUserLimit > 0 {
+		var used int
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM promotion_redemptions
+			WHERE promotion_id = $1 AND user_id = $2
+		`, red.PromotionID, red.UserID).Scan(&used)
+
+		if err != nil {
+			 return utils.NewInternal("failed count promotion redemptions")
+		}
+
+		if used >= perUserLimit {
+			 return utils.NewConflict("promo code usage limit reached")
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO promotion_redemptions (promotion_id, user_id, order_id, estimate_id, discount)
+		VALUES ($1, $2, $3, $4, $5)
+	`, red.PromotionID, red.UserID, red.OrderID, red.EstimateID, red.Discount)
+
+	if err != nil {
+		 return utils.NewInternal("failed record promotion redemption")
+	}
+
+	return nil
+}
+
+// ReleaseRedemption gives back the promotion use consumed by orderID, if any.
+func (r PromotionRepository) ReleaseRedemption(ctx context.Context, tx pgx.Tx, orderID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		WITH released AS (
+			DELETE FROM promotion_redemptions WHERE order_id = $1
+			RETURNING promotion_id
+		)
+		UPDATE promotions p
+		SET used_count = GREATEST(p.used_count - 1, 0)
+		FROM released
+		WHERE p.id = released.promotion_id
+	`, orderID)
+
+	if err != nil {
+		 return utils.NewInternal("failed release promotion redemption")
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -20,6 +21,19 @@ func NewPurchaseRepository(db *pgxpool.Pool) PurchaseRepository {
 	return PurchaseRepository{db: db}
 }
 
+const (
+	defaultNearbyRadius = 3000.0
+	nearbySpeedKmh      = 40.0
+)
+
+var nearbySortOrders = map[string]string{
+	"distance":   "distance ASC, m.id ASC",
+	"rating":     "m.rating_avg DESC, m.rating_count DESC, distance ASC, m.id ASC",
+	"popularity": "popularity DESC, distance ASC, m.id ASC",
+	"newest":     "m.created_at DESC, m.id ASC",
+	"eta":        "eta ASC, distance ASC, m.id ASC",
+}
+
 func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.MerchantNearbyFilter) ([]entities.MerchantWithItems, int, error) {
 	if err := ctx.Err(); err != nil {
 		 return nil, 0, err
@@ -34,10 +48,22 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 		"MerchandiseRestaurant": true, 
 	}
 
+	validProductEnums := map[string]bool{
+		"Beverage":   true,
+		"Food":       true,
+		"Snack":      true,
+		"Condiments": true,
+		"Additions":  true,
+	}
+
 	if f.MerchantCategory != "" && !validEnums[f.MerchantCategory] {
 		return []entities.MerchantWithItems{}, 0, nil
 	}
 
+	if f.ProductCategory != "" && !validProductEnums[f.ProductCategory] {
+		return []entities.MerchantWithItems{}, 0, nil
+	}
+
 	conds := []string{}
 	args := []any{}
 	i := 1
@@ -48,10 +74,15 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 	latIdx := i + 1
 	i += 2
 
-	// radius 3km filter
+	// radius filter, never wider than the 3km delivery radius
+	radius := defaultNearbyRadius
+	if f.MaxDistance > 0 && f.MaxDistance < radius {
+		 radius = f.MaxDistance
+	}
+
 	conds = append(conds, fmt.Sprintf(
-		"ST_DWithin(m.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, 3000)",
-		lonIdx, latIdx,
+		"ST_DWithin(m.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, %f)",
+		lonIdx, latIdx, radius,
 	))
 
 	if f.MerchantID != "" {
@@ -79,6 +110,40 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 		i++
 	}
 
+	// item level filters, applied to both the merchant filter and the returned items
+	itemConds, itemArgs := nearbyItemConditions(f, i)
+	if len(itemConds) > 0 {
+		conds = append(conds, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM items it
+			WHERE it.merchant_id = m.id
+			AND it.is_available
+			AND %s
+		)`, strings.Join(itemConds, " AND ")))
+		args = append(args, itemArgs...)
+		i += len(itemArgs)
+	}
+
+	if f.OpenNow {
+		day, prevDay, at := openAtArgs(f.OpenAt)
+		conds = append(conds, openAtCondition("m.id", i, i+1, i+2))
+		args = append(args, day, prevDay, at)
+		i += 3
+	}
+
+	// favorite merchants, or merchants with a favorite item
+	if f.FavoritesOnly {
+		conds = append(conds, fmt.Sprintf(`(
+			EXISTS (SELECT 1 FROM favorite_merchants fm WHERE fm.merchant_id = m.id AND fm.user_id = $%d)
+			OR EXISTS (
+				SELECT 1 FROM favorite_items fi
+				JOIN items it ON it.id = fi.item_id
+				WHERE it.merchant_id = m.id AND fi.user_id = $%d
+			)
+		)`, i, i))
+		args = append(args, f.UserID)
+		i++
+	}
+
 	where := ""
 	if len(conds) > 0 {
 		where = "WHERE " + strings.Join(conds, " AND ")
@@ -94,6 +159,21 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 		 offset = 0
 	}
 
+	orderBy, ok := nearbySortOrders[f.SortBy]
+	if !ok {
+		 orderBy = nearbySortOrders["distance"]
+	}
+
+	popularity := "0"
+	if f.SortBy == "popularity" {
+		popularity = `(
+			SELECT COUNT(DISTINCT od.id)
+			FROM orders_items oi
+			JOIN orders od ON od.estimate_id = oi.estimate_id
+			WHERE oi.merchant_id = m.id
+		)`
+	}
+
 	// count total merchants
 	queryCount := fmt.Sprintf(`
 		SELECT COUNT(*)
@@ -112,20 +192,30 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 	}
 
 	queryMerchants := fmt.Sprintf(`
-		SELECT
-			m.id::text,
-			m.name,
-			m.category,
-			m.imageurl,
-			ST_Y(m.location::geometry) AS lat,
-			ST_X(m.location::geometry) AS lon,
-			m.created_at,
-			ST_Distance(m.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography) as distance
-		FROM merchants m
-		%s
-		ORDER BY distance
+		SELECT *
+		FROM (
+			SELECT
+				m.id,
+				m.name,
+				m.category,
+				m.imageurl,
+				ST_Y(m.location::geometry) AS lat,
+				ST_X(m.location::geometry) AS lon,
+				m.created_at,
+				m.rating_avg,
+				m.rating_count,
+				ST_Distance(m.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography) AS distance,
+				m.prep_minutes,
+				%s AS popularity
+			FROM merchants m
+			%s
+		) m
+		CROSS JOIN LATERAL (
+			SELECT m.distance / 1000 / %f * 60 + m.prep_minutes AS eta
+		) e
+		ORDER BY %s
 		LIMIT %d OFFSET %d
-	`, lonIdx, latIdx, where, limit, offset)
+	`, lonIdx, latIdx, popularity, where, nearbySpeedKmh, orderBy, limit, offset)
 
 	rows, err := r.db.Query(ctx, queryMerchants, args...)
 	if err != nil {
@@ -136,8 +226,12 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 	merchants := make([]entities.Merchant, 0)
 	for rows.Next() {
 		var m entities.Merchant
-		var distance float64
-		if err := rows.Scan(&m.ID, &m.Name, &m.Category, &m.ImageURL, &m.Location.Lat, &m.Location.Lon, &m.CreatedAt, &distance); err != nil {
+		var distance, eta float64
+		var prepMinutes, popularity int
+		if err := rows.Scan(
+			&m.ID, &m.Name, &m.Category, &m.ImageURL, &m.Location.Lat, &m.Location.Lon, &m.CreatedAt,
+			&m.RatingAvg, &m.RatingCount, &distance, &prepMinutes, &popularity, &eta,
+		); err != nil {
 			return nil, 0, fmt.Errorf("scan merchant failed: %w", err)
 		}
 		merchants = append(merchants, m)
@@ -157,14 +251,21 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 		mmap[m.ID] = &entities.MerchantWithItems{Merchant: m, Items: []entities.MercItem{}}
 	}
 
-	itemQuery := `
-		SELECT id::text, merchant_id::text, name, category, price, imageurl, created_at
-		FROM items
-		WHERE merchant_id = ANY($1)
-		ORDER BY created_at DESC
-	`
+	itemConds, itemArgs = nearbyItemConditions(f, 2)
+	itemWhere := ""
+	if len(itemConds) > 0 {
+		itemWhere = "AND " + strings.Join(itemConds, " AND ")
+	}
+
+	itemQuery := fmt.Sprintf(`
+		SELECT it.id::text, it.merchant_id::text, it.name, it.category, it.price, it.imageurl, it.created_at,
+			it.rating_avg, it.rating_count
+		FROM items it
+		WHERE it.merchant_id = ANY($1) AND it.is_available %s
+		ORDER BY it.created_at DESC, it.id ASC
+	`, itemWhere)
 
-	itemRows, err := r.db.Query(ctx, itemQuery, ids)
+	itemRows, err := r.db.Query(ctx, itemQuery, append([]any{ids}, itemArgs...)...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("query items failed: %w", err)
 	}
@@ -172,7 +273,7 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 
 	for itemRows.Next() {
 		var it entities.MercItem
-		if err := itemRows.Scan(&it.ID, &it.MerchantID, &it.Name, &it.Category, &it.Price, &it.ImageURL, &it.CreatedAt); err != nil {
+		if err := itemRows.Scan(&it.ID, &it.MerchantID, &it.Name, &it.Category, &it.Price, &it.ImageURL, &it.CreatedAt, &it.RatingAvg, &it.RatingCount); err != nil {
 			return nil, 0, fmt.Errorf("scan item failed: %w", err)
 		}
 		if m, ok := mmap[it.MerchantID]; ok {
@@ -192,6 +293,33 @@ func (r PurchaseRepository) GetNearbyMerchants(ctx context.Context, f entities.M
 	return results, total, nil
 }
 
+// nearbyItemConditions builds the item category and price range conditions, numbering
+// placeholders from start so they can be embedded in either the merchant or the item query.
+func nearbyItemConditions(f entities.MerchantNearbyFilter, start int) ([]string, []any) {
+	conds := []string{}
+	args := []any{}
+	i := start
+
+	if f.ProductCategory != "" {
+		conds = append(conds, fmt.Sprintf("it.category = $%d", i))
+		args = append(args, f.ProductCategory)
+		i++
+	}
+
+	if f.MinPrice > 0 {
+		conds = append(conds, fmt.Sprintf("it.price >= $%d", i))
+		args = append(args, f.MinPrice)
+		i++
+	}
+
+	if f.MaxPrice > 0 {
+		conds = append(conds, fmt.Sprintf("it.price <= $%d", i))
+		args = append(args, f.MaxPrice)
+	}
+
+	return conds, args
+}
+
 func (r PurchaseRepository) GetAllMerchantByIDs(ctx context.Context, ids []string) ([]entities.Merchant, error) {
 	if err := ctx.Err(); err != nil {
 		 return nil, err
@@ -205,7 +333,7 @@ func (r PurchaseRepository) GetAllMerchantByIDs(ctx context.Context, ids []strin
 		SELECT id, name, imageurl, category,
 		       ST_X(location::geometry) AS lon,
 		       ST_Y(location::geometry) AS lat,
-		       created_at
+		       created_at, prep_minutes
 		FROM merchants WHERE id = ANY($1)
 	`, ids)
 	if err != nil {
@@ -224,6 +352,7 @@ func (r PurchaseRepository) GetAllMerchantByIDs(ctx context.Context, ids []strin
 			&mrc.Location.Lon,
 			&mrc.Location.Lat,
 			&mrc.CreatedAt,
+			&mrc.PrepMinutes,
 		) 
 
 		if err != nil {
@@ -250,7 +379,7 @@ func (r PurchaseRepository) GetAllMercItemByIDs(ctx context.Context, ids []strin
 	}
 
 	rows, err := r.db.Query(ctx, `
-		SELECT id, merchant_id, name, price, imageurl, category, created_at
+		SELECT id, merchant_id, name, price, imageurl, category, created_at, is_available
 		FROM items
 		WHERE id = ANY($1)
 	`, ids)
@@ -270,6 +399,7 @@ func (r PurchaseRepository) GetAllMercItemByIDs(ctx context.Context, ids []strin
 			&itm.ImageURL,
 			&itm.Category,
 			&itm.CreatedAt,
+			&itm.IsAvailable,
 		); 
 
 		if err != nil {
@@ -291,10 +421,51 @@ func (r PurchaseRepository) GetEstimateDataByID(ctx context.Context, id string)
 		 return entities.Estimate{}, err
 	}
 
-	row := r.db.QueryRow(ctx, `SELECT id, user_id, created_at FROM estimates WHERE id = $1`, id)
+	row := r.db.QueryRow(ctx, `
+		SELECT
+			e.id, e.user_id, e.total_price, COALESCE(e.price_breakdown, '{}'::jsonb),
+			e.promotion_id, e.discount, e.created_at, e.expires_at,
+			e.scheduled_for, e.release_at,
+			COALESCE(e.start_merchant_id::text, ''), COALESCE(e.route_distance_km, 0),
+			COALESCE(e.estimated_delivery_minutes, 0), COALESCE(e.travel_minutes, e.estimated_delivery_minutes, 0),
+			e.delivery_address_id, COALESCE(ST_Y(e.delivery_location::geometry), 0), COALESCE(ST_X(e.delivery_location::geometry), 0),
+			e.delivery_building, e.delivery_floor, e.delivery_notes,
+			COALESCE(e.quote_input, 'null'::jsonb), COALESCE(e.quote_route, '[]'::jsonb),
+			COALESCE(e.quote_eta, '{}'::jsonb), COALESCE(e.algorithm_version, ''),
+			e.expires_at <= CURRENT_TIMESTAMP AS expired,
+			EXISTS (SELECT 1 FROM orders o WHERE o.estimate_id = e.id) AS used
+		FROM estimates e WHERE e.id = $1
+	`, id)
 
 	est := entities.Estimate{}
-	err := row.Scan(&est.ID, &est.UserID, &est.CreatedAt)
+	err := row.Scan(
+		&est.ID,
+		&est.UserID,
+		&est.TotalPrice,
+		&est.PriceBreakdown,
+		&est.PromotionID,
+		&est.Discount,
+		&est.CreatedAt,
+		&est.ExpiresAt,
+		&est.ScheduledFor,
+		&est.ReleaseAt,
+		&est.StartMerchantID,
+		&est.RouteDistanceKm,
+		&est.EstimatedMinutes,
+		&est.TravelMinutes,
+		&est.Delivery.AddressID,
+		&est.Delivery.Location.Lat,
+		&est.Delivery.Location.Lon,
+		&est.Delivery.Building,
+		&est.Delivery.Floor,
+		&est.Delivery.Notes,
+		&est.Quote.Input,
+		&est.Quote.Route,
+		&est.Quote.ETA,
+		&est.Quote.AlgorithmVersion,
+		&est.Expired,
+		&est.Used,
+	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return entities.Estimate{}, utils.NewNotFound("estimates not found")
@@ -306,19 +477,30 @@ func (r PurchaseRepository) GetEstimateDataByID(ctx context.Context, id string)
 	return est, nil
 }
 
-func (r PurchaseRepository) CreateEstimateBatch(ctx context.Context, tx pgx.Tx, est entities.Estimate, items []entities.OrderItem) (string, error) {
+// CreateEstimateBatch stores est with its items and returns it with the id and
+// the expiry the database assigned, ttl from now on its clock.
+func (r PurchaseRepository) CreateEstimateBatch(ctx context.Context, tx pgx.Tx, est entities.Estimate, items []entities.OrderItem, ttl time.Duration) (entities.Estimate, error) {
 	if err := ctx.Err(); err != nil {
-		 return "", err
+		 return entities.Estimate{}, err
 	}
 
 	if len(items) == 0 {
-		 return "", nil
-	}
-
-	var estimateID string
-	err := tx.QueryRow(ctx, `INSERT INTO estimates (user_id) VALUES ($1) RETURNING id`, est.UserID).Scan(&estimateID)
+		 return entities.Estimate{}, nil
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO estimates (user_id, total_price, price_breakdown, promotion_id, discount, expires_at, scheduled_for, release_at, start_merchant_id, route_distance_km, estimated_delivery_minutes, travel_minutes,
+			delivery_address_id, delivery_location, delivery_building, delivery_floor, delivery_notes,
+			quote_input, quote_route, quote_eta, algorithm_version)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + make_interval(secs => $6), $7, $8, $9, $10, $11, $12,
+			$13, ST_SetSRID(ST_MakePoint($14, $15), 4326)::geography, $16, $17, $18,
+			$19, $20, $21, $22)
+		RETURNING id, expires_at
+	`, est.UserID, est.TotalPrice, est.PriceBreakdown, est.PromotionID, est.Discount, ttl.Seconds(), est.ScheduledFor, est.ReleaseAt, est.StartMerchantID, est.RouteDistanceKm, est.EstimatedMinutes, est.TravelMinutes,
+		est.Delivery.AddressID, est.Delivery.Location.Lon, est.Delivery.Location.Lat, est.Delivery.Building, est.Delivery.Floor, est.Delivery.Notes,
+		est.Quote.Input, est.Quote.Route, est.Quote.ETA, est.Quote.AlgorithmVersion).Scan(&est.ID, &est.ExpiresAt)
 	if err != nil {
-		 return "", utils.NewInternal("failed to insert estimate")
+		 return entities.Estimate{}, utils.NewInternal("failed to insert estimate")
 	}
 
 	batch := &pgx.Batch{}
@@ -328,37 +510,181 @@ func (r PurchaseRepository) CreateEstimateBatch(ctx context.Context, tx pgx.Tx,
 				estimate_id, 
 				merchant_id, 
 				merchant_item_id, 
-				quantity
+				quantity,
+				unit_price
 			)
-			VALUES ($1, $2, $3, $4)
+			VALUES ($1, $2, $3, $4, $5)
 		`, 
-			estimateID, 
+			est.ID, 
 			it.MerchantID, 
 			it.MerchantItemID, 
 			it.Quantity,
+			it.UnitPrice,
 		)
 	}
 
 	br := tx.SendBatch(ctx, batch)
 	defer br.Close()
 	if err := br.Close(); err != nil {
-		return "", utils.NewInternal("failed to batch insert order items")
+		return entities.Estimate{}, utils.NewInternal("failed to batch insert order items")
 	}
 
-	return estimateID, nil
+	return est, nil
 }
 
-func (r PurchaseRepository) CreateOrderFromEsID(ctx context.Context, tx pgx.Tx, order entities.Order) error {
+// CreateOrderFromEsID copies the quoted total and schedule from the estimate, so
+// the order is charged exactly what the customer saw on the estimate. Scheduled
+// estimates start out Scheduled instead of Placed. The estimate must belong to
+// order.UserID.
+func (r PurchaseRepository) CreateOrderFromEsID(ctx context.Context, tx pgx.Tx, order entities.Order) (entities.Order, error) {
 	if err := ctx.Err(); err != nil {
-		 return err
+		 return entities.Order{}, err
 	}
 
-	_, err := tx.Exec(ctx, `INSERT INTO orders (estimate_id) VALUES ($1)`, order.EstimateID)
+	err := tx.QueryRow(ctx, `
+		INSERT INTO orders (
+			estimate_id, total_price, status, scheduled_for, release_at,
+			delivery_address_id, delivery_location, delivery_building, delivery_floor, delivery_notes
+		)
+		SELECT
+			id, total_price,
+			CASE WHEN scheduled_for IS NULL THEN 'Placed' ELSE 'Scheduled' END::order_statuses_enum,
+			scheduled_for, release_at,
+			delivery_address_id, delivery_location, delivery_building, delivery_floor, delivery_notes
+		FROM estimates WHERE id = $1 AND user_id = $2
+		RETURNING id, total_price, status, scheduled_for
+	`, order.EstimateID, order.UserID).Scan(&order.ID, &order.TotalPrice, &order.Status, &order.ScheduledFor)
 	if err != nil {
-		return err
+		if err == pgx.ErrNoRows {
+			return entities.Order{}, utils.NewNotFound("estimate does not exist")
+		}
+		if isUniqueViolation(err) {
+			return entities.Order{}, utils.NewConflict("estimate has already been used")
+		}
+		return entities.Order{}, err
+	}
+
+	return order, nil
+}
+
+// GetReorderLines returns the items of orderID from the order history, with
+// whether each can still be ordered and which merchant the route started at.
+func (r PurchaseRepository) GetReorderLines(ctx context.Context, orderID string) ([]entities.ReorderLine, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			v.user_id, v.merchant_id, v.merchant_lat, v.merchant_lon,
+			v.item_id, v.item_name, v.quantity, it.is_available,
+			COALESCE(e.start_merchant_id = v.merchant_id, FALSE)
+		FROM order_history_view v
+		JOIN items it ON it.id = v.item_id
+		JOIN orders o ON o.id = v.order_id
+		JOIN estimates e ON e.id = o.estimate_id
+		WHERE v.order_id = $1
+		ORDER BY v.merchant_name, v.merchant_id, v.item_name
+	`, orderID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query order lines")
+	}
+	defer rows.Close()
+
+	lines := make([]entities.ReorderLine, 0)
+	for rows.Next() {
+		l := entities.ReorderLine{}
+		if err := rows.Scan(
+			&l.UserID,
+			&l.MerchantID,
+			&l.MerchantLocation.Lat,
+			&l.MerchantLocation.Lon,
+			&l.ItemID,
+			&l.ItemName,
+			&l.Quantity,
+			&l.IsAvailable,
+			&l.IsStartingPoint,
+		); err != nil {
+			 return nil, utils.NewInternal("failed to scan order line row")
+		}
+		lines = append(lines, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating order line rows")
+	}
+
+	return lines, nil
+}
+
+// GetClosedMerchantIDs returns which of ids are closed at t according to their
+// opening hours. Opening hours are in server local time, like the open filter on
+// nearby merchants.
+func (r PurchaseRepository) GetClosedMerchantIDs(ctx context.Context, ids []string, t time.Time) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
 	}
 
-	return nil
+	day, prevDay, timeOfDay := openAtArgs(t.Local())
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT m.id FROM merchants m
+		WHERE m.id = ANY($1) AND NOT %s
+	`, openAtCondition("m.id", 2, 3, 4)), ids, day, prevDay, timeOfDay)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query merchant opening hours")
+	}
+	defer rows.Close()
+
+	closed := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			 return nil, utils.NewInternal("failed to scan merchant id")
+		}
+		closed = append(closed, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating merchant rows")
+	}
+
+	return closed, nil
+}
+
+// PurgeStaleEstimates deletes up to limit estimates that expired before the retention
+// window and never became an order, together with their orders_items.
+func (r PurchaseRepository) PurgeStaleEstimates(ctx context.Context, retention time.Duration, limit int) (int, int, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, 0, err
+	}
+
+	var estimates, items int
+	err := r.db.QueryRow(ctx, `
+		WITH stale AS (
+			DELETE FROM estimates
+			WHERE id IN (
+				SELECT e.id FROM estimates e
+				WHERE e.expires_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+				AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.estimate_id = e.id)
+				ORDER BY e.expires_at
+				LIMIT $2
+			)
+			RETURNING id
+		),
+		stale_items AS (
+			DELETE FROM orders_items oi
+			USING stale
+			WHERE oi.estimate_id = stale.id
+			RETURNING oi.id
+		)
+		SELECT (SELECT COUNT(*) FROM stale), (SELECT COUNT(*) FROM stale_items)
+	`, retention.Seconds(), limit).Scan(&estimates, &items)
+
+	if err != nil {
+		 return 0, 0, utils.NewInternal("failed to purge stale estimates")
+	}
+
+	return estimates, items, nil
 }
 
 type OrderGroup struct {
@@ -402,6 +728,16 @@ func (r PurchaseRepository) GetAllOrder(ctx context.Context, filter entities.Ord
 		i++
 	}
 
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("order_status::text = $%d", i))
+		args = append(args, filter.Status)
+		i++
+	}
+
+	if filter.UpcomingScheduled {
+		conditions = append(conditions, "order_status = 'Scheduled' AND order_scheduled_for > CURRENT_TIMESTAMP")
+	}
+
 	query := fmt.Sprintf(`
 		SELECT
 			order_id,
@@ -419,7 +755,11 @@ func (r PurchaseRepository) GetAllOrder(ctx context.Context, filter entities.Ord
 			item_imageurl,
 			item_price,
 			quantity,
-			item_created_at
+			item_created_at,
+			order_status,
+			order_created_at,
+			order_updated_at,
+			order_scheduled_for
 		FROM order_history_view
 		WHERE %s
 		ORDER BY order_id DESC
@@ -454,6 +794,10 @@ func (r PurchaseRepository) GetAllOrder(ctx context.Context, filter entities.Ord
 			&ord.ItemPrice,
 			&ord.Quantity,
 			&ord.ItemCreatedAt,
+			&ord.OrderStatus,
+			&ord.OrderCreatedAt,
+			&ord.OrderUpdatedAt,
+			&ord.OrderScheduledFor,
 		)
 
 		if err != nil {
@@ -483,6 +827,10 @@ func (r PurchaseRepository) GetAllOrder(ctx context.Context, filter entities.Ord
 				Group:make(map[string]*dto.OrderHistoryMerchant, 64),
 				Order:&dto.OrderHistory{
 					OrderID:      ord.OrderID,
+					Status:       ord.OrderStatus,
+					CreatedAt:    ord.OrderCreatedAt,
+					UpdatedAt:    ord.OrderUpdatedAt,
+					ScheduledFor: ord.OrderScheduledFor,
 					OrderHistory: make([]dto.OrderHistoryMerchant, 0, 8),
 				},
 			}
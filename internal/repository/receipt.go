@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReceiptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReceiptRepository(db *pgxpool.Pool) ReceiptRepository {
+	return ReceiptRepository{db: db}
+}
+
+// GetReceipt loads an order with its buyer, delivery and stored price
+// breakdown, and its lines from the order history grouped by merchant.
+// Invoices are not included, they are issued separately.
+func (r ReceiptRepository) GetReceipt(ctx context.Context, orderID string) (entities.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Receipt{}, err
+	}
+
+	rc := entities.Receipt{}
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			o.id, e.user_id, u.username, u.email, o.status, o.created_at,
+			COALESCE(e.price_breakdown, '{}'::jsonb),
+			o.delivery_address_id,
+			COALESCE(ST_Y(o.delivery_location::geometry), 0), COALESCE(ST_X(o.delivery_location::geometry), 0),
+			o.delivery_building, o.delivery_floor, o.delivery_notes
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		JOIN users u ON u.id = e.user_id
+		WHERE o.id = $1
+	`, orderID).Scan(
+		&rc.OrderID,
+		&rc.UserID,
+		&rc.Username,
+		&rc.Email,
+		&rc.Status,
+		&rc.OrderedAt,
+		&rc.PriceBreakdown,
+		&rc.Delivery.AddressID,
+		&rc.Delivery.Location.Lat,
+		&rc.Delivery.Location.Lon,
+		&rc.Delivery.Building,
+		&rc.Delivery.Floor,
+		&rc.Delivery.Notes,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Receipt{}, utils.NewNotFound("order does not exist")
+		}
+		return entities.Receipt{}, utils.NewInternal("failed get order")
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT merchant_id, merchant_name, merchant_lat, merchant_lon, item_id, item_name, item_category, quantity, unit_price
+		FROM order_history_view
+		WHERE order_id = $1
+		ORDER BY merchant_name, merchant_id, item_name, item_id
+	`, orderID)
+	if err != nil {
+		 return entities.Receipt{}, utils.NewInternal("failed to query receipt lines")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := entities.ReceiptMerchant{}
+		l := entities.ReceiptLine{}
+		if err := rows.Scan(&m.MerchantID, &m.Name, &m.Location.Lat, &m.Location.Lon, &l.ItemID, &l.Name, &l.Category, &l.Quantity, &l.UnitPrice); err != nil {
+			 return entities.Receipt{}, utils.NewInternal("failed to scan receipt line row")
+		}
+		if l.UnitPrice != nil {
+			amount := l.Quantity * *l.UnitPrice
+			l.Amount = &amount
+		}
+
+		if n := len(rc.Merchants); n == 0 || rc.Merchants[n-1].MerchantID != m.MerchantID {
+			 rc.Merchants = append(rc.Merchants, m)
+		}
+		last := &rc.Merchants[len(rc.Merchants)-1]
+		last.Lines = append(last.Lines, l)
+		if l.Amount != nil {
+			 last.Subtotal += *l.Amount
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		 return entities.Receipt{}, utils.NewInternal("error iterating receipt line rows")
+	}
+
+	return rc, nil
+}
+
+// IssueInvoices returns the invoices of orderID for merchantIDs, numbering the
+// ones not issued yet. The order row is locked so two requests for the same
+// receipt cannot both number it, and each merchant's counter row is locked
+// while its number is taken.
+func (r ReceiptRepository) IssueInvoices(ctx context.Context, tx pgx.Tx, orderID string, merchantIDs []string) (map[string]entities.Invoice, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM orders WHERE id = $1 FOR UPDATE`, orderID); err != nil {
+		 return nil, utils.NewInternal("failed to lock order")
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, order_id, merchant_id, sequence, number, issued_at
+		FROM invoices
+		WHERE order_id = $1
+	`, orderID)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query invoices")
+	}
+
+	invoices := make(map[string]entities.Invoice, len(merchantIDs))
+	for rows.Next() {
+		inv := entities.Invoice{}
+		if err := rows.Scan(&inv.ID, &inv.OrderID, &inv.MerchantID, &inv.Sequence, &inv.Number, &inv.IssuedAt); err != nil {
+			rows.Close()
+			return nil, utils.NewInternal("failed to scan invoice row")
+		}
+		invoices[inv.MerchantID] = inv
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating invoice rows")
+	}
+
+	for _, merchantID := range merchantIDs {
+		if _, ok := invoices[merchantID]; ok {
+			 continue
+		}
+
+		inv := entities.Invoice{}
+		err := tx.QueryRow(ctx, `
+			WITH seq AS (
+				INSERT INTO merchant_invoice_sequences (merchant_id, last_number)
+				VALUES ($2, 1)
+				ON CONFLICT (merchant_id) DO UPDATE SET last_number = merchant_invoice_sequences.last_number + 1
+				RETURNING last_number
+			)
+			INSERT INTO invoices (order_id, merchant_id, sequence, number)
+			-- e.g. INV-1A2B3C4D-000042, LPAD would cut numbers past six digits
+			SELECT $1, $2, last_number, 'INV-' || UPPER(LEFT($2::text, 8)) || '-' || LPAD(last_number::text, GREATEST(6, LENGTH(last_number::text)), '0')
+			FROM seq
+			RETURNING id, order_id, merchant_id, sequence, number, issued_at
+		`, orderID, merchantID).Scan(&inv.ID, &inv.OrderID, &inv.MerchantID, &inv.Sequence, &inv.Number, &inv.IssuedAt)
+		if err != nil {
+			 return nil, utils.NewInternal("failed to issue invoice")
+		}
+		invoices[merchantID] = inv
+	}
+
+	return invoices, nil
+}
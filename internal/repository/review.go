@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewRepository(db *pgxpool.Pool) ReviewRepository {
+	return ReviewRepository{db: db}
+}
+
+const reviewColumns = `
+	r.id, r.order_id, r.merchant_id, r.user_id, r.rating, r.comment, r.photo_keys,
+	COALESCE((
+		SELECT jsonb_agg(jsonb_build_object('itemId', ri.item_id, 'rating', ri.rating) ORDER BY ri.item_id)
+		FROM review_items ri WHERE ri.review_id = r.id
+	), '[]'::jsonb),
+	r.status, r.moderation_reason, r.moderated_by, r.moderated_at, r.created_at
+`
+
+const courierReviewColumns = `
+	r.id, r.order_id, r.courier_id, r.user_id, r.rating, r.comment,
+	r.status, r.moderation_reason, r.moderated_by, r.moderated_at, r.created_at
+`
+
+func scanReview(row pgx.Row) (entities.Review, error) {
+	rv := entities.Review{}
+	err := row.Scan(
+		&rv.ID,
+		&rv.OrderID,
+		&rv.MerchantID,
+		&rv.UserID,
+		&rv.Rating,
+		&rv.Comment,
+		&rv.PhotoKeys,
+		&rv.Items,
+		&rv.Status,
+		&rv.ModerationReason,
+		&rv.ModeratedBy,
+		&rv.ModeratedAt,
+		&rv.CreatedAt,
+	)
+	return rv, err
+}
+
+func scanCourierReview(row pgx.Row) (entities.CourierReview, error) {
+	rv := entities.CourierReview{}
+	err := row.Scan(
+		&rv.ID,
+		&rv.OrderID,
+		&rv.CourierID,
+		&rv.UserID,
+		&rv.Rating,
+		&rv.Comment,
+		&rv.Status,
+		&rv.ModerationReason,
+		&rv.ModeratedBy,
+		&rv.ModeratedAt,
+		&rv.CreatedAt,
+	)
+	return rv, err
+}
+
+// GetReviewableOrder loads who placed orderID, its status, its courier and the
+// items it had from each merchant.
+func (r ReviewRepository) GetReviewableOrder(ctx context.Context, orderID string) (entities.ReviewableOrder, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.ReviewableOrder{}, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT e.user_id, o.status, o.courier_id, oi.merchant_id, oi.merchant_item_id
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		JOIN orders_items oi ON oi.estimate_id = o.estimate_id
+		WHERE o.id = $1
+	`, orderID)
+	if err != nil {
+		 return entities.ReviewableOrder{}, utils.NewInternal("failed to query order")
+	}
+	defer rows.Close()
+
+	order := entities.ReviewableOrder{Items: map[string][]string{}}
+	found := false
+	for rows.Next() {
+		var merchantID, itemID string
+		if err := rows.Scan(&order.UserID, &order.Status, &order.CourierID, &merchantID, &itemID); err != nil {
+			 return entities.ReviewableOrder{}, utils.NewInternal("failed to scan order row")
+		}
+		order.Items[merchantID] = append(order.Items[merchantID], itemID)
+		found = true
+	}
+
+	if err := rows.Err(); err != nil {
+		 return entities.ReviewableOrder{}, utils.NewInternal("error iterating order rows")
+	}
+
+	if !found {
+		 return entities.ReviewableOrder{}, utils.NewNotFound("order does not exist")
+	}
+
+	return order, nil
+}
+
+// CreateReview stores rv and its item ratings. A second review of the same
+// merchant for the same order is a conflict.
+func (r ReviewRepository) CreateReview(ctx context.Context, tx pgx.Tx, rv entities.Review) (entities.Review, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Review{}, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO reviews (order_id, merchant_id, user_id, rating, comment, photo_keys)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at
+	`, rv.OrderID, rv.MerchantID, rv.UserID, rv.Rating, rv.Comment, rv.PhotoKeys).Scan(&rv.ID, &rv.Status, &rv.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entities.Review{}, utils.NewConflict("merchant has already been reviewed for this order")
+		}
+		return entities.Review{}, utils.NewInternal("failed create review")
+	}
+
+	if len(rv.Items) == 0 {
+		 return rv, nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, it := range rv.Items {
+		batch.Queue(`
+			INSERT INTO review_items (review_id, item_id, rating)
+			VALUES ($1, $2, $3)
+		`, rv.ID, it.ItemID, it.Rating)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
+	if err := br.Close(); err != nil {
+		return entities.Review{}, utils.NewInternal("failed to insert review items")
+	}
+
+	return rv, nil
+}
+
+// CreateCourierReview stores rv. An order's courier can be reviewed once.
+func (r ReviewRepository) CreateCourierReview(ctx context.Context, tx pgx.Tx, rv entities.CourierReview) (entities.CourierReview, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.CourierReview{}, err
+	}
+
+	err := tx.QueryRow(ctx, `
+		INSERT INTO courier_reviews (order_id, courier_id, user_id, rating, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, status, created_at
+	`, rv.OrderID, rv.CourierID, rv.UserID, rv.Rating, rv.Comment).Scan(&rv.ID, &rv.Status, &rv.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entities.CourierReview{}, utils.NewConflict("courier has already been reviewed for this order")
+		}
+		return entities.CourierReview{}, utils.NewInternal("failed create courier review")
+	}
+
+	return rv, nil
+}
+
+// reviewConditions builds the WHERE clause shared by the review listings,
+// narrowed to the reviews of id in column when id is set.
+func reviewConditions(column, id, status string) (string, []any) {
+	conds := []string{"TRUE"}
+	args := []any{}
+
+	if id != "" {
+		args = append(args, id)
+		conds = append(conds, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if status != "" {
+		args = append(args, status)
+		conds = append(conds, fmt.Sprintf("r.status = $%d", len(args)))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+func reviewPage(f entities.ReviewFilter) (int, int) {
+	limit, offset := f.Limit, f.Offset
+	if limit <= 0 {
+		 limit = 5
+	}
+
+	if offset < 0 {
+		 offset = 0
+	}
+
+	return limit, offset
+}
+
+func (r ReviewRepository) GetReviews(ctx context.Context, f entities.ReviewFilter) ([]entities.Review, int, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, 0, err
+	}
+
+	where, args := reviewConditions("r.merchant_id", f.MerchantID, f.Status)
+	limit, offset := reviewPage(f)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER() AS total
+		FROM reviews r
+		WHERE %s
+		ORDER BY r.created_at DESC, r.id
+		LIMIT %d OFFSET %d
+	`, reviewColumns, where, limit, offset), args...)
+	if err != nil {
+		 return nil, 0, utils.NewInternal("failed to query reviews")
+	}
+	defer rows.Close()
+
+	total := 0
+	reviews := make([]entities.Review, 0)
+	for rows.Next() {
+		rv := entities.Review{}
+		err := rows.Scan(
+			&rv.ID, &rv.OrderID, &rv.MerchantID, &rv.UserID, &rv.Rating, &rv.Comment, &rv.PhotoKeys,
+			&rv.Items, &rv.Status, &rv.ModerationReason, &rv.ModeratedBy, &rv.ModeratedAt, &rv.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			 return nil, 0, utils.NewInternal("failed to scan review")
+		}
+		reviews = append(reviews, rv)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, 0, utils.NewInternal("error iterating review rows")
+	}
+
+	return reviews, total, nil
+}
+
+func (r ReviewRepository) GetCourierReviews(ctx context.Context, f entities.ReviewFilter) ([]entities.CourierReview, int, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, 0, err
+	}
+
+	where, args := reviewConditions("r.courier_id", f.CourierID, f.Status)
+	limit, offset := reviewPage(f)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER() AS total
+		FROM courier_reviews r
+		WHERE %s
+		ORDER BY r.created_at DESC, r.id
+		LIMIT %d OFFSET %d
+	`, courierReviewColumns, where, limit, offset), args...)
+	if err != nil {
+		 return nil, 0, utils.NewInternal("failed to query courier reviews")
+	}
+	defer rows.Close()
+
+	total := 0
+	reviews := make([]entities.CourierReview, 0)
+	for rows.Next() {
+		rv := entities.CourierReview{}
+		err := rows.Scan(
+			&rv.ID, &rv.OrderID, &rv.CourierID, &rv.UserID, &rv.Rating, &rv.Comment,
+			&rv.Status, &rv.ModerationReason, &rv.ModeratedBy, &rv.ModeratedAt, &rv.CreatedAt,
+			&total,
+		)
+		if err != nil {
+			 return nil, 0, utils.NewInternal("failed to scan courier review")
+		}
+		reviews = append(reviews, rv)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, 0, utils.NewInternal("error iterating courier review rows")
+	}
+
+	return reviews, total, nil
+}
+
+// ModerateReview publishes or hides a review and records who did it and why.
+func (r ReviewRepository) ModerateReview(ctx context.Context, tx pgx.Tx, m entities.ReviewModeration) (entities.Review, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Review{}, err
+	}
+
+	rv, err := scanReview(tx.QueryRow(ctx, fmt.Sprintf(`
+		UPDATE reviews r
+		SET status = $2, moderation_reason = $3, moderated_by = $4, moderated_at = CURRENT_TIMESTAMP
+		WHERE r.id = $1
+		RETURNING %s
+	`, reviewColumns), m.ReviewID, m.Status, m.Reason, m.ModeratedBy))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Review{}, utils.NewNotFound("review does not exist")
+		}
+		return entities.Review{}, utils.NewInternal("failed moderate review")
+	}
+
+	return rv, nil
+}
+
+// ModerateCourierReview publishes or hides a courier review and records who
+// did it and why.
+func (r ReviewRepository) ModerateCourierReview(ctx context.Context, tx pgx.Tx, m entities.ReviewModeration) (entities.CourierReview, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.CourierReview{}, err
+	}
+
+	rv, err := scanCourierReview(tx.QueryRow(ctx, fmt.Sprintf(`
+		UPDATE courier_reviews r
+		SET status = $2, moderation_reason = $3, moderated_by = $4, moderated_at = CURRENT_TIMESTAMP
+		WHERE r.id = $1
+		RETURNING %s
+	`, courierReviewColumns), m.ReviewID, m.Status, m.Reason, m.ModeratedBy))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.CourierReview{}, utils.NewNotFound("courier review does not exist")
+		}
+		return entities.CourierReview{}, utils.NewInternal("failed moderate courier review")
+	}
+
+	return rv, nil
+}
+
+// RefreshReviewRatings recomputes the summary ratings of the merchant and the
+// items of reviewID from their published reviews, so nearby can sort on them
+// without aggregating. The rows are locked first, so a concurrent review of the
+// same merchant waits and then counts this one.
+func (r ReviewRepository) RefreshReviewRatings(ctx context.Context, tx pgx.Tx, reviewID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		SELECT 1 FROM merchants WHERE id = (SELECT merchant_id FROM reviews WHERE id = $1) FOR UPDATE
+	`, reviewID)
+	if err != nil {
+		 return utils.NewInternal("failed to lock merchant")
+	}
+
+	_, err = tx.Exec(ctx, `
+		SELECT 1 FROM items WHERE id IN (SELECT item_id FROM review_items WHERE review_id = $1) ORDER BY id FOR UPDATE
+	`, reviewID)
+	if err != nil {
+		 return utils.NewInternal("failed to lock items")
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE merchants m
+		SET (rating_avg, rating_count) = (
+			SELECT COALESCE(ROUND(AVG(r.rating), 2), 0), COUNT(*)
+			FROM reviews r
+			WHERE r.merchant_id = m.id AND r.status = 'Published'
+		)
+		WHERE m.id = (SELECT merchant_id FROM reviews WHERE id = $1)
+	`, reviewID)
+	if err != nil {
+		 return utils.NewInternal("failed to refresh merchant rating")
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE items it
+		SET (rating_avg, rating_count) = (
+			SELECT COALESCE(ROUND(AVG(ri.rating), 2), 0), COUNT(*)
+			FROM review_items ri
+			JOIN reviews r ON r.id = ri.review_id
+			WHERE ri.item_id = it.id AND r.status = 'Published'
+		)
+		WHERE it.id IN (SELECT item_id FROM review_items WHERE review_id = $1)
+	`, reviewID)
+	if err != nil {
+		 return utils.NewInternal("failed to refresh item ratings")
+	}
+
+	return nil
+}
+
+// RefreshCourierRating recomputes the summary rating of courierID from its
+// published reviews, locking the courier first like RefreshReviewRatings.
+func (r ReviewRepository) RefreshCourierRating(ctx context.Context, tx pgx.Tx, courierID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := tx.Exec(ctx, `SELECT 1 FROM couriers WHERE id = $1 FOR UPDATE`, courierID)
+	if err != nil {
+		 return utils.NewInternal("failed to lock courier")
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE couriers c
+		SET (rating_avg, rating_count) = (
+			SELECT COALESCE(ROUND(AVG(r.rating), 2), 0), COUNT(*)
+			FROM courier_reviews r
+			WHERE r.courier_id = c.id AND r.status = 'Published'
+		)
+		WHERE c.id = $1
+	`, courierID)
+	if err != nil {
+		 return utils.NewInternal("failed to refresh courier rating")
+	}
+
+	return nil
+}
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TrackingRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTrackingRepository(db *pgxpool.Pool) TrackingRepository {
+	return TrackingRepository{db: db}
+}
+
+// EnsureLocationPartition creates the courier_locations partition holding the
+// month that contains t, if it does not exist yet.
+func (r TrackingRepository) EnsureLocationPartition(ctx context.Context, t time.Time) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	_, err := r.db.Exec(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS courier_locations_%s
+		PARTITION OF courier_locations
+		FOR VALUES FROM ('%s') TO ('%s')
+	`, from.Format("2006_01"), from.Format(time.RFC3339), to.Format(time.RFC3339)))
+	return err
+}
+
+// CopyLocations bulk-loads pings with the COPY protocol.
+func (r TrackingRepository) CopyLocations(ctx context.Context, locations []entities.CourierLocation) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		 return 0, err
+	}
+
+	return r.db.CopyFrom(ctx,
+		pgx.Identifier{"courier_locations"},
+		[]string{"courier_id", "order_id", "recorded_at", "lat", "lon", "accuracy_m", "speed_kmh"},
+		pgx.CopyFromSlice(len(locations), func(i int) ([]any, error) {
+			l := locations[i]
+			return []any{l.CourierID, l.OrderID, l.RecordedAt, l.Lat, l.Lon, l.AccuracyM, l.SpeedKmh}, nil
+		}),
+	)
+}
+
+// GetActiveOrder returns the order the courier is currently delivering, if
+// any, and since when the courier has had it.
+func (r TrackingRepository) GetActiveOrder(ctx context.Context, courierID string) (*string, time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, time.Time{}, err
+	}
+
+	var orderID string
+	var since time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT o.id, COALESCE(
+			(SELECT MAX(d.responded_at) FROM dispatch_offers d WHERE d.order_id = o.id AND d.courier_id = o.courier_id AND d.status = 'Accepted'),
+			o.updated_at
+		)
+		FROM orders o
+		WHERE o.courier_id = $1 AND o.status IN ('Accepted', 'Preparing', 'PickedUp')
+		ORDER BY o.updated_at DESC
+		LIMIT 1
+	`, courierID).Scan(&orderID, &since)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, time.Time{}, nil
+		}
+		return nil, time.Time{}, utils.NewInternal("failed get active order")
+	}
+
+	return &orderID, since, nil
+}
+
+// GetUntrackedDeliveries returns up to limit delivered orders that had a
+// courier but no trip yet, with when they were picked up and delivered
+// according to the status history. Orders never marked picked up start when
+// the courier took the offer. Only orders delivered at least settle ago are
+// returned, so pings that arrive late are in before the trip is built. The
+// quote is the part after pickup; older estimates only stored travel time in
+// estimated_delivery_minutes.
+func (r TrackingRepository) GetUntrackedDeliveries(ctx context.Context, limit int, settle time.Duration) ([]entities.TripSource, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			o.id, o.courier_id, COALESCE(e.travel_minutes, e.estimated_delivery_minutes),
+			COALESCE(
+				(SELECT MAX(h.created_at) FROM order_status_history h WHERE h.order_id = o.id AND h.to_status = 'PickedUp'),
+				(SELECT MAX(d.responded_at) FROM dispatch_offers d WHERE d.order_id = o.id AND d.courier_id = o.courier_id AND d.status = 'Accepted')
+			),
+			dl.delivered_at
+		FROM orders o
+		JOIN estimates e ON e.id = o.estimate_id
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(
+				(SELECT MAX(h.created_at) FROM order_status_history h WHERE h.order_id = o.id AND h.to_status = 'Delivered'),
+				o.updated_at
+			) AS delivered_at
+		) dl
+		WHERE o.status = 'Delivered'
+		AND o.courier_id IS NOT NULL
+		AND dl.delivered_at <= CURRENT_TIMESTAMP - make_interval(secs => $2)
+		AND NOT EXISTS (SELECT 1 FROM order_trips t WHERE t.order_id = o.id)
+		ORDER BY dl.delivered_at
+		LIMIT $1
+	`, limit, settle.Seconds())
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query untracked deliveries")
+	}
+	defer rows.Close()
+
+	sources := make([]entities.TripSource, 0, limit)
+	for rows.Next() {
+		src := entities.TripSource{}
+		if err := rows.Scan(&src.OrderID, &src.CourierID, &src.EstimatedMinutes, &src.PickedUpAt, &src.DeliveredAt); err != nil {
+			 return nil, utils.NewInternal("failed to scan untracked delivery row")
+		}
+		sources = append(sources, src)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating untracked delivery rows")
+	}
+
+	return sources, nil
+}
+
+// GetCourierLocations returns the pings courierID recorded between from and
+// to, in the order they were taken. Pings are matched to an order by time
+// rather than by their order_id, which is only known for pings that reached
+// the server while the courier still had that order.
+func (r TrackingRepository) GetCourierLocations(ctx context.Context, courierID string, from, to time.Time) ([]entities.CourierLocation, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT courier_id, order_id, recorded_at, lat, lon, accuracy_m, speed_kmh
+		FROM courier_locations
+		WHERE courier_id = $1 AND recorded_at >= $2 AND recorded_at <= $3
+		ORDER BY recorded_at
+	`, courierID, from, to)
+	if err != nil {
+		 return nil, utils.NewInternal("failed to query courier locations")
+	}
+	defer rows.Close()
+
+	locations := make([]entities.CourierLocation, 0)
+	for rows.Next() {
+		l := entities.CourierLocation{}
+		if err := rows.Scan(&l.CourierID, &l.OrderID, &l.RecordedAt, &l.Lat, &l.Lon, &l.AccuracyM, &l.SpeedKmh); err != nil {
+			 return nil, utils.NewInternal("failed to scan courier location row")
+		}
+		locations = append(locations, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		 return nil, utils.NewInternal("error iterating courier location rows")
+	}
+
+	return locations, nil
+}
+
+func (r TrackingRepository) InsertTrip(ctx context.Context, trip entities.Trip) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO order_trips (
+			order_id, courier_id, polyline, raw_points, polyline_points,
+			distance_km, duration_seconds, estimated_minutes, started_at, ended_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (order_id) DO NOTHING
+	`,
+		trip.OrderID,
+		trip.CourierID,
+		trip.Polyline,
+		trip.RawPoints,
+		trip.PolylinePoints,
+		trip.DistanceKm,
+		trip.DurationSeconds,
+		trip.EstimatedMinutes,
+		trip.StartedAt,
+		trip.EndedAt,
+	)
+	if err != nil {
+		 return utils.NewInternal("failed create order trip")
+	}
+
+	return nil
+}
+
+func (r TrackingRepository) GetTrip(ctx context.Context, orderID string) (entities.Trip, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Trip{}, err
+	}
+
+	trip := entities.Trip{}
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			order_id, courier_id, polyline, raw_points, polyline_points,
+			distance_km, duration_seconds, estimated_minutes, started_at, ended_at, created_at
+		FROM order_trips WHERE order_id = $1
+	`, orderID).Scan(
+		&trip.OrderID,
+		&trip.CourierID,
+		&trip.Polyline,
+		&trip.RawPoints,
+		&trip.PolylinePoints,
+		&trip.DistanceKm,
+		&trip.DurationSeconds,
+		&trip.EstimatedMinutes,
+		&trip.StartedAt,
+		&trip.EndedAt,
+		&trip.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entities.Trip{}, utils.NewNotFound("trip does not exist")
+		}
+		return entities.Trip{}, utils.NewInternal("failed get order trip")
+	}
+
+	return trip, nil
+}
+
+// GetETAAccuracy aggregates trip duration against the quoted travel time over
+// trips that ended in the filter window. Trips with fewer than two pings or no
+// quote are left out.
+func (r TrackingRepository) GetETAAccuracy(ctx context.Context, filter entities.ETAAccuracyFilter) (entities.ETAAccuracy, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.ETAAccuracy{}, err
+	}
+
+	conditions := []string{"raw_points >= 2", "estimated_minutes > 0"}
+	args := []any{}
+	i := 1
+
+	if filter.From != nil {
+		conditions = append(conditions, fmt.Sprintf("ended_at >= $%d", i))
+		args = append(args, *filter.From)
+		i++
+	}
+
+	if filter.To != nil {
+		conditions = append(conditions, fmt.Sprintf("ended_at < $%d", i))
+		args = append(args, *filter.To)
+		i++
+	}
+
+	acc := entities.ETAAccuracy{}
+	err := r.db.QueryRow(ctx, fmt.Sprintf(`
+		WITH errors AS (
+			SELECT
+				duration_seconds / 60.0 - estimated_minutes AS err,
+				estimated_minutes
+			FROM order_trips
+			WHERE %s
+		)
+		SELECT
+			COUNT(*),
+			COALESCE(AVG(err), 0)::float8,
+			COALESCE(AVG(ABS(err)), 0)::float8,
+			COALESCE(AVG(ABS(err) / estimated_minutes), 0)::float8,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY ABS(err)), 0)::float8,
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY ABS(err)), 0)::float8,
+			COALESCE(AVG(CASE WHEN ABS(err) <= 5 THEN 1.0 ELSE 0.0 END), 0)::float8
+		FROM errors
+	`, strings.Join(conditions, " AND ")), args...).Scan(
+		&acc.Trips,
+		&acc.MeanErrorMinutes,
+		&acc.MeanAbsErrorMinutes,
+		&acc.MeanAbsPctError,
+		&acc.P50AbsErrorMinutes,
+		&acc.P90AbsErrorMinutes,
+		&acc.WithinFiveMinutes,
+	)
+	if err != nil {
+		 return entities.ETAAccuracy{}, utils.NewInternal("failed to compute eta accuracy")
+	}
+
+	return acc, nil
+}
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterAddressRoutes(r chi.Router, h handlers.AddressHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/addresses", h.GetAddresses)
+		g.Get("/users/addresses/{addressId}", h.GetAddress)
+
+		g.Post("/users/addresses", h.CreateAddress)
+		g.Put("/users/addresses/{addressId}", h.UpdateAddress)
+		g.Delete("/users/addresses/{addressId}", h.DeleteAddress)
+	})
+}
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterCartRoutes(r chi.Router, h handlers.CartHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/cart", h.GetCart)
+		g.Delete("/users/cart", h.ClearCart)
+
+		g.Post("/users/cart/items", h.AddItem)
+		g.Put("/users/cart/items/{itemId}", h.UpdateItem)
+		g.Delete("/users/cart/items/{itemId}", h.RemoveItem)
+
+		g.Post("/users/cart/estimate", h.CreateEstimate)
+	})
+}
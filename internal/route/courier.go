@@ -0,0 +1,29 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterCourierRoutes(r chi.Router, h handlers.CourierHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Post("/admin/couriers", h.CreateCourier)
+	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/couriers/me", h.GetMe)
+		g.Get("/couriers/me/offers", h.GetOffers)
+
+		g.Put("/couriers/me/availability", h.SetAvailability)
+		g.Post("/couriers/me/heartbeat", h.Heartbeat)
+
+		g.Post("/couriers/me/offers/{offerId}/accept", h.AcceptOffer)
+		g.Post("/couriers/me/offers/{offerId}/decline", h.DeclineOffer)
+	})
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterFavoriteRoutes(r chi.Router, h handlers.FavoriteHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/favorites", h.GetFavorites)
+		g.Post("/users/favorites", h.AddFavorite)
+		g.Delete("/users/favorites", h.RemoveFavorite)
+	})
+}
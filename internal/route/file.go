@@ -12,4 +12,9 @@ func RegisterFileRoutes(r chi.Router, h handlers.FileHandler) {
 		g.Use(middleware.Protected(true))
 		g.Post("/image", h.UploadFile)
 	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+		g.Post("/users/reviews/photos", h.UploadReviewPhoto)
+	})
 }
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterGeoRoutes(r chi.Router, h handlers.GeoHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/merchants/map", h.GetMerchantMap)
+	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Get("/admin/merchants.geojson", h.ExportMerchantsGeoJSON)
+		g.Get("/tiles/merchants/{z}/{x}/{y}.mvt", h.GetMerchantTile)
+	})
+}
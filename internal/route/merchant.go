@@ -16,5 +16,9 @@ func RegisterMerchantRoutes(r chi.Router, h handlers.MerchantHandler) {
 
 		g.Post("/admin/merchants", h.CreateMerchant)
 		g.Post("/admin/merchants/{merchantId}/items", h.CreateMercItem)
+
+		g.Put("/admin/merchants/{merchantId}/opening-hours", h.SetOpeningHours)
+		g.Put("/admin/merchants/{merchantId}/owner", h.SetOwner)
+		g.Put("/admin/merchants/{merchantId}/items/{itemId}/availability", h.SetItemAvailability)
 	})
 }
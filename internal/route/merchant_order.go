@@ -0,0 +1,20 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterMerchantOrderRoutes(r chi.Router, h handlers.MerchantOrderHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/merchants/{merchantId}/orders", h.GetQueue)
+
+		g.Post("/merchants/{merchantId}/orders/{orderId}/accept", h.Accept)
+		g.Post("/merchants/{merchantId}/orders/{orderId}/reject", h.Reject)
+		g.Post("/merchants/{merchantId}/orders/{orderId}/ready", h.Ready)
+	})
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterOrderRoutes(r chi.Router, h handlers.OrderHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Post("/admin/orders/{orderId}/cancel", h.CancelByOperator)
+		g.Patch("/admin/orders/{orderId}/status", h.UpdateStatus)
+	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/orders/{orderId}/status", h.GetStatus)
+		g.Get("/users/orders/{orderId}/events", h.Events)
+
+		g.Post("/users/orders/{orderId}/cancel", h.CancelByUser)
+	})
+}
@@ -0,0 +1,13 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterPaymentRoutes mounts the provider webhooks. They are authenticated by
+// the provider's signature instead of a bearer token.
+func RegisterPaymentRoutes(r chi.Router, h handlers.PaymentHandler) {
+	r.Post("/payments/webhooks/{provider}", h.Webhook)
+}
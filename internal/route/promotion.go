@@ -0,0 +1,19 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterPromotionRoutes(r chi.Router, h handlers.PromotionHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Get("/admin/promotions", h.GetAllPromotion)
+
+		g.Post("/admin/promotions", h.CreatePromotion)
+		g.Patch("/admin/promotions/{promotionId}", h.SetPromotionActive)
+	})
+}
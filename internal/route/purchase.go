@@ -12,9 +12,11 @@ func RegisterPurchaseRoutes(r chi.Router, h handlers.PurchaseHandler) {
 		g.Use(middleware.Protected(false))
 
 		g.Get("/users/orders", h.GetAllOrder)
+		g.Get("/users/estimates/{estimateId}", h.GetEstimate)
 		g.Get("/merchants/nearby/{lat},{lon}", h.GetNearbyMerchants)
 
 		g.Post("/users/orders", h.CreateOrder)
 		g.Post("/users/estimate", h.CreateEstimate)
+		g.Post("/users/orders/{orderId}/reorder", h.Reorder)
 	})
 }
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterReceiptRoutes(r chi.Router, h handlers.ReceiptHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/orders/{orderId}/receipt", h.GetReceipt)
+	})
+}
@@ -0,0 +1,29 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterReviewRoutes(r chi.Router, h handlers.ReviewHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Get("/admin/reviews", h.GetReviews)
+		g.Get("/admin/courier-reviews", h.GetCourierReviews)
+
+		g.Patch("/admin/reviews/{reviewId}/status", h.ModerateReview)
+		g.Patch("/admin/courier-reviews/{reviewId}/status", h.ModerateCourierReview)
+	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/merchants/{merchantId}/reviews", h.GetMerchantReviews)
+
+		g.Post("/users/orders/{orderId}/reviews", h.CreateReview)
+		g.Post("/users/orders/{orderId}/courier-review", h.CreateCourierReview)
+	})
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterTrackingRoutes(r chi.Router, h handlers.TrackingHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Get("/admin/eta/accuracy", h.GetETAAccuracy)
+	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/orders/{orderId}/trip", h.GetTrip)
+
+		g.Post("/couriers/me/location", h.RecordLocations)
+	})
+}
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"belimang/internal/handlers"
+	"belimang/internal/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterWalletRoutes(r chi.Router, h handlers.WalletHandler) {
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(true))
+
+		g.Post("/admin/wallets/{userId}/credit", h.Credit)
+	})
+
+	r.Group(func(g chi.Router) {
+		g.Use(middleware.Protected(false))
+
+		g.Get("/users/wallet", h.GetWallet)
+		g.Get("/users/wallet/transactions", h.GetTransactions)
+	})
+}
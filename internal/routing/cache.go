@@ -0,0 +1,168 @@
+package routing
+
+import (
+	"belimang/internal/utils"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type pairKey struct {
+	fromLat, fromLon int64
+	toLat, toLon     int64
+}
+
+type pairCost struct {
+	distance  float64
+	duration  float64
+	expiresAt time.Time
+}
+
+// CachedProvider keeps the cost of every point pair it has seen for ttl, so repeated
+// estimates from the same merchants and neighbourhoods skip the routing engine.
+// Coordinates are rounded to ~1m before they are used as cache keys.
+type CachedProvider struct {
+	next       Provider
+	ttl        time.Duration
+	maxEntries int
+
+	mu    sync.Mutex
+	pairs map[pairKey]pairCost
+}
+
+func NewCachedProvider(next Provider, ttl time.Duration, maxEntries int) *CachedProvider {
+	if maxEntries <= 0 {
+		 maxEntries = 100_000
+	}
+
+	return &CachedProvider{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		pairs:      make(map[pairKey]pairCost),
+	}
+}
+
+func (p *CachedProvider) Name() string {
+	return p.next.Name()
+}
+
+func roundCoord(v float64) int64 {
+	return int64(math.Round(v * 1e5))
+}
+
+func newPairKey(a, b utils.Point) pairKey {
+	return pairKey{
+		fromLat: roundCoord(a.Lat), fromLon: roundCoord(a.Lon),
+		toLat: roundCoord(b.Lat), toLon: roundCoord(b.Lon),
+	}
+}
+
+func (p *CachedProvider) Matrix(ctx context.Context, points []utils.Point) (Matrix, error) {
+	if m, ok := p.lookup(points); ok {
+		 return m, nil
+	}
+
+	m, err := p.next.Matrix(ctx, points)
+	if err != nil {
+		 return Matrix{}, err
+	}
+
+	p.store(points, m)
+	return m, nil
+}
+
+func (p *CachedProvider) lookup(points []utils.Point) (Matrix, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	m := newMatrix(len(points), p.next.Name())
+	for i := range points {
+		for j := range points {
+			if i == j {
+				continue
+			}
+
+			c, ok := p.pairs[newPairKey(points[i], points[j])]
+			if !ok || now.After(c.expiresAt) {
+				 return Matrix{}, false
+			}
+
+			m.Distances[i][j] = c.distance
+			m.Durations[i][j] = c.duration
+		}
+	}
+
+	return m, true
+}
+
+func (p *CachedProvider) store(points []utils.Point, m Matrix) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if len(p.pairs)+len(points)*len(points) > p.maxEntries {
+		p.evictExpired(now)
+		if len(p.pairs)+len(points)*len(points) > p.maxEntries {
+			log.Warn().Int("entries", len(p.pairs)).Msg("routing cache full, resetting")
+			p.pairs = make(map[pairKey]pairCost)
+		}
+	}
+
+	expiresAt := now.Add(p.ttl)
+	for i := range points {
+		for j := range points {
+			if i == j {
+				continue
+			}
+
+			p.pairs[newPairKey(points[i], points[j])] = pairCost{
+				distance:  m.Distances[i][j],
+				duration:  m.Durations[i][j],
+				expiresAt: expiresAt,
+			}
+		}
+	}
+}
+
+func (p *CachedProvider) evictExpired(now time.Time) {
+	for k, c := range p.pairs {
+		if now.After(c.expiresAt) {
+			delete(p.pairs, k)
+		}
+	}
+}
+
+// FallbackProvider answers from fallback whenever primary fails, so an unreachable
+// routing engine degrades estimates to straight-line costs instead of failing them.
+// The Source of each matrix tells which of the two answered.
+type FallbackProvider struct {
+	primary  Provider
+	fallback Provider
+}
+
+func NewFallbackProvider(primary, fallback Provider) FallbackProvider {
+	return FallbackProvider{primary: primary, fallback: fallback}
+}
+
+func (p FallbackProvider) Name() string {
+	return p.primary.Name()
+}
+
+func (p FallbackProvider) Matrix(ctx context.Context, points []utils.Point) (Matrix, error) {
+	m, err := p.primary.Matrix(ctx, points)
+	if err == nil {
+		 return m, nil
+	}
+
+	if ctx.Err() != nil {
+		 return Matrix{}, ctx.Err()
+	}
+
+	log.Warn().Err(err).Str("provider", p.primary.Name()).Msg("routing provider failed, using fallback")
+	return p.fallback.Matrix(ctx, points)
+}
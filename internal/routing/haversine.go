@@ -0,0 +1,47 @@
+package routing
+
+import (
+	"belimang/internal/utils"
+	"context"
+)
+
+const DefaultSpeedKmh = 40.0
+
+// HaversineProvider uses straight-line distances and a constant travel speed.
+// It needs no external service and is the fallback for the road-network providers.
+type HaversineProvider struct {
+	speedKmh float64
+}
+
+func NewHaversineProvider(speedKmh float64) HaversineProvider {
+	if speedKmh <= 0 {
+		 speedKmh = DefaultSpeedKmh
+	}
+
+	return HaversineProvider{speedKmh: speedKmh}
+}
+
+func (p HaversineProvider) Name() string {
+	return "haversine"
+}
+
+func (p HaversineProvider) Matrix(ctx context.Context, points []utils.Point) (Matrix, error) {
+	if err := ctx.Err(); err != nil {
+		 return Matrix{}, err
+	}
+
+	m := newMatrix(len(points), p.Name())
+	for i := range points {
+		for j := range points {
+			if i == j {
+				continue
+			}
+
+			d := utils.Haversine(points[i], points[j])
+			m.Distances[i][j] = d
+			m.Durations[i][j] = d / p.speedKmh * 60
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,107 @@
+package routing
+
+import (
+	"belimang/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OSRMProvider queries the table service of an OSRM compatible server.
+type OSRMProvider struct {
+	baseURL string
+	profile string
+	client  *http.Client
+}
+
+func NewOSRMProvider(baseURL, profile string, client *http.Client) OSRMProvider {
+	if profile == "" {
+		 profile = "driving"
+	}
+
+	if client == nil {
+		 client = http.DefaultClient
+	}
+
+	return OSRMProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		profile: profile,
+		client:  client,
+	}
+}
+
+func (p OSRMProvider) Name() string {
+	return "osrm"
+}
+
+type osrmTableResponse struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Distances [][]*float64 `json:"distances"`
+	Durations [][]*float64 `json:"durations"`
+}
+
+func (p OSRMProvider) Matrix(ctx context.Context, points []utils.Point) (Matrix, error) {
+	if err := ctx.Err(); err != nil {
+		 return Matrix{}, err
+	}
+
+	if len(points) < 2 {
+		 return newMatrix(len(points), p.Name()), nil
+	}
+
+	coords := make([]string, 0, len(points))
+	for _, pt := range points {
+		coords = append(coords,
+			strconv.FormatFloat(pt.Lon, 'f', 6, 64)+","+strconv.FormatFloat(pt.Lat, 'f', 6, 64),
+		)
+	}
+
+	url := fmt.Sprintf("%s/table/v1/%s/%s?annotations=distance,duration", p.baseURL, p.profile, strings.Join(coords, ";"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		 return Matrix{}, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		 return Matrix{}, fmt.Errorf("osrm table request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body := osrmTableResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		 return Matrix{}, fmt.Errorf("osrm table response invalid: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK || body.Code != "Ok" {
+		 return Matrix{}, fmt.Errorf("osrm table failed with %d %s: %s", res.StatusCode, body.Code, body.Message)
+	}
+
+	if len(body.Distances) != len(points) || len(body.Durations) != len(points) {
+		 return Matrix{}, fmt.Errorf("osrm table returned %d rows for %d points", len(body.Distances), len(points))
+	}
+
+	m := newMatrix(len(points), p.Name())
+	for i := range points {
+		if len(body.Distances[i]) != len(points) || len(body.Durations[i]) != len(points) {
+			 return Matrix{}, fmt.Errorf("osrm table row %d has wrong length", i)
+		}
+
+		for j := range points {
+			dist, dur := body.Distances[i][j], body.Durations[i][j]
+			if dist == nil || dur == nil {
+				 return Matrix{}, ErrNoRoute
+			}
+
+			// OSRM answers in meters and seconds
+			m.Distances[i][j] = *dist / 1000
+			m.Durations[i][j] = *dur / 60
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,114 @@
+package routing
+
+import (
+	"belimang/internal/utils"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testPoints = []utils.Point{
+	{Lat: -6.2, Lon: 106.8},
+	{Lat: -6.25, Lon: 106.85},
+}
+
+func TestOSRMMatrix(t *testing.T) {
+	var path, query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.RawQuery
+		w.Write([]byte(`{"code":"Ok","distances":[[0,3000],[3500,0]],"durations":[[0,600],[660,0]]}`))
+	}))
+	defer srv.Close()
+
+	m, err := NewOSRMProvider(srv.URL+"/", "", srv.Client()).Matrix(context.Background(), testPoints)
+	if err != nil {
+		 t.Fatalf("Matrix: %v", err)
+	}
+
+	if want := "/table/v1/driving/106.800000,-6.200000;106.850000,-6.250000"; path != want {
+		 t.Errorf("path = %q, want %q", path, want)
+	}
+	if want := "annotations=distance,duration"; query != want {
+		 t.Errorf("query = %q, want %q", query, want)
+	}
+
+	if m.Source != "osrm" {
+		 t.Errorf("source = %q, want osrm", m.Source)
+	}
+
+	// meters and seconds come back as kilometers and minutes
+	if m.Distances[0][1] != 3 || m.Distances[1][0] != 3.5 {
+		 t.Errorf("distances = %v", m.Distances)
+	}
+	if m.Durations[0][1] != 10 || m.Durations[1][0] != 11 {
+		 t.Errorf("durations = %v", m.Durations)
+	}
+}
+
+func TestOSRMMatrixNoRoute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":"Ok","distances":[[0,null],[3500,0]],"durations":[[0,null],[660,0]]}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewOSRMProvider(srv.URL, "", srv.Client()).Matrix(context.Background(), testPoints)
+	if !errors.Is(err, ErrNoRoute) {
+		 t.Fatalf("err = %v, want ErrNoRoute", err)
+	}
+}
+
+func TestOSRMMatrixErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"error code", http.StatusBadRequest, `{"code":"InvalidQuery","message":"bad coordinates"}`},
+		{"not ok", http.StatusOK, `{"code":"NoTable"}`},
+		{"invalid body", http.StatusBadGateway, `<html>bad gateway</html>`},
+		{"short table", http.StatusOK, `{"code":"Ok","distances":[[0,1]],"durations":[[0,1]]}`},
+		{"short row", http.StatusOK, `{"code":"Ok","distances":[[0],[1,0]],"durations":[[0],[1,0]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := NewOSRMProvider(srv.URL, "", srv.Client()).Matrix(context.Background(), testPoints)
+			if err == nil {
+				 t.Fatal("Matrix succeeded, want error")
+			}
+			if errors.Is(err, ErrNoRoute) {
+				 t.Fatalf("err = %v, want a provider error", err)
+			}
+		})
+	}
+}
+
+func TestOSRMMatrixTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := srv.Client()
+	client.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := NewOSRMProvider(srv.URL, "", client).Matrix(context.Background(), testPoints)
+	if err == nil {
+		 t.Fatal("Matrix succeeded, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		 t.Fatalf("Matrix took %v, want it to give up at the client timeout", elapsed)
+	}
+}
@@ -0,0 +1,82 @@
+package routing
+
+import (
+	"belimang/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Matrix holds the travel cost between every pair of points passed to a Provider.
+// Distances are in kilometers and Durations in minutes, indexed [from][to].
+// Source names the provider that worked them out.
+type Matrix struct {
+	Distances [][]float64
+	Durations [][]float64
+	Source    string
+}
+
+// Provider computes travel distances and durations between points.
+type Provider interface {
+	Name() string
+	Matrix(ctx context.Context, points []utils.Point) (Matrix, error)
+}
+
+var ErrNoRoute = errors.New("routing: no route between points")
+
+func newMatrix(n int, source string) Matrix {
+	m := Matrix{
+		Distances: make([][]float64, n),
+		Durations: make([][]float64, n),
+		Source:    source,
+	}
+
+	for i := 0; i < n; i++ {
+		m.Distances[i] = make([]float64, n)
+		m.Durations[i] = make([]float64, n)
+	}
+
+	return m
+}
+
+// PathCost sums the distance and duration along the given visiting order.
+func (m Matrix) PathCost(order []int) (float64, float64) {
+	distance, duration := 0.0, 0.0
+	for i := 1; i < len(order); i++ {
+		distance += m.Distances[order[i-1]][order[i]]
+		duration += m.Durations[order[i-1]][order[i]]
+	}
+
+	return distance, duration
+}
+
+// New builds the provider named by kind. Road-network providers are cached for
+// cacheTTL and fall back to straight-line costs when the engine is unavailable.
+func New(kind, baseURL, profile string, timeout, cacheTTL time.Duration) (Provider, error) {
+	haversine := NewHaversineProvider(DefaultSpeedKmh)
+	client := &http.Client{Timeout: timeout}
+
+	var road Provider
+	switch kind {
+	case "", "haversine":
+		return haversine, nil
+	case "osrm":
+		road = NewOSRMProvider(baseURL, profile, client)
+	case "valhalla":
+		road = NewValhallaProvider(baseURL, profile, client)
+	default:
+		return nil, fmt.Errorf("unknown routing provider %q", kind)
+	}
+
+	if baseURL == "" {
+		 return nil, fmt.Errorf("routing provider %q requires ROUTING_URL", kind)
+	}
+
+	if cacheTTL > 0 {
+		 road = NewCachedProvider(road, cacheTTL, 0)
+	}
+
+	return NewFallbackProvider(road, haversine), nil
+}
@@ -0,0 +1,130 @@
+package routing
+
+import (
+	"belimang/internal/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ValhallaProvider queries the sources_to_targets matrix service of a Valhalla server.
+type ValhallaProvider struct {
+	baseURL string
+	costing string
+	client  *http.Client
+}
+
+func NewValhallaProvider(baseURL, costing string, client *http.Client) ValhallaProvider {
+	if costing == "" {
+		 costing = "auto"
+	}
+
+	if client == nil {
+		 client = http.DefaultClient
+	}
+
+	return ValhallaProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		costing: costing,
+		client:  client,
+	}
+}
+
+func (p ValhallaProvider) Name() string {
+	return "valhalla"
+}
+
+type valhallaLocation struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+type valhallaMatrixRequest struct {
+	Sources []valhallaLocation `json:"sources"`
+	Targets []valhallaLocation `json:"targets"`
+	Costing string             `json:"costing"`
+}
+
+type valhallaMatrixResponse struct {
+	Error           string `json:"error"`
+	SourcesToTarget [][]struct {
+		Distance  *float64 `json:"distance"`
+		Time      *float64 `json:"time"`
+		FromIndex int      `json:"from_index"`
+		ToIndex   int      `json:"to_index"`
+	} `json:"sources_to_targets"`
+}
+
+func (p ValhallaProvider) Matrix(ctx context.Context, points []utils.Point) (Matrix, error) {
+	if err := ctx.Err(); err != nil {
+		 return Matrix{}, err
+	}
+
+	if len(points) < 2 {
+		 return newMatrix(len(points), p.Name()), nil
+	}
+
+	locations := make([]valhallaLocation, 0, len(points))
+	for _, pt := range points {
+		locations = append(locations, valhallaLocation{Lat: pt.Lat, Lon: pt.Lon})
+	}
+
+	payload, err := json.Marshal(valhallaMatrixRequest{Sources: locations, Targets: locations, Costing: p.costing})
+	if err != nil {
+		 return Matrix{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/sources_to_targets", bytes.NewReader(payload))
+	if err != nil {
+		 return Matrix{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		 return Matrix{}, fmt.Errorf("valhalla matrix request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	body := valhallaMatrixResponse{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		 return Matrix{}, fmt.Errorf("valhalla matrix response invalid: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		 return Matrix{}, fmt.Errorf("valhalla matrix failed with %d: %s", res.StatusCode, body.Error)
+	}
+
+	m := newMatrix(len(points), p.Name())
+	filled := make([]bool, len(points)*len(points))
+	for _, row := range body.SourcesToTarget {
+		for _, cell := range row {
+			if cell.FromIndex < 0 || cell.FromIndex >= len(points) || cell.ToIndex < 0 || cell.ToIndex >= len(points) {
+				 return Matrix{}, fmt.Errorf("valhalla matrix index out of range")
+			}
+			filled[cell.FromIndex*len(points)+cell.ToIndex] = true
+
+			if cell.Distance == nil || cell.Time == nil {
+				 return Matrix{}, ErrNoRoute
+			}
+
+			// Valhalla answers in kilometers and seconds
+			m.Distances[cell.FromIndex][cell.ToIndex] = *cell.Distance
+			m.Durations[cell.FromIndex][cell.ToIndex] = *cell.Time / 60
+		}
+	}
+
+	// a pair Valhalla left out would otherwise read as a free leg
+	for i := range points {
+		for j := range points {
+			if i != j && !filled[i*len(points)+j] {
+				 return Matrix{}, fmt.Errorf("valhalla matrix is missing %d to %d", i, j)
+			}
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,109 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValhallaMatrix(t *testing.T) {
+	var got valhallaMatrixRequest
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"sources_to_targets":[
+			[{"from_index":0,"to_index":0,"distance":0,"time":0},{"from_index":0,"to_index":1,"distance":3,"time":600}],
+			[{"from_index":1,"to_index":0,"distance":3.5,"time":660},{"from_index":1,"to_index":1,"distance":0,"time":0}]
+		]}`))
+	}))
+	defer srv.Close()
+
+	m, err := NewValhallaProvider(srv.URL+"/", "", srv.Client()).Matrix(context.Background(), testPoints)
+	if err != nil {
+		 t.Fatalf("Matrix: %v", err)
+	}
+
+	if path != "/sources_to_targets" {
+		 t.Errorf("path = %q, want /sources_to_targets", path)
+	}
+	if got.Costing != "auto" || len(got.Sources) != 2 || len(got.Targets) != 2 || got.Sources[1].Lat != testPoints[1].Lat {
+		 t.Errorf("request = %+v", got)
+	}
+
+	if m.Source != "valhalla" {
+		 t.Errorf("source = %q, want valhalla", m.Source)
+	}
+
+	// kilometers stay kilometers, seconds come back as minutes
+	if m.Distances[0][1] != 3 || m.Distances[1][0] != 3.5 {
+		 t.Errorf("distances = %v", m.Distances)
+	}
+	if m.Durations[0][1] != 10 || m.Durations[1][0] != 11 {
+		 t.Errorf("durations = %v", m.Durations)
+	}
+}
+
+func TestValhallaMatrixNoRoute(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sources_to_targets":[[{"from_index":0,"to_index":1,"distance":null,"time":null}]]}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewValhallaProvider(srv.URL, "", srv.Client()).Matrix(context.Background(), testPoints)
+	if !errors.Is(err, ErrNoRoute) {
+		 t.Fatalf("err = %v, want ErrNoRoute", err)
+	}
+}
+
+func TestValhallaMatrixErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"error status", http.StatusBadRequest, `{"error":"Failed to parse json request"}`},
+		{"invalid body", http.StatusBadGateway, `<html>bad gateway</html>`},
+		{"index out of range", http.StatusOK, `{"sources_to_targets":[[{"from_index":0,"to_index":2,"distance":1,"time":60}]]}`},
+		{"missing pair", http.StatusOK, `{"sources_to_targets":[[{"from_index":0,"to_index":1,"distance":1,"time":60}]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := NewValhallaProvider(srv.URL, "", srv.Client()).Matrix(context.Background(), testPoints)
+			if err == nil {
+				 t.Fatal("Matrix succeeded, want error")
+			}
+			if errors.Is(err, ErrNoRoute) {
+				 t.Fatalf("err = %v, want a provider error", err)
+			}
+		})
+	}
+}
+
+func TestValhallaMatrixTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := NewValhallaProvider(srv.URL, "", srv.Client()).Matrix(ctx, testPoints)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		 t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AddressService keeps the delivery addresses a user has saved.
+type AddressService struct {
+	repository repository.AddressRepository
+}
+
+func NewAddressService(repository repository.AddressRepository) AddressService {
+	return AddressService{repository: repository}
+}
+
+func (s AddressService) GetAddresses(ctx context.Context, userID string) ([]dto.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	addresses, err := s.repository.GetAddresses(ctx, userID)
+	if err != nil {
+		 return nil, err
+	}
+
+	resp := make([]dto.Address, 0, len(addresses))
+	for _, a := range addresses {
+		resp = append(resp, toAddressDTO(a))
+	}
+
+	return resp, nil
+}
+
+func (s AddressService) GetAddress(ctx context.Context, userID, addressID string) (dto.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Address{}, err
+	}
+
+	a, err := s.ownedAddress(ctx, userID, addressID)
+	if err != nil {
+		 return dto.Address{}, err
+	}
+
+	return toAddressDTO(a), nil
+}
+
+func (s AddressService) CreateAddress(ctx context.Context, userID string, req dto.AddressRequest) (dto.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Address{}, err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.Address{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	a, err := s.repository.CreateAddress(ctx, tx, toAddressEntity(userID, req))
+	if err != nil {
+		 return dto.Address{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.Address{}, err
+	}
+
+	return toAddressDTO(a), nil
+}
+
+func (s AddressService) UpdateAddress(ctx context.Context, userID, addressID string, req dto.AddressRequest) (dto.Address, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Address{}, err
+	}
+
+	if _, err := uuid.Parse(addressID); err != nil {
+		 return dto.Address{}, utils.NewNotFound("address does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.Address{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	a := toAddressEntity(userID, req)
+	a.ID = addressID
+	a, err = s.repository.UpdateAddress(ctx, tx, a)
+	if err != nil {
+		 return dto.Address{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.Address{}, err
+	}
+
+	return toAddressDTO(a), nil
+}
+
+func (s AddressService) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if _, err := uuid.Parse(addressID); err != nil {
+		 return utils.NewNotFound("address does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.repository.DeleteAddress(ctx, tx, addressID, userID); err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delivery works out where an order for userID goes: the saved address
+// addressID when given, the raw location otherwise. Exactly one must be set.
+func (s AddressService) Delivery(ctx context.Context, userID, addressID string, location *dto.Location) (entities.Delivery, error) {
+	if (addressID == "") == (location == nil) {
+		 return entities.Delivery{}, utils.NewBadRequest("exactly one of addressId or userLocation is required")
+	}
+
+	if location != nil {
+		 return entities.Delivery{Location: entities.Location{Lat: location.Lat, Lon: location.Lon}}, nil
+	}
+
+	a, err := s.ownedAddress(ctx, userID, addressID)
+	if err != nil {
+		 return entities.Delivery{}, err
+	}
+
+	return entities.Delivery{
+		AddressID: &a.ID,
+		Location:  a.Location,
+		Building:  a.Building,
+		Floor:     a.Floor,
+		Notes:     a.Notes,
+	}, nil
+}
+
+func toAddressEntity(userID string, req dto.AddressRequest) entities.Address {
+	return entities.Address{
+		UserID:   userID,
+		Label:    req.Label,
+		Location: entities.Location{Lat: req.Location.Lat, Lon: req.Location.Lon},
+		Building: req.Building,
+		Floor:    req.Floor,
+		Notes:    req.Notes,
+	}
+}
+
+func toAddressDTO(a entities.Address) dto.Address {
+	return dto.Address{
+		ID:        a.ID,
+		Label:     a.Label,
+		Location:  dto.Location{Lat: a.Location.Lat, Lon: a.Location.Lon},
+		Building:  a.Building,
+		Floor:     a.Floor,
+		Notes:     a.Notes,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+func toDeliveryDTO(d entities.Delivery) dto.Delivery {
+	return dto.Delivery{
+		AddressID: d.AddressID,
+		Location:  dto.Location{Lat: d.Location.Lat, Lon: d.Location.Lon},
+		Building:  d.Building,
+		Floor:     d.Floor,
+		Notes:     d.Notes,
+	}
+}
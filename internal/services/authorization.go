@@ -0,0 +1,111 @@
+package services
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// authorizeOwner rejects access to a resource owned by someone else. It answers
+// with not found rather than forbidden so callers cannot probe which ids exist.
+// Operations on a user's resources load them through one of the owned helpers
+// below, which all end here, instead of calling the repository directly.
+func authorizeOwner(ownerID, userID, resource string) error {
+	if userID == "" || ownerID != userID {
+		 return utils.NewNotFound(resource + " does not exist")
+	}
+	return nil
+}
+
+// ownedEstimate loads an estimate on behalf of userID.
+func (s PurchaseService) ownedEstimate(ctx context.Context, userID, estimateID string) (entities.Estimate, error) {
+	estimate, err := s.repository.GetEstimateDataByID(ctx, estimateID)
+	if err != nil {
+		 return entities.Estimate{}, utils.NewNotFound("estimate does not exist")
+	}
+
+	if err := authorizeOwner(estimate.UserID, userID, "estimate"); err != nil {
+		 return entities.Estimate{}, err
+	}
+
+	return estimate, nil
+}
+
+// ownedOrder checks that orderID was placed by userID.
+func (s OrderService) ownedOrder(ctx context.Context, userID, orderID string) error {
+	ownerID, err := s.repository.GetOrderOwner(ctx, orderID)
+	if err != nil {
+		 return utils.NewNotFound("order does not exist")
+	}
+
+	return authorizeOwner(ownerID, userID, "order")
+}
+
+// ownedMerchant checks that userID runs merchantID.
+func (s MerchantOrderService) ownedMerchant(ctx context.Context, userID, merchantID string) error {
+	ownerID, err := s.merchants.GetMerchantOwner(ctx, merchantID)
+	if err != nil {
+		 return utils.NewNotFound("merchant does not exist")
+	}
+	if ownerID == nil {
+		 return utils.NewNotFound("merchant does not exist")
+	}
+
+	return authorizeOwner(*ownerID, userID, "merchant")
+}
+
+// ownedAddress loads a saved address on behalf of userID.
+func (s AddressService) ownedAddress(ctx context.Context, userID, addressID string) (entities.Address, error) {
+	if _, err := uuid.Parse(addressID); err != nil {
+		 return entities.Address{}, utils.NewNotFound("address does not exist")
+	}
+
+	address, err := s.repository.GetAddress(ctx, addressID)
+	if err != nil {
+		 return entities.Address{}, utils.NewNotFound("address does not exist")
+	}
+
+	if err := authorizeOwner(address.UserID, userID, "address"); err != nil {
+		 return entities.Address{}, err
+	}
+
+	return address, nil
+}
+
+// reviewableOrder loads an order of userID for reviewing it.
+func (s ReviewService) reviewableOrder(ctx context.Context, userID, orderID string) (entities.ReviewableOrder, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return entities.ReviewableOrder{}, utils.NewNotFound("order does not exist")
+	}
+
+	order, err := s.repository.GetReviewableOrder(ctx, orderID)
+	if err != nil {
+		 return entities.ReviewableOrder{}, utils.NewNotFound("order does not exist")
+	}
+
+	if err := authorizeOwner(order.UserID, userID, "order"); err != nil {
+		 return entities.ReviewableOrder{}, err
+	}
+
+	return order, nil
+}
+
+// ownedReceipt loads the receipt of an order on behalf of userID.
+func (s ReceiptService) ownedReceipt(ctx context.Context, userID, orderID string) (entities.Receipt, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return entities.Receipt{}, utils.NewNotFound("order does not exist")
+	}
+
+	rc, err := s.repository.GetReceipt(ctx, orderID)
+	if err != nil {
+		 return entities.Receipt{}, utils.NewNotFound("order does not exist")
+	}
+
+	if err := authorizeOwner(rc.UserID, userID, "order"); err != nil {
+		 return entities.Receipt{}, err
+	}
+
+	return rc, nil
+}
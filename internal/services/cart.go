@@ -0,0 +1,212 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxCartQuantity caps how many of one item a cart holds.
+const maxCartQuantity = 99
+
+// CartService keeps a cart per user on the server, so it survives restarts and
+// follows the user across devices. Prices and availability are never trusted
+// from the cart itself: every read and every estimate looks at the items as
+// they are now.
+type CartService struct {
+	repository repository.CartRepository
+	purchases  PurchaseService
+}
+
+func NewCartService(repository repository.CartRepository, purchases PurchaseService) CartService {
+	return CartService{
+		repository: repository,
+		purchases:  purchases,
+	}
+}
+
+func (s CartService) GetCart(ctx context.Context, userID string) (dto.CartResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CartResponse{}, err
+	}
+
+	lines, err := s.repository.GetCart(ctx, userID)
+	if err != nil {
+		 return dto.CartResponse{}, err
+	}
+
+	return toCartDTO(lines), nil
+}
+
+func (s CartService) AddItem(ctx context.Context, userID string, req dto.AddCartItemRequest) (dto.CartResponse, error) {
+	return s.update(ctx, userID, func(tx pgx.Tx) error {
+		return s.repository.AddItem(ctx, tx, userID, req.ItemID, req.Quantity, maxCartQuantity)
+	})
+}
+
+func (s CartService) UpdateItem(ctx context.Context, userID, itemID string, req dto.UpdateCartItemRequest) (dto.CartResponse, error) {
+	if _, err := uuid.Parse(itemID); err != nil {
+		 return dto.CartResponse{}, utils.NewNotFound("cart item does not exist")
+	}
+
+	return s.update(ctx, userID, func(tx pgx.Tx) error {
+		return s.repository.UpdateQuantity(ctx, tx, userID, itemID, req.Quantity)
+	})
+}
+
+func (s CartService) RemoveItem(ctx context.Context, userID, itemID string) (dto.CartResponse, error) {
+	if _, err := uuid.Parse(itemID); err != nil {
+		 return dto.CartResponse{}, utils.NewNotFound("cart item does not exist")
+	}
+
+	return s.update(ctx, userID, func(tx pgx.Tx) error {
+		return s.repository.RemoveItem(ctx, tx, userID, itemID)
+	})
+}
+
+func (s CartService) ClearCart(ctx context.Context, userID string) (dto.CartResponse, error) {
+	return s.update(ctx, userID, func(tx pgx.Tx) error {
+		return s.repository.ClearCart(ctx, tx, userID)
+	})
+}
+
+// update runs change in a transaction and returns the cart as it is afterwards.
+func (s CartService) update(ctx context.Context, userID string, change func(tx pgx.Tx) error) (dto.CartResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CartResponse{}, err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.CartResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := change(tx); err != nil {
+		 return dto.CartResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.CartResponse{}, err
+	}
+
+	return s.GetCart(ctx, userID)
+}
+
+// CreateEstimate quotes the whole cart. It refuses when any item has become
+// unavailable so the user can fix the cart first; price changes need no
+// handling since the estimate is priced from the items as they are now.
+func (s CartService) CreateEstimate(ctx context.Context, userID string, req dto.CartEstimateRequest) (dto.EstimateRes, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.EstimateRes{}, err
+	}
+
+	lines, err := s.repository.GetCart(ctx, userID)
+	if err != nil {
+		 return dto.EstimateRes{}, err
+	}
+	if len(lines) == 0 {
+		 return dto.EstimateRes{}, utils.NewBadRequest("cart is empty")
+	}
+
+	unavailable := make([]string, 0)
+	for _, l := range lines {
+		if !l.Item.IsAvailable {
+			unavailable = append(unavailable, l.Item.Name)
+		}
+	}
+	if len(unavailable) > 0 {
+		 return dto.EstimateRes{}, utils.NewBadRequest(fmt.Sprintf("items are no longer available: %s", strings.Join(unavailable, ", ")))
+	}
+
+	startID := req.StartMerchantID
+	if startID == "" {
+		 startID = lines[0].MerchantID
+	}
+
+	orders := make([]dto.EstimateOrder, 0)
+	orderIdx := make(map[string]int)
+	for _, l := range lines {
+		idx, ok := orderIdx[l.MerchantID]
+		if !ok {
+			idx = len(orders)
+			orderIdx[l.MerchantID] = idx
+			orders = append(orders, dto.EstimateOrder{
+				MerchantID:      l.MerchantID,
+				IsStartingPoint: l.MerchantID == startID,
+				OrderItems:      []dto.EstimateOrderItem{},
+			})
+		}
+
+		orders[idx].OrderItems = append(orders[idx].OrderItems, dto.EstimateOrderItem{
+			ItemID:       l.ItemID,
+			ItemQuantity: l.Quantity,
+		})
+	}
+
+	if _, ok := orderIdx[startID]; !ok {
+		 return dto.EstimateRes{}, utils.NewBadRequest("startMerchantId is not in the cart")
+	}
+
+	return s.purchases.CreateEstimate(ctx, dto.EstimateReq{
+		UserID:       userID,
+		UserPurchase: orders,
+		AddressID:    req.AddressID,
+		UserLocation: req.UserLocation,
+		PromoCode:    req.PromoCode,
+		ScheduledFor: req.ScheduledFor,
+	})
+}
+
+func toCartDTO(lines []entities.CartLine) dto.CartResponse {
+	resp := dto.CartResponse{Merchants: make([]dto.CartMerchant, 0)}
+	merchantIdx := make(map[string]int)
+	for _, l := range lines {
+		idx, ok := merchantIdx[l.MerchantID]
+		if !ok {
+			idx = len(resp.Merchants)
+			merchantIdx[l.MerchantID] = idx
+			resp.Merchants = append(resp.Merchants, dto.CartMerchant{
+				Merchant: dto.Merchant{
+					ID:        l.Merchant.ID,
+					Name:      l.Merchant.Name,
+					Category:  l.Merchant.Category,
+					ImageURL:  l.Merchant.ImageURL,
+					Location:  dto.Location{Lat: l.Merchant.Location.Lat, Lon: l.Merchant.Location.Lon},
+					CreatedAt: l.Merchant.CreatedAt,
+				},
+				Items: []dto.CartItem{},
+			})
+		}
+
+		item := dto.CartItem{
+			ItemID:          l.ItemID,
+			Name:            l.Item.Name,
+			ProductCategory: l.Item.Category,
+			ImageURL:        l.Item.ImageURL,
+			Quantity:        l.Quantity,
+			Price:           l.Item.Price,
+			PriceWhenAdded:  l.PriceAtAdd,
+			PriceChanged:    l.Item.Price != l.PriceAtAdd,
+			IsAvailable:     l.Item.IsAvailable,
+			AddedAt:         l.CreatedAt,
+		}
+
+		m := &resp.Merchants[idx]
+		m.Items = append(m.Items, item)
+		if item.IsAvailable {
+			m.Subtotal += item.Price * item.Quantity
+			resp.TotalPrice += item.Price * item.Quantity
+		}
+		resp.Changed = resp.Changed || item.PriceChanged || !item.IsAvailable
+	}
+
+	return resp
+}
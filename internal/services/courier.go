@@ -0,0 +1,238 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/events"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// CourierService covers the courier's side of dispatch: going on and off
+// shift, reporting position and answering offers.
+type CourierService struct {
+	repository repository.CourierRepository
+	events     events.Bus
+}
+
+func NewCourierService(repository repository.CourierRepository, bus events.Bus) CourierService {
+	return CourierService{
+		repository: repository,
+		events:     bus,
+	}
+}
+
+// CreateCourier lets userID take deliveries. New couriers start Offline.
+func (s CourierService) CreateCourier(ctx context.Context, userID string) (dto.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Courier{}, err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	courier, err := s.repository.CreateCourier(ctx, tx, userID)
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.Courier{}, err
+	}
+
+	return toCourierDTO(courier), nil
+}
+
+func (s CourierService) GetMe(ctx context.Context, userID string) (dto.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Courier{}, err
+	}
+
+	courier, err := s.repository.GetCourierByUser(ctx, userID)
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+
+	return toCourierDTO(courier), nil
+}
+
+func (s CourierService) SetAvailability(ctx context.Context, userID string, available bool) (dto.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Courier{}, err
+	}
+
+	courier, err := s.repository.GetCourierByUser(ctx, userID)
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+
+	status := entities.CourierOffline
+	if available {
+		 status = entities.CourierAvailable
+	}
+
+	courier, err = s.repository.SetCourierAvailability(ctx, courier.ID, status)
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+
+	return toCourierDTO(courier), nil
+}
+
+// Heartbeat records where the courier is. Couriers that stop sending
+// heartbeats drop out of dispatch after a while without going Offline.
+func (s CourierService) Heartbeat(ctx context.Context, userID string, loc dto.Location) (dto.Courier, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Courier{}, err
+	}
+
+	courier, err := s.repository.GetCourierByUser(ctx, userID)
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+
+	courier, err = s.repository.RecordHeartbeat(ctx, courier.ID, entities.Location{Lat: loc.Lat, Lon: loc.Lon})
+	if err != nil {
+		 return dto.Courier{}, err
+	}
+
+	return toCourierDTO(courier), nil
+}
+
+func (s CourierService) GetOffers(ctx context.Context, userID string) ([]dto.DispatchOffer, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	courier, err := s.repository.GetCourierByUser(ctx, userID)
+	if err != nil {
+		 return nil, err
+	}
+
+	offers, err := s.repository.GetPendingOffers(ctx, courier.ID)
+	if err != nil {
+		 return nil, err
+	}
+
+	resp := make([]dto.DispatchOffer, 0, len(offers))
+	for _, o := range offers {
+		resp = append(resp, toDispatchOfferDTO(o))
+	}
+
+	return resp, nil
+}
+
+// AcceptOffer assigns the offered order to the courier, provided the offer is
+// still open and nobody else got the order first.
+func (s CourierService) AcceptOffer(ctx context.Context, userID, offerID string) (dto.DispatchOffer, error) {
+	offer, err := s.respond(ctx, userID, offerID, entities.OfferAccepted)
+	if err != nil {
+		 return dto.DispatchOffer{}, err
+	}
+
+	e, err := events.NewEvent(events.TypeCourierAssigned, offer.OrderID, map[string]string{"courierId": offer.CourierID})
+	if err == nil {
+		 err = s.events.Publish(context.WithoutCancel(ctx), e)
+	}
+	if err != nil {
+		 log.Error().Err(err).Str("orderId", offer.OrderID).Msg("failed to publish courier assignment")
+	}
+
+	return toDispatchOfferDTO(offer), nil
+}
+
+// DeclineOffer turns the offer down. The dispatcher offers the order to the
+// next best courier on its following round.
+func (s CourierService) DeclineOffer(ctx context.Context, userID, offerID string) (dto.DispatchOffer, error) {
+	offer, err := s.respond(ctx, userID, offerID, entities.OfferDeclined)
+	if err != nil {
+		 return dto.DispatchOffer{}, err
+	}
+
+	return toDispatchOfferDTO(offer), nil
+}
+
+func (s CourierService) respond(ctx context.Context, userID, offerID, status string) (entities.DispatchOffer, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	if _, err := uuid.Parse(offerID); err != nil {
+		 return entities.DispatchOffer{}, utils.NewNotFound("offer does not exist")
+	}
+
+	courier, err := s.repository.GetCourierByUser(ctx, userID)
+	if err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	offer, err := s.repository.GetOfferForUpdate(ctx, tx, courier.ID, offerID)
+	if err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	if offer.Status != entities.OfferPending {
+		 return entities.DispatchOffer{}, utils.NewConflict(fmt.Sprintf("offer is already %s", offer.Status))
+	}
+	if offer.Expired {
+		 return entities.DispatchOffer{}, utils.NewGone("offer has expired")
+	}
+
+	if err := s.repository.RespondToOffer(ctx, tx, offer.ID, status); err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	if status == entities.OfferAccepted {
+		if err := s.repository.AssignCourier(ctx, tx, offer.OrderID, courier.ID); err != nil {
+			 return entities.DispatchOffer{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return entities.DispatchOffer{}, err
+	}
+
+	offer.Status = status
+	return offer, nil
+}
+
+func toCourierDTO(c entities.Courier) dto.Courier {
+	resp := dto.Courier{
+		CourierID:  c.ID,
+		UserID:     c.UserID,
+		Status:     c.Status,
+		LastSeenAt: c.LastSeenAt,
+		CreatedAt:  c.CreatedAt,
+	}
+	if c.Location != nil {
+		 resp.Location = &dto.Location{Lat: c.Location.Lat, Lon: c.Location.Lon}
+	}
+
+	return resp
+}
+
+func toDispatchOfferDTO(o entities.DispatchOffer) dto.DispatchOffer {
+	return dto.DispatchOffer{
+		OfferID:    o.ID,
+		OrderID:    o.OrderID,
+		Status:     o.Status,
+		ApproachKm: o.ApproachKm,
+		RouteKm:    o.RouteKm,
+		ExpiresAt:  o.ExpiresAt,
+		CreatedAt:  o.CreatedAt,
+	}
+}
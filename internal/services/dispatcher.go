@@ -0,0 +1,251 @@
+package services
+
+import (
+	"belimang/internal/dispatch"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/routing"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	dispatchBatch      = 20
+	dispatchCandidates = 10
+)
+
+// DispatchConfig tunes the dispatcher. Couriers are searched within RadiusKm of
+// the first pickup and must have sent a heartbeat within HeartbeatTTL. Each
+// offer stays open for OfferTimeout before the order moves to the next courier,
+// and a courier who let it pass is not offered that order again for
+// OfferCooldown.
+type DispatchConfig struct {
+	Interval      time.Duration
+	OfferTimeout  time.Duration
+	OfferCooldown time.Duration
+	RadiusKm      float64
+	HeartbeatTTL  time.Duration
+}
+
+// Dispatcher offers orders that merchants have accepted to the best nearby
+// courier, one courier at a time, until one of them takes the order.
+type Dispatcher struct {
+	repository repository.CourierRepository
+	routing    routing.Provider
+	score      dispatch.ScoreFunc
+	config     DispatchConfig
+}
+
+func NewDispatcher(repository repository.CourierRepository, provider routing.Provider, score dispatch.ScoreFunc, config DispatchConfig) Dispatcher {
+	return Dispatcher{
+		repository: repository,
+		routing:    provider,
+		score:      score,
+		config:     config,
+	}
+}
+
+// Run dispatches until ctx is cancelled. It is meant to be started in its own goroutine.
+func (d Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.round(ctx)
+		}
+	}
+}
+
+// round closes stale offers, frees couriers whose deliveries ended and then
+// makes a new offer for every order still without a courier.
+func (d Dispatcher) round(ctx context.Context) {
+	if _, err := d.repository.ExpireOffers(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to expire dispatch offers")
+		return
+	}
+
+	if _, err := d.repository.ReleaseCouriers(ctx); err != nil {
+		log.Error().Err(err).Msg("failed to release couriers")
+		return
+	}
+
+	offered, err := d.dispatchPending(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to dispatch orders")
+		return
+	}
+
+	if offered > 0 {
+		log.Info().Int("offers", offered).Msg("dispatched orders")
+	}
+}
+
+func (d Dispatcher) dispatchPending(ctx context.Context) (int, error) {
+	approaches, err := d.approaches(ctx)
+	if err != nil || len(approaches) == 0 {
+		 return 0, err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	orders, err := d.repository.ClaimDispatchableOrders(ctx, tx, dispatchBatch)
+	if err != nil {
+		 return 0, err
+	}
+
+	offered := 0
+	for _, order := range orders {
+		// orders that turned up since approaches were measured wait a round
+		measured, ok := approaches[order.OrderID]
+		if !ok {
+			 continue
+		}
+
+		// a savepoint per order keeps one failure from undoing the others
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			 return offered, err
+		}
+
+		ok, err = d.offer(ctx, sp, order, measured)
+		if err != nil {
+			log.Error().Err(err).Str("orderId", order.OrderID).Msg("failed to offer order")
+			if err := sp.Rollback(ctx); err != nil {
+				 return offered, err
+			}
+			continue
+		}
+
+		if err := sp.Commit(ctx); err != nil {
+			 return offered, err
+		}
+		if ok {
+			 offered++
+		}
+	}
+
+	return offered, tx.Commit(ctx)
+}
+
+// approaches measures how far each candidate of every order waiting for a
+// courier is from its pickup, keyed by order and courier. The routing provider
+// is a remote call, so the orders and couriers are only looked at here and the
+// locks are let go before it is asked.
+func (d Dispatcher) approaches(ctx context.Context) (map[string]map[string]entities.CourierCandidate, error) {
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	orders, err := d.repository.ClaimDispatchableOrders(ctx, tx, dispatchBatch)
+	if err != nil {
+		 return nil, err
+	}
+
+	candidates := make([][]entities.CourierCandidate, 0, len(orders))
+	for _, order := range orders {
+		found, err := d.repository.FindCandidates(ctx, tx, order, d.config.RadiusKm, d.config.HeartbeatTTL, d.config.OfferCooldown, dispatchCandidates)
+		if err != nil {
+			 return nil, err
+		}
+		candidates = append(candidates, found)
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		 return nil, err
+	}
+
+	approaches := make(map[string]map[string]entities.CourierCandidate, len(orders))
+	for i, order := range orders {
+		if len(candidates[i]) == 0 {
+			 continue
+		}
+
+		d.approach(ctx, order, candidates[i])
+		measured := make(map[string]entities.CourierCandidate, len(candidates[i]))
+		for _, c := range candidates[i] {
+			measured[c.CourierID] = c
+		}
+		approaches[order.OrderID] = measured
+	}
+
+	return approaches, nil
+}
+
+// offer picks the best candidate for order and offers it to them. ok is false
+// when no courier is around; the order is tried again next round. Candidates
+// without a measured approach count by straight-line distance.
+func (d Dispatcher) offer(ctx context.Context, tx pgx.Tx, order entities.DispatchOrder, measured map[string]entities.CourierCandidate) (bool, error) {
+	candidates, err := d.repository.FindCandidates(ctx, tx, order, d.config.RadiusKm, d.config.HeartbeatTTL, d.config.OfferCooldown, dispatchCandidates)
+	if err != nil || len(candidates) == 0 {
+		 return false, err
+	}
+
+	for i := range candidates {
+		if m, ok := measured[candidates[i].CourierID]; ok {
+			candidates[i].ApproachKm = m.ApproachKm
+			candidates[i].ApproachMinutes = m.ApproachMinutes
+		} else {
+			straightApproach(&candidates[i])
+		}
+	}
+
+	best := dispatch.Rank(order, candidates, d.score)[0]
+
+	_, err = d.repository.InsertOffer(ctx, tx, entities.DispatchOffer{
+		OrderID:    order.OrderID,
+		CourierID:  best.CourierID,
+		Score:      best.Score,
+		ApproachKm: best.ApproachKm,
+		RouteKm:    order.RouteKm,
+	}, d.config.OfferTimeout)
+	if err != nil {
+		 return false, err
+	}
+
+	return true, nil
+}
+
+// approach fills in each candidate's road distance and time to the pickup.
+// When the routing provider fails the straight-line distance at the default
+// speed stands in, so dispatch never stalls on routing.
+func (d Dispatcher) approach(ctx context.Context, order entities.DispatchOrder, candidates []entities.CourierCandidate) {
+	points := make([]utils.Point, 0, len(candidates)+1)
+	points = append(points, utils.Point{Lat: order.Pickup.Lat, Lon: order.Pickup.Lon})
+	for _, c := range candidates {
+		points = append(points, utils.Point{Lat: c.Location.Lat, Lon: c.Location.Lon})
+	}
+
+	matrix, err := d.routing.Matrix(ctx, points)
+	if err != nil {
+		log.Warn().Err(err).Str("orderId", order.OrderID).Msg("routing failed, using straight-line approach")
+	}
+
+	for i := range candidates {
+		if err != nil {
+			straightApproach(&candidates[i])
+			continue
+		}
+
+		candidates[i].ApproachKm = matrix.Distances[i+1][0]
+		candidates[i].ApproachMinutes = matrix.Durations[i+1][0]
+	}
+}
+
+func straightApproach(c *entities.CourierCandidate) {
+	c.ApproachKm = c.StraightKm
+	c.ApproachMinutes = c.StraightKm / routing.DefaultSpeedKmh * 60
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"belimang/internal/repository"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const estimatePurgeBatch = 500
+
+// EstimatePurger periodically deletes estimates that expired more than retention
+// ago without being turned into an order, along with their orders_items.
+type EstimatePurger struct {
+	repository repository.PurchaseRepository
+	interval   time.Duration
+	retention  time.Duration
+}
+
+func NewEstimatePurger(repository repository.PurchaseRepository, interval, retention time.Duration) EstimatePurger {
+	return EstimatePurger{
+		repository: repository,
+		interval:   interval,
+		retention:  retention,
+	}
+}
+
+// Run purges until ctx is cancelled. It is meant to be started in its own goroutine.
+func (p EstimatePurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p EstimatePurger) purge(ctx context.Context) {
+	totalEstimates, totalItems := 0, 0
+	for ctx.Err() == nil {
+		estimates, items, err := p.repository.PurgeStaleEstimates(ctx, p.retention, estimatePurgeBatch)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to purge stale estimates")
+			return
+		}
+
+		totalEstimates += estimates
+		totalItems += items
+		if estimates < estimatePurgeBatch {
+			break
+		}
+	}
+
+	if totalEstimates > 0 {
+		log.Info().Int("estimates", totalEstimates).Int("items", totalItems).Msg("purged stale estimates")
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/eta"
+	"belimang/internal/repository"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ETAService predicts delivery times with the latest calibrated model and
+// recalibrates it from completed orders.
+type ETAService struct {
+	repository repository.ETARepository
+	store      *eta.Store
+}
+
+func NewETAService(repository repository.ETARepository) ETAService {
+	return ETAService{
+		repository: repository,
+		store:      eta.NewStore(eta.Default()),
+	}
+}
+
+// Estimate predicts the delivery of a route through merchants for a kitchen
+// starting at at. Each merchant's learned median preparation time is used when
+// there is one and its configured prep_minutes otherwise.
+func (s ETAService) Estimate(ctx context.Context, merchants []entities.Merchant, distanceKm, routedMinutes float64, at time.Time) (eta.Estimate, error) {
+	in, err := s.input(ctx, merchants, distanceKm, routedMinutes)
+	if err != nil {
+		 return eta.Estimate{}, err
+	}
+
+	in.At = at
+	return eta.Predict(s.store.Load(), in), nil
+}
+
+// EstimateFor predicts the same for an order to arrive at deliverAt. The hour
+// of day factors need when the kitchen starts, which depends on the prediction
+// itself, so a first pass from deliverAt gives the duration to count back by.
+func (s ETAService) EstimateFor(ctx context.Context, merchants []entities.Merchant, distanceKm, routedMinutes float64, deliverAt time.Time) (eta.Estimate, error) {
+	in, err := s.input(ctx, merchants, distanceKm, routedMinutes)
+	if err != nil {
+		 return eta.Estimate{}, err
+	}
+
+	model := s.store.Load()
+	in.At = deliverAt
+	first := eta.Predict(model, in)
+
+	in.At = deliverAt.Add(-time.Duration(first.Minutes()) * time.Minute)
+	return eta.Predict(model, in), nil
+}
+
+func (s ETAService) input(ctx context.Context, merchants []entities.Merchant, distanceKm, routedMinutes float64) (eta.Input, error) {
+	ids := make([]string, 0, len(merchants))
+	for _, m := range merchants {
+		ids = append(ids, m.ID)
+	}
+
+	stats, err := s.repository.GetMerchantPrepStats(ctx, ids)
+	if err != nil {
+		 return eta.Input{}, err
+	}
+
+	prep := make([]float64, 0, len(merchants))
+	for _, m := range merchants {
+		if stat, ok := stats[m.ID]; ok {
+			prep = append(prep, stat.MedianMinutes)
+		} else {
+			prep = append(prep, float64(m.PrepMinutes))
+		}
+	}
+
+	return eta.Input{
+		PrepMinutes:   prep,
+		Stops:         len(merchants),
+		DistanceKm:    distanceKm,
+		RoutedMinutes: routedMinutes,
+	}, nil
+}
+
+// Reload swaps in the newest stored model. Without one the default stays in use.
+func (s ETAService) Reload(ctx context.Context) error {
+	m, err := s.repository.GetLatestModel(ctx)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	s.store.Set(m)
+	return nil
+}
+
+// Run reloads the model every interval so recalibrations reach running
+// servers, until ctx is cancelled. It is meant to be started in its own goroutine.
+func (s ETAService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				log.Error().Err(err).Msg("failed to reload eta model")
+			}
+		}
+	}
+}
+
+// Recalibrate learns a new model from the deliveries of the last window and
+// refreshes merchant preparation times over the same window. Hours, trips and
+// merchants with fewer than minSamples observations keep their fallbacks.
+func (s ETAService) Recalibrate(ctx context.Context, window time.Duration, minSamples int) (entities.ETAModel, int64, error) {
+	since := time.Now().Add(-window)
+
+	buckets, err := s.repository.GetSpeedBuckets(ctx, since, maxPingAccuracyM)
+	if err != nil {
+		 return entities.ETAModel{}, 0, err
+	}
+
+	trips, err := s.repository.GetTripSamples(ctx, since)
+	if err != nil {
+		 return entities.ETAModel{}, 0, err
+	}
+
+	model := eta.Calibrate(buckets, trips, time.Local, minSamples)
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return entities.ETAModel{}, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	model, err = s.repository.InsertModel(ctx, tx, model)
+	if err != nil {
+		 return entities.ETAModel{}, 0, err
+	}
+
+	merchants, err := s.repository.RefreshMerchantPrepStats(ctx, tx, since, minSamples)
+	if err != nil {
+		 return entities.ETAModel{}, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return entities.ETAModel{}, 0, err
+	}
+
+	s.store.Set(model)
+	return model, merchants, nil
+}
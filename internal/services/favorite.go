@@ -0,0 +1,125 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+)
+
+// FavoriteService keeps the merchants and items a user has starred.
+type FavoriteService struct {
+	repository repository.FavoriteRepository
+}
+
+func NewFavoriteService(repository repository.FavoriteRepository) FavoriteService {
+	return FavoriteService{repository: repository}
+}
+
+func (s FavoriteService) GetFavorites(ctx context.Context, userID string) (dto.FavoritesResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.FavoritesResponse{}, err
+	}
+
+	merchants, err := s.repository.GetFavoriteMerchants(ctx, userID)
+	if err != nil {
+		 return dto.FavoritesResponse{}, err
+	}
+
+	items, err := s.repository.GetFavoriteItems(ctx, userID)
+	if err != nil {
+		 return dto.FavoritesResponse{}, err
+	}
+
+	resp := dto.FavoritesResponse{
+		Merchants: make([]dto.Merchant, 0, len(merchants)),
+		Items:     make([]dto.FavoriteItem, 0, len(items)),
+	}
+	for _, m := range merchants {
+		resp.Merchants = append(resp.Merchants, dto.Merchant{
+			ID:        m.ID,
+			Name:      m.Name,
+			Category:  m.Category,
+			ImageURL:  m.ImageURL,
+			Location:  dto.Location{Lat: m.Location.Lat, Lon: m.Location.Lon},
+			CreatedAt: m.CreatedAt,
+		})
+	}
+	for _, it := range items {
+		resp.Items = append(resp.Items, dto.FavoriteItem{
+			MercItem: dto.MercItem{
+				ID:          it.ID,
+				Name:        it.Name,
+				Category:    it.Category,
+				ImageURL:    it.ImageURL,
+				Price:       it.Price,
+				CreateAt:    it.CreatedAt,
+				IsAvailable: it.IsAvailable,
+			},
+			MerchantID: it.MerchantID,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s FavoriteService) AddFavorite(ctx context.Context, userID string, req dto.FavoriteRequest) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if err := checkFavoriteRequest(req); err != nil {
+		 return err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if req.MerchantID != "" {
+		err = s.repository.AddFavoriteMerchant(ctx, tx, userID, req.MerchantID)
+	} else {
+		err = s.repository.AddFavoriteItem(ctx, tx, userID, req.ItemID)
+	}
+	if err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s FavoriteService) RemoveFavorite(ctx context.Context, userID string, req dto.FavoriteRequest) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if err := checkFavoriteRequest(req); err != nil {
+		 return err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if req.MerchantID != "" {
+		err = s.repository.RemoveFavoriteMerchant(ctx, tx, userID, req.MerchantID)
+	} else {
+		err = s.repository.RemoveFavoriteItem(ctx, tx, userID, req.ItemID)
+	}
+	if err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func checkFavoriteRequest(req dto.FavoriteRequest) error {
+	if (req.MerchantID == "") == (req.ItemID == "") {
+		 return utils.NewBadRequest("exactly one of merchantId or itemId is required")
+	}
+	return nil
+}
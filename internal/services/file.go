@@ -5,11 +5,16 @@ import (
 	"belimang/internal/dto"
 	"context"
 	"mime/multipart"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v7"
 )
 
+// presignedURLTTL is how long a link to a stored object keeps working.
+const presignedURLTTL = 7 * 24 * time.Hour
+
 type FileService struct {
 	minio *minio.Client
 	cfg   config.Config
@@ -38,7 +43,7 @@ func (s FileService) UploadImage(ctx context.Context, src multipart.File, file *
 		 return dto.FileResponse{}, err
 	}
 
-	url, err := s.minio.PresignedGetObject(ctx, bucketName, objectName, 7*24*time.Hour, nil)
+	link, err := s.PresignedURL(ctx, objectName)
 	if err != nil {
 		 return dto.FileResponse{}, err
 	}
@@ -46,7 +51,47 @@ func (s FileService) UploadImage(ctx context.Context, src multipart.File, file *
 	return dto.FileResponse{
 		Message: "File uploaded sucessfully",
 		Data: dto.UploadFileData{
-			ImageUrl: url.String(),
+			ImageUrl: link,
 		},
 	}, nil
 }
+
+// PresignedURL returns a link to objectName that works for presignedURLTTL.
+func (s FileService) PresignedURL(ctx context.Context, objectName string) (string, error) {
+	link, err := s.minio.PresignedGetObject(ctx, s.cfg.BucketName, objectName, presignedURLTTL, nil)
+	if err != nil {
+		 return "", err
+	}
+
+	return link.String(), nil
+}
+
+// ObjectName returns the name of the object a link handed out by PresignedURL
+// points at, or false when link points somewhere else.
+func (s FileService) ObjectName(link string) (string, bool) {
+	base, err := url.Parse(s.cfg.StorageURL())
+	if err != nil {
+		 return "", false
+	}
+
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != base.Scheme || u.Host != base.Host {
+		 return "", false
+	}
+
+	name, ok := strings.CutPrefix(u.Path, base.Path)
+	return name, ok && name != ""
+}
+
+// Exists reports whether objectName is in the bucket.
+func (s FileService) Exists(ctx context.Context, objectName string) (bool, error) {
+	_, err := s.minio.StatObject(ctx, s.cfg.BucketName, objectName, minio.StatObjectOptions{})
+	if err == nil {
+		 return true, nil
+	}
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		 return false, nil
+	}
+
+	return false, err
+}
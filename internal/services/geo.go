@@ -0,0 +1,138 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"time"
+)
+
+type GeoService struct {
+	repository repository.GeoRepository
+}
+
+func NewGeoService(repository repository.GeoRepository) GeoService {
+	return GeoService{repository: repository}
+}
+
+const (
+	// at or above this zoom level merchants are returned one by one
+	mapUnclusteredZoom = 15
+	maxMapMerchants    = 1000
+)
+
+// geohashPrecision maps a web map zoom level to a geohash length whose cells
+// are roughly the size of a few screen tiles at that zoom.
+func geohashPrecision(zoom int) int {
+	switch {
+	case zoom <= 2:
+		return 1
+	case zoom <= 4:
+		return 2
+	case zoom <= 7:
+		return 3
+	case zoom <= 9:
+		return 4
+	case zoom <= 12:
+		return 5
+	default:
+		return 6
+	}
+}
+
+func (s GeoService) GetMerchantMap(ctx context.Context, f entities.MapFilter) (dto.MapResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.MapResponse{}, err
+	}
+
+	res := dto.MapResponse{
+		Zoom:      f.Zoom,
+		Merchants: make([]dto.MapMerchant, 0),
+		Clusters:  make([]dto.MapCluster, 0),
+	}
+
+	if f.Zoom >= mapUnclusteredZoom {
+		merchants, err := s.repository.GetMerchantsInBBox(ctx, f, maxMapMerchants+1)
+		if err != nil {
+			 return dto.MapResponse{}, err
+		}
+
+		if len(merchants) > maxMapMerchants {
+			res.Truncated = true
+			merchants = merchants[:maxMapMerchants]
+		}
+
+		for _, m := range merchants {
+			res.Merchants = append(res.Merchants, dto.MapMerchant{
+				ID:       m.ID,
+				Name:     m.Name,
+				Category: m.Category,
+				ImageURL: m.ImageURL,
+				Location: dto.Location{Lat: m.Location.Lat, Lon: m.Location.Lon},
+			})
+		}
+
+		return res, nil
+	}
+
+	clusters, err := s.repository.GetMerchantClustersInBBox(ctx, f, geohashPrecision(f.Zoom))
+	if err != nil {
+		 return dto.MapResponse{}, err
+	}
+
+	res.Clustered = true
+	for _, c := range clusters {
+		res.Clusters = append(res.Clusters, dto.MapCluster{
+			ID:       c.Cell,
+			Count:    c.Count,
+			Location: dto.Location{Lat: c.Location.Lat, Lon: c.Location.Lon},
+			Bounds:   [4]float64{c.Bounds.MinLon, c.Bounds.MinLat, c.Bounds.MaxLon, c.Bounds.MaxLat},
+		})
+	}
+
+	return res, nil
+}
+
+func (s GeoService) ExportMerchantsGeoJSON(ctx context.Context, category string) (dto.GeoJSONFeatureCollection, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.GeoJSONFeatureCollection{}, err
+	}
+
+	merchants, err := s.repository.GetAllMerchantLocations(ctx, category)
+	if err != nil {
+		 return dto.GeoJSONFeatureCollection{}, err
+	}
+
+	features := make([]dto.GeoJSONFeature, 0, len(merchants))
+	for _, m := range merchants {
+		features = append(features, dto.NewPointFeature(m.ID, dto.Location{Lat: m.Location.Lat, Lon: m.Location.Lon}, map[string]any{
+			"name":             m.Name,
+			"merchantCategory": m.Category,
+			"imageUrl":         m.ImageURL,
+			"createdAt":        m.CreatedAt.Format(time.RFC3339Nano),
+		}))
+	}
+
+	return dto.GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}, nil
+}
+
+func (s GeoService) GetMerchantTile(ctx context.Context, z, x, y int) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		 return nil, err
+	}
+
+	if z < 0 || z > 22 {
+		 return nil, utils.NewBadRequest("zoom must be between 0 and 22")
+	}
+
+	if n := 1 << z; x < 0 || x >= n || y < 0 || y >= n {
+		 return nil, utils.NewBadRequest("tile coordinates out of range")
+	}
+
+	return s.repository.GetMerchantTile(ctx, z, x, y)
+}
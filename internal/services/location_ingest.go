@@ -0,0 +1,161 @@
+package services
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	locationQueueSize   = 50000
+	locationFlushBatch  = 2000
+	locationFlushWindow = 5 * time.Second
+
+	// partitions are kept open this many months ahead, checked every
+	// locationPartitionCheck
+	locationPartitionsAhead = 2
+	locationPartitionCheck  = time.Hour
+)
+
+// LocationIngestor buffers courier pings in memory and writes them with COPY,
+// either once locationFlushBatch have piled up or every interval. Pings still
+// buffered when the process dies are lost, which tracking can live with.
+type LocationIngestor struct {
+	repository repository.TrackingRepository
+	queue      chan entities.CourierLocation
+	interval   time.Duration
+	done       chan struct{}
+}
+
+func NewLocationIngestor(repository repository.TrackingRepository, interval time.Duration) LocationIngestor {
+	return LocationIngestor{
+		repository: repository,
+		queue:      make(chan entities.CourierLocation, locationQueueSize),
+		interval:   interval,
+		done:       make(chan struct{}),
+	}
+}
+
+// Done is closed once Run has written its last pings and returned.
+func (i LocationIngestor) Done() <-chan struct{} {
+	return i.done
+}
+
+// Enqueue hands pings to the writer without waiting for the database. When the
+// buffer is full the whole batch is refused so the client retries it later.
+func (i LocationIngestor) Enqueue(locations []entities.CourierLocation) error {
+	if cap(i.queue)-len(i.queue) < len(locations) {
+		 return utils.NewTooManyReq("location buffer is full, retry later")
+	}
+
+	for _, l := range locations {
+		select {
+		case i.queue <- l:
+		default:
+			return utils.NewTooManyReq("location buffer is full, retry later")
+		}
+	}
+
+	return nil
+}
+
+// Run writes buffered pings until ctx is cancelled, then flushes what is left.
+// It also keeps the monthly partitions open ahead of the pings. It is meant to
+// be started in its own goroutine.
+func (i LocationIngestor) Run(ctx context.Context) {
+	defer close(i.done)
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	partitionTicker := time.NewTicker(locationPartitionCheck)
+	defer partitionTicker.Stop()
+
+	partitions := make(map[string]bool)
+	batch := make([]entities.CourierLocation, 0, locationFlushBatch)
+
+	i.ensurePartitions(ctx, partitions)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for len(i.queue) > 0 {
+				batch = append(batch, <-i.queue)
+			}
+
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), locationFlushWindow)
+			i.flush(flushCtx, batch, partitions)
+			cancel()
+			return
+		case l := <-i.queue:
+			batch = append(batch, l)
+			if len(batch) >= locationFlushBatch {
+				i.flush(ctx, batch, partitions)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			i.flush(ctx, batch, partitions)
+			batch = batch[:0]
+		case <-partitionTicker.C:
+			i.ensurePartitions(ctx, partitions)
+		}
+	}
+}
+
+// ensurePartitions opens the partitions of every month a ping may be recorded
+// in, from the oldest accepted ping to locationPartitionsAhead months ahead.
+func (i LocationIngestor) ensurePartitions(ctx context.Context, partitions map[string]bool) {
+	now := time.Now().UTC()
+	from := now.Add(-maxPingAge)
+	from = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), now.Month()+locationPartitionsAhead, 1, 0, 0, 0, 0, time.UTC)
+
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		i.ensurePartition(ctx, month, partitions)
+	}
+}
+
+func (i LocationIngestor) ensurePartition(ctx context.Context, t time.Time, partitions map[string]bool) bool {
+	month := t.UTC().Format("2006_01")
+	if partitions[month] {
+		 return true
+	}
+
+	if err := i.repository.EnsureLocationPartition(ctx, t); err != nil {
+		log.Warn().Err(err).Str("month", month).Msg("failed to create courier location partition")
+		return false
+	}
+	partitions[month] = true
+
+	return true
+}
+
+// flush writes batch. There is no default partition, so pings for a month
+// whose partition cannot be created are dropped rather than failing the rest.
+func (i LocationIngestor) flush(ctx context.Context, batch []entities.CourierLocation, partitions map[string]bool) {
+	if len(batch) == 0 {
+		 return
+	}
+
+	writable := make([]entities.CourierLocation, 0, len(batch))
+	for _, l := range batch {
+		if i.ensurePartition(ctx, l.RecordedAt, partitions) {
+			 writable = append(writable, l)
+		}
+	}
+
+	if dropped := len(batch) - len(writable); dropped > 0 {
+		log.Error().Int("pings", dropped).Msg("dropped courier locations without a partition")
+	}
+	if len(writable) == 0 {
+		 return
+	}
+
+	if _, err := i.repository.CopyLocations(ctx, writable); err != nil {
+		log.Error().Err(err).Int("pings", len(writable)).Msg("failed to write courier locations")
+	}
+}
@@ -6,6 +6,11 @@ import (
 	"belimang/internal/repository"
 	"belimang/internal/utils"
 	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type MerchantService struct {
@@ -93,3 +98,121 @@ func (s MerchantService) CreateMercItem(ctx context.Context, req entities.MercIt
 		ID: merchantItem.ID,
 	}, nil
 }
+
+func (s MerchantService) SetOpeningHours(ctx context.Context, merchantId string, hours []entities.OpeningHour) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	_, err := s.repository.GetMerchantById(ctx, merchantId)
+	if err != nil {
+		 return utils.NewNotFound("merchant does not exist")
+	}
+
+	if err := checkOpeningHours(hours); err != nil {
+		 return err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.repository.ReplaceOpeningHours(ctx, tx, merchantId, hours); err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// checkOpeningHours rejects slots that overlap anywhere in the week. A slot
+// that closes at or before it opens runs past midnight into the next day, and
+// Saturday night runs into Sunday.
+func checkOpeningHours(hours []entities.OpeningHour) error {
+	const day, week = 24 * 60, 7 * 24 * 60
+
+	type slot struct{ start, end, dayOfWeek int }
+	slots := make([]slot, 0, len(hours))
+	for _, h := range hours {
+		opens, err := time.Parse("15:04", h.OpensAt)
+		if err != nil {
+			 return utils.NewBadRequest("invalid opening hours")
+		}
+		closes, err := time.Parse("15:04", h.ClosesAt)
+		if err != nil {
+			 return utils.NewBadRequest("invalid opening hours")
+		}
+
+		start := h.DayOfWeek*day + opens.Hour()*60 + opens.Minute()
+		end := h.DayOfWeek*day + closes.Hour()*60 + closes.Minute()
+		if end <= start {
+			 end += day
+		}
+		slots = append(slots, slot{start: start, end: end, dayOfWeek: h.DayOfWeek})
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].start < slots[j].start })
+	for i := range slots {
+		next := slots[(i+1)%len(slots)]
+		if i == len(slots)-1 {
+			// the week wraps around to the first slot
+			next.start += week
+		}
+		if len(slots) > 1 && slots[i].end > next.start {
+			 return utils.NewBadRequest(fmt.Sprintf("opening hours overlap on day %d", next.dayOfWeek))
+		}
+	}
+
+	return nil
+}
+
+// SetOwner hands the merchant's order queue to userId.
+func (s MerchantService) SetOwner(ctx context.Context, merchantId, userId string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if _, err := uuid.Parse(merchantId); err != nil {
+		 return utils.NewNotFound("merchant does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.repository.SetMerchantOwner(ctx, tx, merchantId, userId); err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetItemAvailability takes an item off the menu or puts it back without
+// deleting it, so past orders keep pointing at it.
+func (s MerchantService) SetItemAvailability(ctx context.Context, merchantId, itemId string, available bool) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if _, err := uuid.Parse(merchantId); err != nil {
+		 return utils.NewNotFound("merchant does not exist")
+	}
+	if _, err := uuid.Parse(itemId); err != nil {
+		 return utils.NewNotFound("item does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.repository.SetItemAvailability(ctx, tx, merchantId, itemId, available); err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
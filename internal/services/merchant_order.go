@@ -0,0 +1,222 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// MerchantOrderService is the kitchen side of an order. Each merchant works its
+// own sub-order; the parent order is accepted once every merchant has accepted
+// and cancelled as soon as any of them rejects.
+type MerchantOrderService struct {
+	repository repository.MerchantOrderRepository
+	merchants  repository.MerchantRepository
+	orders     OrderService
+}
+
+func NewMerchantOrderService(repository repository.MerchantOrderRepository, merchants repository.MerchantRepository, orders OrderService) MerchantOrderService {
+	return MerchantOrderService{
+		repository: repository,
+		merchants:  merchants,
+		orders:     orders,
+	}
+}
+
+func (s MerchantOrderService) GetQueue(ctx context.Context, userID string, filter entities.MerchantOrderFilter) (dto.MerchantOrderResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.MerchantOrderResponse{}, err
+	}
+
+	if _, err := uuid.Parse(filter.MerchantID); err != nil {
+		 return dto.MerchantOrderResponse{}, utils.NewNotFound("merchant does not exist")
+	}
+
+	if err := s.ownedMerchant(ctx, userID, filter.MerchantID); err != nil {
+		 return dto.MerchantOrderResponse{}, err
+	}
+
+	orders, total, err := s.repository.GetMerchantOrders(ctx, filter)
+	if err != nil {
+		 return dto.MerchantOrderResponse{}, err
+	}
+
+	data := make([]dto.MerchantOrder, 0, len(orders))
+	for _, mo := range orders {
+		data = append(data, toMerchantOrderDTO(mo))
+	}
+
+	return dto.MerchantOrderResponse{
+		Data: data,
+		Meta: dto.Meta{Total: total, Limit: filter.Limit, Offset: filter.Offset},
+	}, nil
+}
+
+// Accept takes the merchant's share of the order with an estimate of how long
+// the kitchen needs. The last merchant to accept moves the order to Preparing.
+func (s MerchantOrderService) Accept(ctx context.Context, userID, merchantID, orderID string, req dto.AcceptMerchantOrderRequest) (dto.MerchantOrder, error) {
+	return s.update(ctx, userID, merchantID, orderID, func(tx pgx.Tx, order entities.Order, mo entities.MerchantOrder) (entities.MerchantOrder, []entities.OrderStatusChange, error) {
+		if mo.Status != entities.MerchantOrderPending {
+			 return entities.MerchantOrder{}, nil, utils.NewConflict(fmt.Sprintf("%s orders cannot be accepted", mo.Status))
+		}
+
+		mo.Status = entities.MerchantOrderAccepted
+		mo.PrepMinutes = &req.PrepMinutes
+		mo, err := s.repository.UpdateMerchantOrder(ctx, tx, mo)
+		if err != nil {
+			 return entities.MerchantOrder{}, nil, err
+		}
+
+		pending, err := s.repository.CountMerchantOrders(ctx, tx, order.ID, entities.MerchantOrderPending)
+		if err != nil {
+			 return entities.MerchantOrder{}, nil, err
+		}
+		if pending > 0 || order.Status != entities.OrderPlaced {
+			 return mo, nil, nil
+		}
+
+		accepted, err := s.orders.applyTransition(ctx, tx, order, entities.OrderAccepted, userID, "accepted by merchants")
+		if err != nil {
+			 return entities.MerchantOrder{}, nil, err
+		}
+
+		order.Status = entities.OrderAccepted
+		preparing, err := s.orders.applyTransition(ctx, tx, order, entities.OrderPreparing, userID, "preparation started")
+		if err != nil {
+			 return entities.MerchantOrder{}, nil, err
+		}
+
+		return mo, []entities.OrderStatusChange{accepted, preparing}, nil
+	})
+}
+
+// Reject turns down the merchant's share. An order cannot be delivered in part,
+// so the whole order is cancelled and the customer refunded without a fee.
+func (s MerchantOrderService) Reject(ctx context.Context, userID, merchantID, orderID string, req dto.RejectMerchantOrderRequest) (dto.MerchantOrder, error) {
+	return s.update(ctx, userID, merchantID, orderID, func(tx pgx.Tx, order entities.Order, mo entities.MerchantOrder) (entities.MerchantOrder, []entities.OrderStatusChange, error) {
+		if mo.Status != entities.MerchantOrderPending {
+			 return entities.MerchantOrder{}, nil, utils.NewConflict(fmt.Sprintf("%s orders cannot be rejected", mo.Status))
+		}
+
+		mo.Status = entities.MerchantOrderRejected
+		mo.Reason = req.ReasonCode
+		mo, err := s.repository.UpdateMerchantOrder(ctx, tx, mo)
+		if err != nil {
+			 return entities.MerchantOrder{}, nil, err
+		}
+
+		_, change, err := s.orders.cancelLocked(ctx, tx, order, entities.OrderCancellation{
+			ReasonCode:  req.ReasonCode,
+			Note:        req.Note,
+			InitiatedBy: entities.CancelledByMerchant,
+			ActorID:     &userID,
+		})
+		if err != nil {
+			 return entities.MerchantOrder{}, nil, err
+		}
+
+		return mo, []entities.OrderStatusChange{change}, nil
+	})
+}
+
+// Ready marks the merchant's share as waiting for the courier.
+func (s MerchantOrderService) Ready(ctx context.Context, userID, merchantID, orderID string) (dto.MerchantOrder, error) {
+	return s.update(ctx, userID, merchantID, orderID, func(tx pgx.Tx, order entities.Order, mo entities.MerchantOrder) (entities.MerchantOrder, []entities.OrderStatusChange, error) {
+		if mo.Status != entities.MerchantOrderAccepted {
+			 return entities.MerchantOrder{}, nil, utils.NewConflict(fmt.Sprintf("%s orders cannot be marked ready", mo.Status))
+		}
+
+		mo.Status = entities.MerchantOrderReady
+		mo, err := s.repository.UpdateMerchantOrder(ctx, tx, mo)
+		return mo, nil, err
+	})
+}
+
+// update locks the parent order and then the merchant's sub-order, the same
+// order cancellations lock them in, and lets apply change them in one
+// transaction. Status changes of the parent are published after the commit.
+func (s MerchantOrderService) update(ctx context.Context, userID, merchantID, orderID string, apply func(pgx.Tx, entities.Order, entities.MerchantOrder) (entities.MerchantOrder, []entities.OrderStatusChange, error)) (dto.MerchantOrder, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+
+	if _, err := uuid.Parse(merchantID); err != nil {
+		 return dto.MerchantOrder{}, utils.NewNotFound("merchant does not exist")
+	}
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return dto.MerchantOrder{}, utils.NewNotFound("order does not exist")
+	}
+
+	if err := s.ownedMerchant(ctx, userID, merchantID); err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := s.orders.repository.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+
+	mo, err := s.repository.GetMerchantOrderForUpdate(ctx, tx, orderID, merchantID)
+	if err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+
+	if order.Status == entities.OrderScheduled {
+		 return dto.MerchantOrder{}, utils.NewConflict("order has not been released yet")
+	}
+
+	mo, changes, err := apply(tx, order, mo)
+	if err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.MerchantOrder{}, err
+	}
+
+	for _, c := range changes {
+		s.orders.publishStatus(context.WithoutCancel(ctx), c)
+	}
+
+	mo.ScheduledFor = order.ScheduledFor
+	return toMerchantOrderDTO(mo), nil
+}
+
+func toMerchantOrderDTO(mo entities.MerchantOrder) dto.MerchantOrder {
+	items := make([]dto.MerchantOrderItem, 0, len(mo.Items))
+	for _, it := range mo.Items {
+		items = append(items, dto.MerchantOrderItem{
+			ItemID:   it.ItemID,
+			Name:     it.Name,
+			Quantity: it.Quantity,
+			Price:    it.Price,
+		})
+	}
+
+	return dto.MerchantOrder{
+		MerchantOrderID: mo.ID,
+		OrderID:         mo.OrderID,
+		MerchantID:      mo.MerchantID,
+		Status:          mo.Status,
+		PrepMinutes:     mo.PrepMinutes,
+		AcceptedAt:      mo.AcceptedAt,
+		ReadyBy:         mo.ReadyBy,
+		ReadyAt:         mo.ReadyAt,
+		ScheduledFor:    mo.ScheduledFor,
+		Reason:          mo.Reason,
+		CreatedAt:       mo.CreatedAt,
+		Items:           items,
+	}
+}
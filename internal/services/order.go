@@ -0,0 +1,439 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/events"
+	"belimang/internal/payments"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Delivered and Cancelled are terminal, as is Rejected, which only older orders
+// are in: a rejected order is cancelled so it is refunded and its promotion
+// released.
+var orderTransitions = map[string][]string{
+	entities.OrderScheduled: {entities.OrderPlaced, entities.OrderCancelled},
+	entities.OrderPlaced:    {entities.OrderAccepted, entities.OrderCancelled},
+	entities.OrderAccepted:  {entities.OrderPreparing, entities.OrderCancelled},
+	entities.OrderPreparing: {entities.OrderPickedUp, entities.OrderCancelled},
+	entities.OrderPickedUp:  {entities.OrderDelivered, entities.OrderCancelled},
+}
+
+func canTransition(from, to string) bool {
+	return slices.Contains(orderTransitions[from], to)
+}
+
+// CancellationPolicy decides what a customer pays for cancelling. Orders still
+// waiting for the merchant, or placed less than FreeWindow ago, cancel for free;
+// afterwards FeePercent of the total is kept, depending on the order status.
+type CancellationPolicy struct {
+	FreeWindow time.Duration
+	FeePercent map[string]int
+}
+
+// userCancellable lists the statuses a customer may cancel from. Once the
+// courier has picked the order up only the merchant or an admin can cancel it,
+// e.g. when the delivery fails on the way.
+var userCancellable = []string{entities.OrderScheduled, entities.OrderPlaced, entities.OrderAccepted, entities.OrderPreparing}
+
+func (p CancellationPolicy) fee(order entities.Order) int {
+	if order.Status == entities.OrderScheduled || order.Status == entities.OrderPlaced || order.Age <= p.FreeWindow {
+		 return 0
+	}
+
+	return order.TotalPrice * p.FeePercent[order.Status] / 100
+}
+
+type OrderService struct {
+	repository     repository.OrderRepository
+	merchantOrders repository.MerchantOrderRepository
+	payments       repository.PaymentRepository
+	events         events.Bus
+	promotions     PromotionService
+	policy         CancellationPolicy
+}
+
+func NewOrderService(repository repository.OrderRepository, merchantOrderRepository repository.MerchantOrderRepository, paymentRepository repository.PaymentRepository, bus events.Bus, promotions PromotionService, policy CancellationPolicy) OrderService {
+	return OrderService{
+		repository:     repository,
+		merchantOrders: merchantOrderRepository,
+		payments:       paymentRepository,
+		events:         bus,
+		promotions:     promotions,
+		policy:         policy,
+	}
+}
+
+// RecordCreated writes the first history entry of a new order, Placed or
+// Scheduled, and opens a sub-order for each of its merchants inside the
+// transaction that created it.
+func (s OrderService) RecordCreated(ctx context.Context, tx pgx.Tx, order entities.Order) error {
+	_, err := s.repository.InsertStatusHistory(ctx, tx, entities.OrderStatusChange{
+		OrderID:   order.ID,
+		ToStatus:  order.Status,
+		ChangedBy: &order.UserID,
+	})
+	if err != nil {
+		 return err
+	}
+
+	return s.merchantOrders.CreateForOrder(ctx, tx, order.ID, order.EstimateID)
+}
+
+func (s OrderService) UpdateStatus(ctx context.Context, orderID, actorID string, req dto.UpdateOrderStatusRequest) (dto.OrderStatusResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return dto.OrderStatusResponse{}, utils.NewNotFound("order does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	change, err := s.transition(ctx, tx, orderID, req.Status, actorID, req.Reason)
+	if err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+
+	s.publishStatus(context.WithoutCancel(ctx), change)
+
+	return s.statusResponse(ctx, orderID)
+}
+
+// transition locks the order, checks the move against orderTransitions and
+// records it. Callers own tx so side effects can commit atomically with it.
+func (s OrderService) transition(ctx context.Context, tx pgx.Tx, orderID, to, actorID, reason string) (entities.OrderStatusChange, error) {
+	order, err := s.repository.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	return s.applyTransition(ctx, tx, order, to, actorID, reason)
+}
+
+// applyTransition is transition for an order already locked by the caller.
+func (s OrderService) applyTransition(ctx context.Context, tx pgx.Tx, order entities.Order, to, actorID, reason string) (entities.OrderStatusChange, error) {
+	if !canTransition(order.Status, to) {
+		 return entities.OrderStatusChange{}, utils.NewConflict(fmt.Sprintf("order cannot move from %s to %s", order.Status, to))
+	}
+
+	if err := s.settlePayment(ctx, tx, order, to); err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	from := order.Status
+	change := entities.OrderStatusChange{
+		OrderID:    order.ID,
+		FromStatus: &from,
+		ToStatus:   to,
+		Reason:     reason,
+	}
+	if actorID != "" {
+		change.ChangedBy = &actorID
+	}
+
+	change, err := s.repository.UpdateOrderStatus(ctx, tx, change)
+	if err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	if err := s.syncMerchantOrders(ctx, tx, order.ID, to); err != nil {
+		 return entities.OrderStatusChange{}, err
+	}
+
+	return change, nil
+}
+
+// syncMerchantOrders closes the merchants' sub-orders when the order as a whole
+// ends or leaves their kitchens.
+func (s OrderService) syncMerchantOrders(ctx context.Context, tx pgx.Tx, orderID, to string) error {
+	switch to {
+	case entities.OrderCancelled:
+		return s.merchantOrders.MoveMerchantOrders(ctx, tx, orderID, entities.MerchantOrderCancelled, []string{
+			entities.MerchantOrderPending,
+			entities.MerchantOrderAccepted,
+			entities.MerchantOrderReady,
+		})
+	case entities.OrderPickedUp:
+		return s.merchantOrders.MoveMerchantOrders(ctx, tx, orderID, entities.MerchantOrderPickedUp, []string{
+			entities.MerchantOrderAccepted,
+			entities.MerchantOrderReady,
+		})
+	}
+
+	return nil
+}
+
+// publishStatus notifies order followers after the change has been committed.
+// A failed publish is only logged; the history table stays the source of truth.
+func (s OrderService) publishStatus(ctx context.Context, c entities.OrderStatusChange) {
+	e, err := events.NewEvent(events.TypeStatus, c.OrderID, toStatusChangeDTO(c))
+	if err == nil {
+		 err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		 log.Error().Err(err).Str("orderId", c.OrderID).Msg("failed to publish order status")
+	}
+}
+
+// Watch subscribes userID to the events of one of their orders. The snapshot
+// is read after subscribing so no change can fall between the two.
+func (s OrderService) Watch(ctx context.Context, userID, orderID string) (dto.OrderStatusResponse, <-chan events.Event, func(), error) {
+	if err := s.ownedOrder(ctx, userID, orderID); err != nil {
+		 return dto.OrderStatusResponse{}, nil, nil, err
+	}
+
+	ch, unsubscribe := s.events.Subscribe(orderID)
+
+	snapshot, err := s.statusResponse(ctx, orderID)
+	if err != nil {
+		unsubscribe()
+		return dto.OrderStatusResponse{}, nil, nil, err
+	}
+
+	return snapshot, ch, unsubscribe, nil
+}
+
+func (s OrderService) GetStatus(ctx context.Context, userID, orderID string) (dto.OrderStatusResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+
+	if err := s.ownedOrder(ctx, userID, orderID); err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+
+	return s.statusResponse(ctx, orderID)
+}
+
+func (s OrderService) statusResponse(ctx context.Context, orderID string) (dto.OrderStatusResponse, error) {
+	history, err := s.repository.GetOrderStatusHistory(ctx, orderID)
+	if err != nil {
+		 return dto.OrderStatusResponse{}, err
+	}
+
+	resp := dto.OrderStatusResponse{
+		OrderID: orderID,
+		History: make([]dto.OrderStatusChange, 0, len(history)),
+	}
+
+	for _, c := range history {
+		resp.History = append(resp.History, toStatusChangeDTO(c))
+		resp.Status = c.ToStatus
+	}
+
+	return resp, nil
+}
+
+func toStatusChangeDTO(c entities.OrderStatusChange) dto.OrderStatusChange {
+	change := dto.OrderStatusChange{
+		ToStatus:  c.ToStatus,
+		Reason:    c.Reason,
+		CreatedAt: c.CreatedAt,
+	}
+	if c.FromStatus != nil {
+		change.FromStatus = *c.FromStatus
+	}
+	return change
+}
+
+// CancelByUser cancels one of the caller's own orders, charging the fee the
+// cancellation policy sets for the order's status and age.
+func (s OrderService) CancelByUser(ctx context.Context, userID, orderID string, req dto.CancelOrderRequest) (dto.CancelOrderResponse, error) {
+	return s.cancel(ctx, orderID, func(order entities.Order) (entities.OrderCancellation, error) {
+		if err := authorizeOwner(order.UserID, userID, "order"); err != nil {
+			 return entities.OrderCancellation{}, err
+		}
+
+		if !slices.Contains(userCancellable, order.Status) {
+			 return entities.OrderCancellation{}, utils.NewConflict(fmt.Sprintf("%s orders can no longer be cancelled", order.Status))
+		}
+
+		return entities.OrderCancellation{
+			ReasonCode:  entities.ReasonCustomerRequest,
+			Note:        req.Note,
+			InitiatedBy: entities.CancelledByUser,
+			ActorID:     &userID,
+			Fee:         s.policy.fee(order),
+		}, nil
+	})
+}
+
+// CancelByOperator cancels an order on behalf of the merchant or an admin. The
+// customer is never charged a fee for these.
+func (s OrderService) CancelByOperator(ctx context.Context, actorID, orderID string, req dto.OperatorCancelOrderRequest) (dto.CancelOrderResponse, error) {
+	return s.cancel(ctx, orderID, func(order entities.Order) (entities.OrderCancellation, error) {
+		return entities.OrderCancellation{
+			ReasonCode:  req.ReasonCode,
+			Note:        req.Note,
+			InitiatedBy: req.InitiatedBy,
+			ActorID:     &actorID,
+		}, nil
+	})
+}
+
+// cancel locks the order, lets decide validate the request and describe the
+// cancellation, then applies it in one transaction.
+func (s OrderService) cancel(ctx context.Context, orderID string, decide func(entities.Order) (entities.OrderCancellation, error)) (dto.CancelOrderResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CancelOrderResponse{}, err
+	}
+
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return dto.CancelOrderResponse{}, utils.NewNotFound("order does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.CancelOrderResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := s.repository.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		 return dto.CancelOrderResponse{}, err
+	}
+
+	cancellation, err := decide(order)
+	if err != nil {
+		 return dto.CancelOrderResponse{}, err
+	}
+
+	resp, change, err := s.cancelLocked(ctx, tx, order, cancellation)
+	if err != nil {
+		 return dto.CancelOrderResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.CancelOrderResponse{}, err
+	}
+
+	s.publishStatus(context.WithoutCancel(ctx), change)
+
+	return resp, nil
+}
+
+// cancelLocked moves an order locked by the caller to Cancelled, records why,
+// gives back its promotion use and settles the payment: money already taken is
+// refunded minus the fee, a payment still in progress is called off.
+// Items carry no stock level, so there is no inventory to put back.
+func (s OrderService) cancelLocked(ctx context.Context, tx pgx.Tx, order entities.Order, cancellation entities.OrderCancellation) (dto.CancelOrderResponse, entities.OrderStatusChange, error) {
+	actorID := ""
+	if cancellation.ActorID != nil {
+		 actorID = *cancellation.ActorID
+	}
+
+	change, err := s.applyTransition(ctx, tx, order, entities.OrderCancelled, actorID, cancellation.ReasonCode)
+	if err != nil {
+		 return dto.CancelOrderResponse{}, entities.OrderStatusChange{}, err
+	}
+
+	cancellation.OrderID = order.ID
+	cancellation.PreviousStatus = order.Status
+	cancellation.Fee = min(cancellation.Fee, order.TotalPrice)
+	if err := s.repository.InsertCancellation(ctx, tx, cancellation); err != nil {
+		 return dto.CancelOrderResponse{}, entities.OrderStatusChange{}, err
+	}
+
+	if err := s.promotions.Release(ctx, tx, order.ID); err != nil {
+		 return dto.CancelOrderResponse{}, entities.OrderStatusChange{}, err
+	}
+
+	resp := dto.CancelOrderResponse{
+		OrderID: order.ID,
+		Status:  entities.OrderCancelled,
+		Fee:     cancellation.Fee,
+	}
+
+	refund := entities.Refund{OrderID: order.ID, Amount: order.TotalPrice - cancellation.Fee}
+
+	payment, err := s.payments.GetPaymentByOrderForUpdate(ctx, tx, order.ID)
+	switch {
+	case isNotFound(err):
+		// orders placed before payments existed were never charged
+		refund.Amount = 0
+	case err != nil:
+		return dto.CancelOrderResponse{}, entities.OrderStatusChange{}, err
+	case payment.Status == payments.StatusSucceeded:
+		refund.PaymentID = &payment.ID
+		refund.Amount = min(refund.Amount, payment.Amount)
+	case payment.Status == payments.StatusPending || payment.Status == payments.StatusRequiresAction:
+		payment.Status = payments.StatusCancelled
+		if err := s.payments.UpdatePayment(ctx, tx, payment); err != nil {
+			 return dto.CancelOrderResponse{}, entities.OrderStatusChange{}, err
+		}
+		refund.Amount = 0
+	default:
+		refund.Amount = 0
+	}
+
+	if refund.Amount > 0 {
+		refund, err := s.repository.InsertRefund(ctx, tx, refund)
+		if err != nil {
+			 return dto.CancelOrderResponse{}, entities.OrderStatusChange{}, err
+		}
+
+		resp.Refund = &dto.Refund{
+			RefundID: refund.ID,
+			Amount:   refund.Amount,
+			Status:   refund.Status,
+		}
+	}
+
+	return resp, change, nil
+}
+
+// settlePayment keeps the payment in step with the order: merchants only accept
+// orders that are paid for, and cash on delivery is settled on delivery. Orders
+// placed before payments existed have no payment and are left alone.
+func (s OrderService) settlePayment(ctx context.Context, tx pgx.Tx, order entities.Order, to string) error {
+	if to != entities.OrderAccepted && to != entities.OrderDelivered {
+		 return nil
+	}
+
+	payment, err := s.payments.GetPaymentByOrderForUpdate(ctx, tx, order.ID)
+	if isNotFound(err) {
+		 return nil
+	}
+	if err != nil {
+		 return err
+	}
+
+	cod := payment.Provider == payments.MethodCOD
+
+	if to == entities.OrderAccepted && !cod && payment.Status != payments.StatusSucceeded {
+		 return utils.NewConflict("order has not been paid yet")
+	}
+
+	if to == entities.OrderDelivered && cod && payment.Status == payments.StatusPending {
+		payment.Status = payments.StatusSucceeded
+		return s.payments.UpdatePayment(ctx, tx, payment)
+	}
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	appErr, ok := err.(utils.AppError)
+	return ok && appErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const scheduledReleaseReason = "scheduled release"
+
+// OrderScheduler releases Scheduled orders to their merchants once their
+// release time, the delivery slot minus route and preparation time, is reached.
+type OrderScheduler struct {
+	orders   OrderService
+	interval time.Duration
+}
+
+func NewOrderScheduler(orders OrderService, interval time.Duration) OrderScheduler {
+	return OrderScheduler{
+		orders:   orders,
+		interval: interval,
+	}
+}
+
+// Run releases due orders until ctx is cancelled. It is meant to be started in
+// its own goroutine.
+func (s OrderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.releaseDue(ctx)
+		}
+	}
+}
+
+func (s OrderScheduler) releaseDue(ctx context.Context) {
+	released := 0
+	for ctx.Err() == nil {
+		ok, err := s.orders.releaseNext(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to release scheduled order")
+			break
+		}
+		if !ok {
+			break
+		}
+		released++
+	}
+
+	if released > 0 {
+		log.Info().Int("orders", released).Msg("released scheduled orders")
+	}
+}
+
+// releaseNext moves one due Scheduled order to Placed. Each order gets its own
+// transaction so one bad order does not hold back the rest.
+func (s OrderService) releaseNext(ctx context.Context) (bool, error) {
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	order, ok, err := s.repository.ClaimDueScheduledOrder(ctx, tx)
+	if err != nil || !ok {
+		 return false, err
+	}
+
+	change, err := s.applyTransition(ctx, tx, order, entities.OrderPlaced, "", scheduledReleaseReason)
+	if err != nil {
+		 return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return false, err
+	}
+
+	s.publishStatus(context.WithoutCancel(ctx), change)
+	return true, nil
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/payments"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type PaymentService struct {
+	repository repository.PaymentRepository
+	providers  payments.Registry
+	orders     OrderService
+	wallet     WalletService
+}
+
+func NewPaymentService(repository repository.PaymentRepository, providers payments.Registry, orders OrderService, wallet WalletService) PaymentService {
+	return PaymentService{
+		repository: repository,
+		providers:  providers,
+		orders:     orders,
+		wallet:     wallet,
+	}
+}
+
+// Prepare records the payment for a new order inside the order transaction.
+// Wallet payments are debited right here, so an order the balance cannot cover
+// is never created. Other providers are only contacted by Start, once the
+// order exists.
+func (s PaymentService) Prepare(ctx context.Context, tx pgx.Tx, order entities.Order, method string) (entities.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Payment{}, err
+	}
+
+	if _, ok := s.providers.Get(method); !ok {
+		 return entities.Payment{}, utils.NewBadRequest("payment method is not available")
+	}
+
+	payment := entities.Payment{
+		OrderID:  order.ID,
+		UserID:   order.UserID,
+		Provider: method,
+		Amount:   order.TotalPrice,
+		Status:   payments.StatusPending,
+	}
+
+	if method == payments.MethodWallet && order.TotalPrice > 0 {
+		ref, err := s.wallet.DebitTx(ctx, tx, order.UserID, order.TotalPrice, "order:"+order.ID)
+		if err != nil {
+			 return entities.Payment{}, err
+		}
+
+		payment.ProviderRef = &ref
+		payment.Status = payments.StatusSucceeded
+	}
+
+	return s.repository.CreatePayment(ctx, tx, payment)
+}
+
+// Start creates the payment intent with the provider. When the provider turns
+// the payment down the order is cancelled and the failure is reported in the
+// returned payment rather than as an error, since the order already exists.
+func (s PaymentService) Start(ctx context.Context, payment entities.Payment) (dto.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Payment{}, err
+	}
+
+	if payment.Status == payments.StatusSucceeded {
+		return dto.Payment{
+			PaymentID: payment.ID,
+			Method:    payment.Provider,
+			Amount:    payment.Amount,
+			Status:    payment.Status,
+		}, nil
+	}
+
+	intent := payments.Intent{Status: payments.StatusSucceeded}
+	if payment.Amount > 0 {
+		provider, _ := s.providers.Get(payment.Provider)
+
+		var err error
+		intent, err = provider.CreateIntent(ctx, payments.IntentRequest{
+			PaymentID: payment.ID,
+			OrderID:   payment.OrderID,
+			UserID:    payment.UserID,
+			Amount:    payment.Amount,
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("paymentId", payment.ID).Msg("payment intent failed")
+			intent = payments.Intent{Status: payments.StatusFailed}
+			payment.FailureReason = "payment was declined"
+			if appErr, ok := err.(utils.AppError); ok {
+				payment.FailureReason = appErr.Message
+			}
+		}
+	}
+
+	if intent.Reference != "" {
+		payment.ProviderRef = &intent.Reference
+	}
+
+	if err := s.apply(ctx, payment, intent.Status, payment.FailureReason); err != nil {
+		 return dto.Payment{}, err
+	}
+
+	return dto.Payment{
+		PaymentID:     payment.ID,
+		Method:        payment.Provider,
+		Amount:        payment.Amount,
+		Status:        intent.Status,
+		ClientSecret:  intent.ClientSecret,
+		FailureReason: payment.FailureReason,
+	}, nil
+}
+
+// HandleWebhook verifies and applies a provider notification. Deliveries are
+// recorded by id in the same transaction, so retries are acknowledged without
+// being applied twice.
+func (s PaymentService) HandleWebhook(ctx context.Context, providerName string, header http.Header, body []byte) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	provider, ok := s.providers.Get(providerName)
+	if !ok {
+		 return utils.NewNotFound("payment provider not found")
+	}
+
+	event, err := provider.ParseWebhook(header, body)
+	if errors.Is(err, payments.ErrNoWebhooks) {
+		 return utils.NewNotFound("payment provider does not accept webhooks")
+	}
+	if errors.Is(err, payments.ErrInvalidSignature) {
+		 return utils.NewUnauthorized("invalid webhook signature")
+	}
+	if err != nil {
+		 return utils.NewBadRequest(err.Error())
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	fresh, err := s.repository.InsertWebhookEvent(ctx, tx, entities.WebhookEvent{
+		Provider: providerName,
+		EventID:  event.ID,
+		Payload:  body,
+	})
+	if err != nil {
+		 return err
+	}
+	if !fresh {
+		 return nil
+	}
+
+	payment, err := s.repository.GetPaymentByRef(ctx, tx, providerName, event.Reference)
+	if err != nil {
+		 return err
+	}
+
+	change, err := s.reconcile(ctx, tx, payment.OrderID, event.Status, event.Reason)
+	if err != nil {
+		 return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return err
+	}
+
+	if change != nil {
+		 s.orders.publishStatus(context.WithoutCancel(ctx), *change)
+	}
+
+	return nil
+}
+
+// apply stores the outcome of Start and cancels the order when it failed.
+func (s PaymentService) apply(ctx context.Context, payment entities.Payment, status, reason string) error {
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if payment.ProviderRef != nil {
+		if err := s.repository.UpdatePayment(ctx, tx, payment); err != nil {
+			 return err
+		}
+	}
+
+	change, err := s.reconcile(ctx, tx, payment.OrderID, status, reason)
+	if err != nil {
+		 return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return err
+	}
+
+	if change != nil {
+		 s.orders.publishStatus(context.WithoutCancel(ctx), *change)
+	}
+
+	return nil
+}
+
+// reconcile moves the payment of orderID to status and brings the order in
+// line. The order is locked before the payment, the same order cancellation
+// takes them in. A failed payment cancels an order still waiting for the
+// merchant; money taken for an order that was cancelled meanwhile is refunded.
+func (s PaymentService) reconcile(ctx context.Context, tx pgx.Tx, orderID, status, reason string) (*entities.OrderStatusChange, error) {
+	order, err := s.orders.repository.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		 return nil, err
+	}
+
+	payment, err := s.repository.GetPaymentByOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		 return nil, err
+	}
+
+	open := []string{payments.StatusPending, payments.StatusRequiresAction}
+	lateCapture := payment.Status == payments.StatusCancelled && status == payments.StatusSucceeded
+	if !slices.Contains(open, payment.Status) && !lateCapture {
+		 return nil, nil
+	}
+
+	payment.Status = status
+	payment.FailureReason = reason
+	if err := s.repository.UpdatePayment(ctx, tx, payment); err != nil {
+		 return nil, err
+	}
+
+	switch {
+	case status == payments.StatusSucceeded && order.Status == entities.OrderCancelled:
+		_, err := s.orders.repository.InsertRefund(ctx, tx, entities.Refund{
+			OrderID:   order.ID,
+			PaymentID: &payment.ID,
+			Amount:    payment.Amount,
+		})
+		return nil, err
+	case status == payments.StatusFailed && canTransition(order.Status, entities.OrderCancelled):
+		_, change, err := s.orders.cancelLocked(ctx, tx, order, entities.OrderCancellation{
+			ReasonCode:  entities.ReasonPaymentFailed,
+			Note:        reason,
+			InitiatedBy: entities.CancelledBySystem,
+		})
+		if err != nil {
+			 return nil, err
+		}
+		return &change, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PromotionService struct {
+	repository repository.PromotionRepository
+}
+
+func NewPromotionService(repository repository.PromotionRepository) PromotionService {
+	return PromotionService{repository: repository}
+}
+
+func (s PromotionService) CreatePromotion(ctx context.Context, req entities.Promotion) (dto.CreatePromotionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CreatePromotionResponse{}, err
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		 return dto.CreatePromotionResponse{}, utils.NewBadRequest("endsAt must be after startsAt")
+	}
+
+	if req.Type == "Percentage" && (req.Value < 1 || req.Value > 100) {
+		 return dto.CreatePromotionResponse{}, utils.NewBadRequest("percentage value must be between 1 and 100")
+	}
+
+	if req.Type == "Fixed" && req.Value < 1 {
+		 return dto.CreatePromotionResponse{}, utils.NewBadRequest("fixed value must be greater than 0")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.CreatePromotionResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	promo, err := s.repository.CreatePromotion(ctx, tx, req)
+	if err != nil {
+		 return dto.CreatePromotionResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.CreatePromotionResponse{}, err
+	}
+
+	return dto.CreatePromotionResponse{ID: promo.ID}, nil
+}
+
+func (s PromotionService) GetAllPromotion(ctx context.Context, filter entities.PromotionFilter) (dto.PromotionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.PromotionResponse{}, err
+	}
+
+	promos, total, err := s.repository.GetAllPromotion(ctx, filter)
+	if err != nil {
+		 return dto.PromotionResponse{}, err
+	}
+
+	data := make([]dto.Promotion, 0, len(promos))
+	for _, p := range promos {
+		data = append(data, dto.Promotion{
+			ID:                 p.ID,
+			Code:               p.Code,
+			Description:        p.Description,
+			Type:               p.Type,
+			Value:              p.Value,
+			MaxDiscount:        p.MaxDiscount,
+			MinBasket:          p.MinBasket,
+			GlobalLimit:        p.GlobalLimit,
+			PerUserLimit:       p.PerUserLimit,
+			UsedCount:          p.UsedCount,
+			MerchantIDs:        p.MerchantIDs,
+			MerchantCategories: p.MerchantCategories,
+			ProductCategories:  p.ProductCategories,
+			StartsAt:           p.StartsAt,
+			EndsAt:             p.EndsAt,
+			IsActive:           p.IsActive,
+			CreatedAt:          p.CreatedAt,
+		})
+	}
+
+	return dto.PromotionResponse{
+		Data: data,
+		Meta: dto.Meta{Total: total, Limit: filter.Limit, Offset: filter.Offset},
+	}, nil
+}
+
+func (s PromotionService) SetPromotionActive(ctx context.Context, id string, active bool) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		 return utils.NewNotFound("promotion not found")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.repository.SetPromotionActive(ctx, tx, id, active); err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// inScope reports whether a purchased line is covered by the promotion. Every
+// non-empty scope list must match; empty lists match everything.
+func inScope(p entities.Promotion, line entities.PromotionLine) bool {
+	if len(p.MerchantIDs) > 0 && !slices.Contains(p.MerchantIDs, line.MerchantID) {
+		 return false
+	}
+
+	if len(p.MerchantCategories) > 0 && !slices.Contains(p.MerchantCategories, line.MerchantCategory) {
+		 return false
+	}
+
+	if len(p.ProductCategories) > 0 && !slices.Contains(p.ProductCategories, line.ProductCategory) {
+		 return false
+	}
+
+	return true
+}
+
+// Evaluate checks that code can be used by the user for the given basket and
+// computes its discounts. Usage is only checked here; it is consumed by Redeem
+// when the estimate is turned into an order.
+func (s PromotionService) Evaluate(ctx context.Context, code, userID string, lines []entities.PromotionLine, b entities.PriceBreakdown) (entities.AppliedPromotion, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.AppliedPromotion{}, err
+	}
+
+	p, err := s.repository.GetPromotionByCode(ctx, code)
+	if err != nil {
+		 return entities.AppliedPromotion{}, err
+	}
+
+	switch {
+	case !p.IsActive:
+		return entities.AppliedPromotion{}, utils.NewBadRequest("promo code is not active")
+	case !p.Started:
+		return entities.AppliedPromotion{}, utils.NewBadRequest("promo code is not valid yet")
+	case p.Ended:
+		return entities.AppliedPromotion{}, utils.NewBadRequest("promo code has expired")
+	case p.GlobalLimit > 0 && p.UsedCount >= p.GlobalLimit:
+		return entities.AppliedPromotion{}, utils.NewBadRequest("promo code has been fully redeemed")
+	}
+
+	if p.PerUserLimit > 0 {
+		used, err := s.repository.CountUserRedemptions(ctx, p.ID, userID)
+		if err != nil {
+			 return entities.AppliedPromotion{}, err
+		}
+
+		if used >= p.PerUserLimit {
+			 return entities.AppliedPromotion{}, utils.NewBadRequest("promo code usage limit reached")
+		}
+	}
+
+	eligible := 0
+	for _, line := range lines {
+		if inScope(p, line) {
+			eligible += line.Amount
+		}
+	}
+
+	if eligible == 0 {
+		 return entities.AppliedPromotion{}, utils.NewBadRequest("promo code does not apply to these items")
+	}
+
+	if eligible < p.MinBasket {
+		 return entities.AppliedPromotion{}, utils.NewBadRequest("basket is below the promo code minimum")
+	}
+
+	applied := entities.AppliedPromotion{PromotionID: p.ID, Code: p.Code}
+	switch p.Type {
+	case "Percentage":
+		applied.Discount = eligible * p.Value / 100
+	case "Fixed":
+		applied.Discount = min(p.Value, eligible)
+	case "FreeDelivery":
+		applied.DeliveryDiscount = b.DeliveryFee + b.StopSurcharge
+	}
+
+	if p.MaxDiscount > 0 {
+		applied.Discount = min(applied.Discount, p.MaxDiscount)
+		applied.DeliveryDiscount = min(applied.DeliveryDiscount, p.MaxDiscount)
+	}
+
+	return applied, nil
+}
+
+// Redeem consumes one use of the promotion attached to an estimate inside the
+// order transaction, so a failed order never uses up the code.
+func (s PromotionService) Redeem(ctx context.Context, tx pgx.Tx, red entities.Redemption) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	return s.repository.RedeemPromotion(ctx, tx, red)
+}
+
+// Release restores the promotion use of a cancelled order inside the
+// cancellation transaction.
+func (s PromotionService) Release(ctx context.Context, tx pgx.Tx, orderID string) error {
+	if err := ctx.Err(); err != nil {
+		 return err
+	}
+
+	return s.repository.ReleaseRedemption(ctx, tx, orderID)
+}
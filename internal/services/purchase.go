@@ -3,30 +3,72 @@ package services
 import (
 	"belimang/internal/dto"
 	"belimang/internal/entities"
+	"belimang/internal/eta"
+	"belimang/internal/optimizer"
+	"belimang/internal/payments"
+	"belimang/internal/pricing"
 	"belimang/internal/repository"
+	"belimang/internal/routing"
 	"belimang/internal/utils"
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 )
 
 type PurchaseService struct {
-	repository repository.PurchaseRepository
+	repository  repository.PurchaseRepository
+	routing     routing.Provider
+	eta         ETAService
+	pricing     pricing.Engine
+	promotions  PromotionService
+	orders      OrderService
+	payments    PaymentService
+	addresses   AddressService
+	estimateTTL time.Duration
+	schedule    SchedulingWindow
 }
 
-func NewPurchaseService(repository repository.PurchaseRepository) PurchaseService {
-	return PurchaseService{repository: repository}
+// SchedulingWindow bounds how far ahead an order may be scheduled: at least
+// MinLead from now and at most MaxAhead.
+type SchedulingWindow struct {
+	MinLead  time.Duration
+	MaxAhead time.Duration
+}
+
+func NewPurchaseService(repository repository.PurchaseRepository, provider routing.Provider, etaService ETAService, engine pricing.Engine, promotions PromotionService, orders OrderService, payments PaymentService, addresses AddressService, estimateTTL time.Duration, schedule SchedulingWindow) PurchaseService {
+	return PurchaseService{
+		repository:  repository,
+		routing:     provider,
+		eta:         etaService,
+		pricing:     engine,
+		promotions:  promotions,
+		orders:      orders,
+		payments:    payments,
+		addresses:   addresses,
+		estimateTTL: estimateTTL,
+		schedule:    schedule,
+	}
 }
 
 const (
-	speedInKmh  = 40.0
-	minPerHour  = 60.0
-	kmToMinute  = 1 / speedInKmh * minPerHour
-	maxRadiusKm = 3.0
+	maxRadiusKm       = 3.0
+	routeSearchBudget = 50 * time.Millisecond
+
+	// quoteAlgorithm is stored with every estimate. Bump it whenever the same
+	// request would be routed, priced or timed differently.
+	quoteAlgorithm = "quote/v1"
 )
 
 func (s PurchaseService) GetNearbyMerchants(ctx context.Context, f entities.MerchantNearbyFilter) (map[string]any, error) {
+	if f.OpenNow {
+		 f.OpenAt = time.Now()
+	}
+
 	merchants, total, err := s.repository.GetNearbyMerchants(ctx, f)
 	if err != nil {
 		 return nil, err
@@ -42,6 +84,8 @@ func (s PurchaseService) GetNearbyMerchants(ctx context.Context, f entities.Merc
 				"productCategory": it.Category,
 				"price":           it.Price,
 				"imageUrl":        it.ImageURL,
+				"rating":          it.RatingAvg,
+				"ratingCount":     it.RatingCount,
 				"createdAt":       it.CreatedAt.Format(time.RFC3339Nano),
 			})
 		}
@@ -52,6 +96,8 @@ func (s PurchaseService) GetNearbyMerchants(ctx context.Context, f entities.Merc
 				"merchantId":       m.ID,
 				"merchantCategory": m.Category,
 				"imageUrl":         m.ImageURL,
+				"rating":           m.RatingAvg,
+				"ratingCount":      m.RatingCount,
 				"location": map[string]float64{
 					"lat":  m.Location.Lat,
 					"long": m.Location.Lon,
@@ -104,6 +150,17 @@ func (s PurchaseService) CreateEstimate(ctx context.Context, req dto.EstimateReq
 		 return dto.EstimateRes{}, utils.NewBadRequest("must have exactly one starting point")
 	}
 
+	if req.ScheduledFor != nil {
+		if err := s.schedule.check(*req.ScheduledFor, time.Now()); err != nil {
+			 return dto.EstimateRes{}, err
+		}
+	}
+
+	delivery, err := s.addresses.Delivery(ctx, req.UserID, req.AddressID, req.UserLocation)
+	if err != nil {
+		 return dto.EstimateRes{}, err
+	}
+
 	merchants,err := s.repository.GetAllMerchantByIDs(ctx, mercIDs)
 	if err != nil {
 		 return dto.EstimateRes{}, utils.NewInternal("failed to get merchants")
@@ -139,8 +196,8 @@ func (s PurchaseService) CreateEstimate(ctx context.Context, req dto.EstimateReq
 	}
 
 	merchantPoints = append(merchantPoints, utils.Point{
-		Lat: req.UserLocation.Lat, 
-		Lon: req.UserLocation.Lon,
+		Lat: delivery.Location.Lat, 
+		Lon: delivery.Location.Lon,
 	})
 
 	maxDistance := 0.0
@@ -155,30 +212,164 @@ func (s PurchaseService) CreateEstimate(ctx context.Context, req dto.EstimateReq
 		return dto.EstimateRes{}, utils.NewBadRequest("distance too far")
 	}
 
-	totalDistance := utils.NearestNeighborTSP(startId, merchantPoints)
+	matrix, err := s.routing.Matrix(ctx, merchantPoints)
+	if err != nil {
+		 return dto.EstimateRes{}, utils.NewInternal("failed to calculate route")
+	}
+
+	plan := optimizer.Solve(startId, matrix.Distances, routeSearchBudget)
+	_, totalDuration := matrix.PathCost(plan.Order)
+
+	route := make([]dto.EstimateRoute, 0, len(plan.Order))
+	quoteRoute := make([]entities.QuoteStop, 0, len(plan.Order))
+	for seq, idx := range plan.Order {
+		stop := dto.EstimateRoute{
+			Sequence: seq,
+			Location: dto.Location{Lat: merchantPoints[idx].Lat, Lon: merchantPoints[idx].Lon},
+		}
+
+		if idx == len(merchantPoints)-1 {
+			stop.IsDestination = true
+		} else {
+			stop.MerchantID = req.UserPurchase[idx].MerchantID
+		}
+
+		if seq > 0 {
+			prev := plan.Order[seq-1]
+			stop.DistanceKm = plan.Legs[seq-1]
+			stop.DurationMinutes = matrix.Durations[prev][idx]
+		}
+
+		route = append(route, stop)
+		quoteRoute = append(quoteRoute, entities.QuoteStop{
+			Sequence:        stop.Sequence,
+			MerchantID:      stop.MerchantID,
+			IsDestination:   stop.IsDestination,
+			Lat:             stop.Location.Lat,
+			Lon:             stop.Location.Lon,
+			DistanceKm:      stop.DistanceKm,
+			DurationMinutes: stop.DurationMinutes,
+		})
+	}
 
-	totalPrice := 0
+	subtotalIdx := make(map[string]int, len(req.UserPurchase))
+	subtotals := make([]entities.MerchantSubtotal, 0, len(req.UserPurchase))
+	promoLines := make([]entities.PromotionLine, 0, len(itemIDs))
 	orderItems := make([]entities.OrderItem, 0, len(itemIDs))
 	for _, order := range req.UserPurchase {
+		if _, ok := merchantMap[order.MerchantID]; !ok {
+			 return dto.EstimateRes{}, utils.NewNotFound("merchant not found")
+		}
+
+		idx, ok := subtotalIdx[order.MerchantID]
+		if !ok {
+			idx = len(subtotals)
+			subtotalIdx[order.MerchantID] = idx
+			subtotals = append(subtotals, entities.MerchantSubtotal{MerchantID: order.MerchantID})
+		}
+
 		for _, orderItem := range order.OrderItems {
-			item := mercItemMap[orderItem.ItemID]
-			totalPrice += orderItem.ItemQuantity * item.Price
+			item, ok := mercItemMap[orderItem.ItemID]
+			if !ok {
+				 return dto.EstimateRes{}, utils.NewNotFound("mercItem not found")
+			}
+			if item.MerchantID != order.MerchantID {
+				 return dto.EstimateRes{}, utils.NewBadRequest("item does not belong to merchant")
+			}
+			if !item.IsAvailable {
+				 return dto.EstimateRes{}, utils.NewBadRequest(fmt.Sprintf("item is not available: %s", item.Name))
+			}
+
+			subtotals[idx].Subtotal += orderItem.ItemQuantity * item.Price
+			promoLines = append(promoLines, entities.PromotionLine{
+				MerchantID:       order.MerchantID,
+				MerchantCategory: merchantMap[order.MerchantID].Category,
+				ProductCategory:  item.Category,
+				Amount:           orderItem.ItemQuantity * item.Price,
+			})
 			orderItems = append(orderItems, entities.OrderItem{
 				MerchantID:     order.MerchantID,
 				MerchantItemID: orderItem.ItemID,
 				Quantity:       orderItem.ItemQuantity,
+				UnitPrice:      item.Price,
 			})
 		}
 	}
 
+	breakdown := s.pricing.Price(pricing.Quote{
+		Destination: usrLocPoint,
+		Merchants:   subtotals,
+		DistanceKm:  plan.Total,
+		Stops:       len(subtotals),
+	})
+
+	var timing eta.Estimate
+	if req.ScheduledFor != nil {
+		timing, err = s.eta.EstimateFor(ctx, merchants, plan.Total, totalDuration, *req.ScheduledFor)
+	} else {
+		timing, err = s.eta.Estimate(ctx, merchants, plan.Total, totalDuration, time.Now())
+	}
+	if err != nil {
+		 return dto.EstimateRes{}, err
+	}
+
+	var releaseAt *time.Time
+	if req.ScheduledFor != nil {
+		releaseAt, err = s.scheduleRelease(ctx, *req.ScheduledFor, timing, mercIDs)
+		if err != nil {
+			 return dto.EstimateRes{}, err
+		}
+	}
+
+	var promotionID *string
+	if req.PromoCode != "" {
+		applied, err := s.promotions.Evaluate(ctx, req.PromoCode, req.UserID, promoLines, breakdown)
+		if err != nil {
+			 return dto.EstimateRes{}, err
+		}
+
+		s.pricing.ApplyPromotion(&breakdown, usrLocPoint, applied)
+		promotionID = &applied.PromotionID
+	}
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		 return dto.EstimateRes{}, utils.NewInternal("failed to encode estimate request")
+	}
+
 	tx,err := repository.BeginTx(ctx)
 	if err != nil {
 		 return dto.EstimateRes{}, err
 	}
 	defer tx.Rollback(ctx)
 
-	estimateRq := entities.Estimate{UserID: req.UserID}
-	estimateID, err := s.repository.CreateEstimateBatch(ctx, tx, estimateRq, orderItems)
+	estimateRq := entities.Estimate{
+		UserID:         req.UserID,
+		TotalPrice:     breakdown.Total,
+		PriceBreakdown: breakdown,
+		PromotionID:    promotionID,
+		Discount:       breakdown.Discount + breakdown.DeliveryDiscount,
+		ScheduledFor:   req.ScheduledFor,
+		ReleaseAt:      releaseAt,
+
+		StartMerchantID:  req.UserPurchase[startId].MerchantID,
+		RouteDistanceKm:  plan.Total,
+		EstimatedMinutes: timing.Minutes(),
+		TravelMinutes:    timing.AfterPickupMinutes(),
+		Delivery:         delivery,
+		Quote: entities.Quote{
+			Input: input,
+			Route: quoteRoute,
+			ETA: entities.QuoteETA{
+				ModelID:       timing.ModelID,
+				PrepMinutes:   timing.PrepMinutes,
+				TravelMinutes: timing.TravelMinutes,
+				DwellMinutes:  timing.DwellMinutes,
+			},
+			AlgorithmVersion: s.algorithmVersion(matrix.Source, plan, timing),
+		},
+	}
+	estimate, err := s.repository.CreateEstimateBatch(ctx, tx, estimateRq, orderItems, s.estimateTTL)
 	if err != nil {
 		 return dto.EstimateRes{}, err
 	}
@@ -188,24 +379,141 @@ func (s PurchaseService) CreateEstimate(ctx context.Context, req dto.EstimateReq
 	}
 
 	return dto.EstimateRes{
-		TotalPrice:                   totalPrice,
-		CalculatedEstimateId:         estimateID,
-		EstimatedDeliveryTimeMinutes: int(totalDistance * kmToMinute),
+		TotalPrice:                   breakdown.Total,
+		CalculatedEstimateId:         estimate.ID,
+		EstimatedDeliveryTimeMinutes: timing.Minutes(),
+		ETABreakdown: dto.ETABreakdown{
+			PrepMinutes:   timing.PrepMinutes,
+			TravelMinutes: timing.TravelMinutes,
+			DwellMinutes:  timing.DwellMinutes,
+		},
+		Route:                        route,
+		PriceBreakdown:               toPriceBreakdownDTO(breakdown),
+		ExpiresAt:                    estimate.ExpiresAt,
+		ScheduledFor:                 req.ScheduledFor,
+		Delivery:                     toDeliveryDTO(delivery),
 	}, nil
 }
 
+// GetEstimate returns an estimate of userID as it was quoted. Estimates made
+// before quotes were stored come back without input, route or version.
+func (s PurchaseService) GetEstimate(ctx context.Context, userID, estimateID string) (dto.EstimateDetail, error) {
+	estimate, err := s.ownedEstimate(ctx, userID, estimateID)
+	if err != nil {
+		 return dto.EstimateDetail{}, err
+	}
+
+	route := make([]dto.EstimateRoute, 0, len(estimate.Quote.Route))
+	for _, stop := range estimate.Quote.Route {
+		route = append(route, dto.EstimateRoute{
+			Sequence:        stop.Sequence,
+			MerchantID:      stop.MerchantID,
+			IsDestination:   stop.IsDestination,
+			Location:        dto.Location{Lat: stop.Lat, Lon: stop.Lon},
+			DistanceKm:      stop.DistanceKm,
+			DurationMinutes: stop.DurationMinutes,
+		})
+	}
+
+	return dto.EstimateDetail{
+		CalculatedEstimateId:         estimate.ID,
+		TotalPrice:                   estimate.TotalPrice,
+		EstimatedDeliveryTimeMinutes: estimate.EstimatedMinutes,
+		ETABreakdown: dto.ETABreakdown{
+			PrepMinutes:   estimate.Quote.ETA.PrepMinutes,
+			TravelMinutes: estimate.Quote.ETA.TravelMinutes,
+			DwellMinutes:  estimate.Quote.ETA.DwellMinutes,
+		},
+		StartMerchantID:  estimate.StartMerchantID,
+		RouteDistanceKm:  estimate.RouteDistanceKm,
+		Route:            route,
+		PriceBreakdown:   toPriceBreakdownDTO(estimate.PriceBreakdown),
+		Delivery:         toDeliveryDTO(estimate.Delivery),
+		Input:            estimate.Quote.Input,
+		AlgorithmVersion: estimate.Quote.AlgorithmVersion,
+		ScheduledFor:     estimate.ScheduledFor,
+		CreatedAt:        estimate.CreatedAt,
+		ExpiresAt:        estimate.ExpiresAt,
+		Expired:          estimate.Expired,
+		Used:             estimate.Used,
+	}, nil
+}
+
+// algorithmVersion identifies everything that shaped a quote: this code, the
+// routing provider that answered, whether the route was solved exactly and the
+// ETA model.
+func (s PurchaseService) algorithmVersion(routedBy string, plan optimizer.Result, timing eta.Estimate) string {
+	solver := "exact"
+	if !plan.Exact {
+		 solver = "local-search"
+	}
+
+	return fmt.Sprintf("%s routing=%s solver=%s eta=%d", quoteAlgorithm, routedBy, solver, timing.ModelID)
+}
+
+func (w SchedulingWindow) check(scheduledFor, now time.Time) error {
+	if scheduledFor.Before(now.Add(w.MinLead)) {
+		 return utils.NewBadRequest(fmt.Sprintf("scheduledFor must be at least %s from now", w.MinLead))
+	}
+	if scheduledFor.After(now.Add(w.MaxAhead)) {
+		 return utils.NewBadRequest(fmt.Sprintf("scheduledFor must be within %s from now", w.MaxAhead))
+	}
+
+	return nil
+}
+
+// scheduleRelease works out when a scheduled order is handed to its merchants:
+// early enough for the slowest kitchen and the whole route, stops included, to
+// finish by scheduledFor. Every merchant must be open both when the order is
+// released and when the courier picks it up.
+func (s PurchaseService) scheduleRelease(ctx context.Context, scheduledFor time.Time, est eta.Estimate, ids []string) (*time.Time, error) {
+	pickupAt := scheduledFor.Add(-time.Duration((est.TravelMinutes + est.DwellMinutes) * float64(time.Minute)))
+	releaseAt := pickupAt.Add(-time.Duration(est.PrepMinutes * float64(time.Minute)))
+
+	for _, at := range []time.Time{releaseAt, pickupAt} {
+		closed, err := s.repository.GetClosedMerchantIDs(ctx, ids, at)
+		if err != nil {
+			 return nil, err
+		}
+		if len(closed) > 0 {
+			 return nil, utils.NewBadRequest(fmt.Sprintf("merchants closed at the scheduled time: %s", strings.Join(closed, ", ")))
+		}
+	}
+
+	return &releaseAt, nil
+}
+
 func (s PurchaseService) CreateOrder(ctx context.Context, req dto.CreateOrderRequest) (dto.CreateOrderResponse, error) {
 	if err := ctx.Err(); err != nil {
 		 return dto.CreateOrderResponse{}, err
 	}
 
-	_, err := s.repository.GetEstimateDataByID(ctx, req.EstimateID)
+	estimate, err := s.ownedEstimate(ctx, req.UserID, req.EstimateID)
 	if err != nil {
-		 return dto.CreateOrderResponse{}, utils.NewNotFound("estimate does not exist")
+		 return dto.CreateOrderResponse{}, err
+	}
+
+	if estimate.Used {
+		 return dto.CreateOrderResponse{}, utils.NewConflict("estimate has already been used")
+	}
+
+	if estimate.Expired {
+		 return dto.CreateOrderResponse{}, utils.NewGone("estimate has expired, please request a new estimate")
+	}
+
+	if estimate.ReleaseAt != nil && estimate.ReleaseAt.Before(time.Now()) {
+		 return dto.CreateOrderResponse{}, utils.NewGone("scheduled time can no longer be met, please request a new estimate")
 	}
 
 	order := entities.Order{
+		UserID:     req.UserID,
 		EstimateID: req.EstimateID,
+		TotalPrice: estimate.TotalPrice,
+	}
+
+	method := req.PaymentMethod
+	if method == "" {
+		 method = payments.MethodCOD
 	}
 
 	tx,err := repository.BeginTx(ctx)
@@ -214,17 +522,63 @@ func (s PurchaseService) CreateOrder(ctx context.Context, req dto.CreateOrderReq
 	}
 	defer tx.Rollback(ctx)
 
-	if err := s.repository.CreateOrderFromEsID(ctx, tx, order); err != nil {
+	order, err = s.repository.CreateOrderFromEsID(ctx, tx, order)
+	if err != nil {
+		 return dto.CreateOrderResponse{}, err
+	}
+
+	if err := s.orders.RecordCreated(ctx, tx, order); err != nil {
+		 return dto.CreateOrderResponse{}, err
+	}
+
+	payment, err := s.payments.Prepare(ctx, tx, order, method)
+	if err != nil {
 		 return dto.CreateOrderResponse{}, err
 	}
 
+	if estimate.PromotionID != nil {
+		err := s.promotions.Redeem(ctx, tx, entities.Redemption{
+			PromotionID: *estimate.PromotionID,
+			UserID:      estimate.UserID,
+			OrderID:     order.ID,
+			EstimateID:  estimate.ID,
+			Discount:    estimate.Discount,
+		})
+		if err != nil {
+			 return dto.CreateOrderResponse{}, err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		 return dto.CreateOrderResponse{}, err
 	}
 
-	return dto.CreateOrderResponse{
-		OrderID: order.ID,
-	}, nil
+	resp := dto.CreateOrderResponse{
+		OrderID:      order.ID,
+		Status:       order.Status,
+		TotalPrice:   estimate.TotalPrice,
+		ScheduledFor: order.ScheduledFor,
+	}
+
+	// the order is committed at this point, so a failure to reach the provider
+	// leaves the payment pending instead of failing the request
+	started, err := s.payments.Start(ctx, payment)
+	if err != nil {
+		log.Error().Err(err).Str("orderId", order.ID).Msg("failed to start payment")
+		started = dto.Payment{
+			PaymentID: payment.ID,
+			Method:    payment.Provider,
+			Amount:    payment.Amount,
+			Status:    payment.Status,
+		}
+	}
+
+	if started.Status == payments.StatusFailed {
+		 resp.Status = entities.OrderCancelled
+	}
+	resp.Payment = &started
+
+	return resp, nil
 }
 
 func (s PurchaseService) GetAllOrder(ctx context.Context, filter entities.OrderFilter) ([]dto.OrderHistory, error) {
@@ -234,3 +588,133 @@ func (s PurchaseService) GetAllOrder(ctx context.Context, filter entities.OrderF
 
 	return s.repository.GetAllOrder(ctx, filter)
 }
+
+// Reorder rebuilds orderID as an estimate request for a new delivery address or
+// location. Items that are off the menu, from merchants closed right now, or
+// from merchants out of range of the new delivery are left out and reported.
+// The route starts where the original one did when that merchant is still in it.
+func (s PurchaseService) Reorder(ctx context.Context, userID, orderID string, req dto.ReorderRequest) (dto.ReorderResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.ReorderResponse{}, err
+	}
+
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return dto.ReorderResponse{}, utils.NewNotFound("order does not exist")
+	}
+
+	lines, err := s.repository.GetReorderLines(ctx, orderID)
+	if err != nil {
+		 return dto.ReorderResponse{}, err
+	}
+	if len(lines) == 0 {
+		 return dto.ReorderResponse{}, utils.NewNotFound("order does not exist")
+	}
+
+	if err := authorizeOwner(lines[0].UserID, userID, "order"); err != nil {
+		 return dto.ReorderResponse{}, err
+	}
+
+	delivery, err := s.addresses.Delivery(ctx, userID, req.AddressID, req.UserLocation)
+	if err != nil {
+		 return dto.ReorderResponse{}, err
+	}
+
+	ids := make([]string, 0, len(lines))
+	for _, l := range lines {
+		ids = append(ids, l.MerchantID)
+	}
+
+	closedIDs, err := s.repository.GetClosedMerchantIDs(ctx, ids, time.Now())
+	if err != nil {
+		 return dto.ReorderResponse{}, err
+	}
+	closed := make(map[string]bool, len(closedIDs))
+	for _, id := range closedIDs {
+		closed[id] = true
+	}
+
+	destination := utils.Point{Lat: delivery.Location.Lat, Lon: delivery.Location.Lon}
+	orders := make([]dto.EstimateOrder, 0)
+	orderIdx := make(map[string]int)
+	unavailable := make([]dto.UnavailableItem, 0)
+	for _, l := range lines {
+		reason := ""
+		switch {
+		case closed[l.MerchantID]:
+			reason = "MerchantClosed"
+		case utils.Haversine(destination, utils.Point{Lat: l.MerchantLocation.Lat, Lon: l.MerchantLocation.Lon}) > maxRadiusKm:
+			reason = "OutOfRange"
+		case !l.IsAvailable:
+			reason = "ItemUnavailable"
+		}
+
+		if reason != "" {
+			unavailable = append(unavailable, dto.UnavailableItem{
+				MerchantID: l.MerchantID,
+				ItemID:     l.ItemID,
+				Name:       l.ItemName,
+				Quantity:   l.Quantity,
+				Reason:     reason,
+			})
+			continue
+		}
+
+		idx, ok := orderIdx[l.MerchantID]
+		if !ok {
+			idx = len(orders)
+			orderIdx[l.MerchantID] = idx
+			orders = append(orders, dto.EstimateOrder{
+				MerchantID:      l.MerchantID,
+				IsStartingPoint: l.IsStartingPoint,
+				OrderItems:      []dto.EstimateOrderItem{},
+			})
+		}
+
+		orders[idx].OrderItems = append(orders[idx].OrderItems, dto.EstimateOrderItem{
+			ItemID:       l.ItemID,
+			ItemQuantity: l.Quantity,
+		})
+	}
+
+	hasStart := false
+	for _, o := range orders {
+		hasStart = hasStart || o.IsStartingPoint
+	}
+	if !hasStart && len(orders) > 0 {
+		 orders[0].IsStartingPoint = true
+	}
+
+	return dto.ReorderResponse{
+		EstimateRequest: dto.EstimateReq{
+			UserPurchase: orders,
+			AddressID:    req.AddressID,
+			UserLocation: req.UserLocation,
+		},
+		Unavailable: unavailable,
+	}, nil
+}
+
+func toPriceBreakdownDTO(b entities.PriceBreakdown) dto.PriceBreakdown {
+	merchants := make([]dto.MerchantSubtotal, 0, len(b.Merchants))
+	for _, m := range b.Merchants {
+		merchants = append(merchants, dto.MerchantSubtotal{MerchantID: m.MerchantID, Subtotal: m.Subtotal})
+	}
+
+	return dto.PriceBreakdown{
+		City:             b.City,
+		Merchants:        merchants,
+		ItemsSubtotal:    b.ItemsSubtotal,
+		DeliveryFee:      b.DeliveryFee,
+		StopSurcharge:    b.StopSurcharge,
+		ServiceFee:       b.ServiceFee,
+		PromoCode:        b.PromoCode,
+		Discount:         b.Discount,
+		DeliveryDiscount: b.DeliveryDiscount,
+		TaxName:          b.TaxName,
+		TaxRate:          b.TaxRate,
+		Taxable:          b.Taxable,
+		Tax:              b.Tax,
+		Rounding:         b.Rounding,
+		Total:            b.Total,
+	}
+}
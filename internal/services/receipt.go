@@ -0,0 +1,236 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/receipt"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"math"
+	"slices"
+	"time"
+)
+
+// receiptCurrency is the currency of every amount on a receipt.
+const receiptCurrency = "IDR"
+
+// ReceiptService issues receipts for delivered orders, as a PDF for people and
+// as an e-invoice for accounting systems. Both show the same invoice numbers,
+// which are assigned the first time either is requested.
+type ReceiptService struct {
+	repository repository.ReceiptRepository
+}
+
+func NewReceiptService(repository repository.ReceiptRepository) ReceiptService {
+	return ReceiptService{repository: repository}
+}
+
+// PDF renders the receipt of an order of userID.
+func (s ReceiptService) PDF(ctx context.Context, userID, orderID string) ([]byte, error) {
+	rc, err := s.issue(ctx, userID, orderID)
+	if err != nil {
+		 return nil, err
+	}
+
+	return receipt.Render(rc, time.Local), nil
+}
+
+// EInvoice returns the receipt of an order of userID in structured form.
+func (s ReceiptService) EInvoice(ctx context.Context, userID, orderID string) (dto.EInvoice, error) {
+	rc, err := s.issue(ctx, userID, orderID)
+	if err != nil {
+		 return dto.EInvoice{}, err
+	}
+
+	invoices := make([]dto.MerchantInvoice, 0, len(rc.Merchants))
+	for _, m := range rc.Merchants {
+		lines := make([]dto.InvoiceLine, 0, len(m.Lines))
+		for _, l := range m.Lines {
+			lines = append(lines, dto.InvoiceLine{
+				ItemID:    l.ItemID,
+				Name:      l.Name,
+				Category:  l.Category,
+				Quantity:  l.Quantity,
+				UnitPrice: l.UnitPrice,
+				Amount:    l.Amount,
+			})
+		}
+
+		invoices = append(invoices, dto.MerchantInvoice{
+			InvoiceNumber: m.Invoice.Number,
+			Sequence:      m.Invoice.Sequence,
+			IssuedAt:      m.Invoice.IssuedAt,
+			Seller: dto.InvoiceSeller{
+				MerchantID: m.MerchantID,
+				Name:       m.Name,
+				Location:   dto.Location{Lat: m.Location.Lat, Lon: m.Location.Lon},
+			},
+			Lines:    lines,
+			Subtotal: m.Subtotal,
+			Tax: dto.InvoiceTax{
+				Name:   rc.PriceBreakdown.TaxName,
+				Rate:   rc.PriceBreakdown.TaxRate,
+				Base:   m.TaxBase,
+				Amount: m.Tax,
+			},
+		})
+	}
+
+	return dto.EInvoice{
+		OrderID:   rc.OrderID,
+		Status:    rc.Status,
+		OrderedAt: rc.OrderedAt,
+		Currency:  receiptCurrency,
+		Buyer: dto.InvoiceBuyer{
+			UserID:   rc.UserID,
+			Username: rc.Username,
+			Email:    rc.Email,
+		},
+		Delivery:       toDeliveryDTO(rc.Delivery),
+		Invoices:       invoices,
+		PriceBreakdown: toPriceBreakdownDTO(rc.PriceBreakdown),
+		Tax: dto.InvoiceTax{
+			Name:   rc.PriceBreakdown.TaxName,
+			Rate:   rc.PriceBreakdown.TaxRate,
+			Base:   taxable(rc.PriceBreakdown),
+			Amount: rc.PriceBreakdown.Tax,
+		},
+		Total: rc.PriceBreakdown.Total,
+	}, nil
+}
+
+// issue loads the receipt of a delivered order and numbers its invoices, one
+// per merchant, if that has not happened yet.
+func (s ReceiptService) issue(ctx context.Context, userID, orderID string) (entities.Receipt, error) {
+	if err := ctx.Err(); err != nil {
+		 return entities.Receipt{}, err
+	}
+
+	rc, err := s.ownedReceipt(ctx, userID, orderID)
+	if err != nil {
+		 return entities.Receipt{}, err
+	}
+
+	if rc.Status != entities.OrderDelivered {
+		 return entities.Receipt{}, utils.NewBadRequest("receipts are only available for delivered orders")
+	}
+
+	merchantIDs := make([]string, 0, len(rc.Merchants))
+	for _, m := range rc.Merchants {
+		merchantIDs = append(merchantIDs, m.MerchantID)
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return entities.Receipt{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	invoices, err := s.repository.IssueInvoices(ctx, tx, rc.OrderID, merchantIDs)
+	if err != nil {
+		 return entities.Receipt{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return entities.Receipt{}, err
+	}
+
+	for i := range rc.Merchants {
+		rc.Merchants[i].Invoice = invoices[rc.Merchants[i].MerchantID]
+	}
+	quotedSubtotals(&rc)
+	allocateTax(&rc)
+
+	return rc, nil
+}
+
+// quotedSubtotals replaces the subtotal of merchants with a line of unknown
+// price by the subtotal quoted on the estimate, which is what was charged.
+func quotedSubtotals(rc *entities.Receipt) {
+	for i, m := range rc.Merchants {
+		if !slices.ContainsFunc(m.Lines, func(l entities.ReceiptLine) bool { return l.UnitPrice == nil }) {
+			 continue
+		}
+
+		for _, q := range rc.PriceBreakdown.Merchants {
+			if q.MerchantID == m.MerchantID {
+				 rc.Merchants[i].Subtotal = q.Subtotal
+			}
+		}
+	}
+}
+
+// allocateTax splits the order's taxable amount and tax over its merchants in
+// proportion to what each sold, as quoted on the estimate.
+func allocateTax(rc *entities.Receipt) {
+	quoted := make(map[string]int, len(rc.PriceBreakdown.Merchants))
+	for _, m := range rc.PriceBreakdown.Merchants {
+		quoted[m.MerchantID] = m.Subtotal
+	}
+
+	weights := make([]int, 0, len(rc.Merchants))
+	for _, m := range rc.Merchants {
+		if subtotal, ok := quoted[m.MerchantID]; ok {
+			weights = append(weights, subtotal)
+		} else {
+			weights = append(weights, m.Subtotal)
+		}
+	}
+
+	bases := allocate(taxable(rc.PriceBreakdown), weights)
+	taxes := allocate(rc.PriceBreakdown.Tax, weights)
+	for i := range rc.Merchants {
+		rc.Merchants[i].TaxBase = bases[i]
+		rc.Merchants[i].Tax = taxes[i]
+	}
+}
+
+// taxable is the amount the order's tax was charged on. Breakdowns stored
+// before it was recorded get it back from the tax and its rate.
+func taxable(b entities.PriceBreakdown) int {
+	if b.Taxable > 0 || b.Tax == 0 || b.TaxRate == 0 {
+		 return b.Taxable
+	}
+	return int(math.Round(float64(b.Tax) * 100 / b.TaxRate))
+}
+
+// allocate splits amount in proportion to weights. The parts are rounded down
+// and what is left goes one by one to the largest remainders, so they always
+// add up to amount. Without any weight the parts are equal.
+func allocate(amount int, weights []int) []int {
+	parts := make([]int, len(weights))
+	if len(weights) == 0 {
+		 return parts
+	}
+
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		weights = slices.Repeat([]int{1}, len(weights))
+		total = len(weights)
+	}
+
+	remainders := make([]int, len(weights))
+	left := amount
+	for i, w := range weights {
+		parts[i] = amount * w / total
+		remainders[i] = amount * w % total
+		left -= parts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return remainders[b] - remainders[a] })
+
+	for i := 0; left > 0; i++ {
+		parts[order[i%len(order)]]++
+		left--
+	}
+
+	return parts
+}
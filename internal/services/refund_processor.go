@@ -0,0 +1,129 @@
+package services
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/payments"
+	"belimang/internal/repository"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	refundBatch = 50
+	// a refund is sent at most this many times before it is left Failed for
+	// someone to look into
+	refundMaxAttempts = 6
+	refundRetryBase   = time.Minute
+	refundRetryMax    = 6 * time.Hour
+	// how long a claimed batch is kept from other processors; it has to cover
+	// one provider call per refund in the batch
+	refundLease = 15 * time.Minute
+)
+
+// RefundProcessor periodically sends pending refunds to the provider that took
+// the original payment and records the outcome. Failed refunds are sent again
+// with exponential backoff. Refunds are claimed before they are sent, so
+// several processors never send the same refund at once.
+type RefundProcessor struct {
+	repository repository.PaymentRepository
+	providers  payments.Registry
+	interval   time.Duration
+}
+
+func NewRefundProcessor(repository repository.PaymentRepository, providers payments.Registry, interval time.Duration) RefundProcessor {
+	return RefundProcessor{
+		repository: repository,
+		providers:  providers,
+		interval:   interval,
+	}
+}
+
+// Run processes refunds until ctx is cancelled. It is meant to be started in its own goroutine.
+func (p RefundProcessor) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.process(ctx)
+		}
+	}
+}
+
+func (p RefundProcessor) process(ctx context.Context) {
+	pending, err := p.repository.ClaimRefunds(ctx, refundBatch, refundLease)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to claim pending refunds")
+		return
+	}
+
+	for _, rf := range pending {
+		result := entities.Refund{
+			ID:        rf.RefundID,
+			PaymentID: &rf.PaymentID,
+			Status:    entities.RefundCompleted,
+		}
+
+		provider, ok := p.providers.Get(rf.Provider)
+		if !ok {
+			result.Status = entities.RefundFailed
+			result.FailureReason = "payment provider is not configured"
+		} else {
+			ref, err := provider.Refund(ctx, payments.RefundRequest{
+				RefundID:  rf.RefundID,
+				Reference: rf.ProviderRef,
+				UserID:    rf.UserID,
+				Amount:    rf.Amount,
+			})
+			if err != nil {
+				result.Status = entities.RefundFailed
+				result.FailureReason = err.Error()
+			} else {
+				result.ProviderRef = &ref
+			}
+		}
+
+		retryIn := time.Duration(0)
+		if result.Status == entities.RefundFailed {
+			retryIn = refundRetryDelay(rf.Attempts + 1)
+			if retryIn == 0 {
+				log.Error().Str("refundId", rf.RefundID).Str("reason", result.FailureReason).Msg("refund failed, giving up")
+			}
+		}
+
+		if err := p.complete(ctx, result, rf.FullRefund, retryIn); err != nil {
+			log.Error().Err(err).Str("refundId", rf.RefundID).Msg("failed to record refund result")
+		}
+	}
+}
+
+// refundRetryDelay is how long to wait before sending a refund again after its
+// attempts-th try failed, doubling from refundRetryBase up to refundRetryMax.
+// It is zero once the refund has used up its attempts.
+func refundRetryDelay(attempts int) time.Duration {
+	if attempts >= refundMaxAttempts {
+		 return 0
+	}
+
+	delay := refundRetryBase << (attempts - 1)
+	return min(delay, refundRetryMax)
+}
+
+func (p RefundProcessor) complete(ctx context.Context, result entities.Refund, fullRefund bool, retryIn time.Duration) error {
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.repository.CompleteRefund(ctx, tx, result, fullRefund, retryIn); err != nil {
+		 return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ReviewService collects ratings of merchants, items and couriers from
+// delivered orders and lets admins moderate them. Every change keeps the
+// summary ratings on merchants, items and couriers up to date.
+type ReviewService struct {
+	repository repository.ReviewRepository
+	files      FileService
+}
+
+func NewReviewService(repository repository.ReviewRepository, files FileService) ReviewService {
+	return ReviewService{
+		repository: repository,
+		files:      files,
+	}
+}
+
+// CreateReview rates one merchant of a delivered order of userID. Rated items
+// must be ones the order had from that merchant.
+func (s ReviewService) CreateReview(ctx context.Context, userID, orderID string, req dto.CreateReviewRequest) (dto.Review, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Review{}, err
+	}
+
+	order, err := s.reviewableOrder(ctx, userID, orderID)
+	if err != nil {
+		 return dto.Review{}, err
+	}
+
+	if order.Status != entities.OrderDelivered {
+		 return dto.Review{}, utils.NewBadRequest("only delivered orders can be reviewed")
+	}
+
+	ordered, ok := order.Items[req.MerchantID]
+	if !ok {
+		 return dto.Review{}, utils.NewBadRequest("merchant is not part of this order")
+	}
+
+	items := make([]entities.ItemRating, 0, len(req.Items))
+	for _, it := range req.Items {
+		if !slices.Contains(ordered, it.ItemID) {
+			 return dto.Review{}, utils.NewBadRequest("item is not part of this order: " + it.ItemID)
+		}
+		if slices.ContainsFunc(items, func(r entities.ItemRating) bool { return r.ItemID == it.ItemID }) {
+			 return dto.Review{}, utils.NewBadRequest("item is rated more than once: " + it.ItemID)
+		}
+		items = append(items, entities.ItemRating{ItemID: it.ItemID, Rating: it.Rating})
+	}
+
+	photos := make([]string, 0, len(req.PhotoURLs))
+	for _, photo := range req.PhotoURLs {
+		name, err := s.uploadedPhoto(ctx, photo, userID)
+		if err != nil {
+			 return dto.Review{}, err
+		}
+		photos = append(photos, name)
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.Review{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	review, err := s.repository.CreateReview(ctx, tx, entities.Review{
+		OrderID:    orderID,
+		MerchantID: req.MerchantID,
+		UserID:     userID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+		PhotoKeys:  photos,
+		Items:      items,
+	})
+	if err != nil {
+		 return dto.Review{}, err
+	}
+
+	if err := s.repository.RefreshReviewRatings(ctx, tx, review.ID); err != nil {
+		 return dto.Review{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.Review{}, err
+	}
+
+	return s.toReviewDTO(ctx, review)
+}
+
+// CreateCourierReview rates the courier who delivered an order of userID.
+func (s ReviewService) CreateCourierReview(ctx context.Context, userID, orderID string, req dto.CreateCourierReviewRequest) (dto.CourierReview, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	order, err := s.reviewableOrder(ctx, userID, orderID)
+	if err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	if order.Status != entities.OrderDelivered {
+		 return dto.CourierReview{}, utils.NewBadRequest("only delivered orders can be reviewed")
+	}
+	if order.CourierID == nil {
+		 return dto.CourierReview{}, utils.NewBadRequest("order was not delivered by a courier")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.CourierReview{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	review, err := s.repository.CreateCourierReview(ctx, tx, entities.CourierReview{
+		OrderID:   orderID,
+		CourierID: *order.CourierID,
+		UserID:    userID,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+	})
+	if err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	if err := s.repository.RefreshCourierRating(ctx, tx, review.CourierID); err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	return toCourierReviewDTO(review), nil
+}
+
+// GetMerchantReviews lists the published reviews of a merchant, newest first.
+func (s ReviewService) GetMerchantReviews(ctx context.Context, merchantID string, limit, offset int) (dto.ReviewResponse, error) {
+	if _, err := uuid.Parse(merchantID); err != nil {
+		 return dto.ReviewResponse{}, utils.NewNotFound("merchant does not exist")
+	}
+
+	resp, err := s.GetReviews(ctx, entities.ReviewFilter{
+		MerchantID: merchantID,
+		Status:     entities.ReviewPublished,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		 return dto.ReviewResponse{}, err
+	}
+
+	// moderation details are for admins only
+	for i := range resp.Data {
+		resp.Data[i].ModerationReason = ""
+		resp.Data[i].ModeratedAt = nil
+	}
+
+	return resp, nil
+}
+
+func (s ReviewService) GetReviews(ctx context.Context, filter entities.ReviewFilter) (dto.ReviewResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.ReviewResponse{}, err
+	}
+
+	if err := checkReviewFilter(filter); err != nil {
+		 return dto.ReviewResponse{}, err
+	}
+
+	reviews, total, err := s.repository.GetReviews(ctx, filter)
+	if err != nil {
+		 return dto.ReviewResponse{}, err
+	}
+
+	data := make([]dto.Review, 0, len(reviews))
+	for _, rv := range reviews {
+		review, err := s.toReviewDTO(ctx, rv)
+		if err != nil {
+			 return dto.ReviewResponse{}, err
+		}
+		data = append(data, review)
+	}
+
+	return dto.ReviewResponse{
+		Data: data,
+		Meta: dto.Meta{Limit: filter.Limit, Offset: filter.Offset, Total: total},
+	}, nil
+}
+
+func (s ReviewService) GetCourierReviews(ctx context.Context, filter entities.ReviewFilter) (dto.CourierReviewResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CourierReviewResponse{}, err
+	}
+
+	if err := checkReviewFilter(filter); err != nil {
+		 return dto.CourierReviewResponse{}, err
+	}
+
+	reviews, total, err := s.repository.GetCourierReviews(ctx, filter)
+	if err != nil {
+		 return dto.CourierReviewResponse{}, err
+	}
+
+	data := make([]dto.CourierReview, 0, len(reviews))
+	for _, rv := range reviews {
+		data = append(data, toCourierReviewDTO(rv))
+	}
+
+	return dto.CourierReviewResponse{
+		Data: data,
+		Meta: dto.Meta{Limit: filter.Limit, Offset: filter.Offset, Total: total},
+	}, nil
+}
+
+// ModerateReview publishes or hides a review. Hidden reviews stop counting
+// towards the merchant's and items' ratings.
+func (s ReviewService) ModerateReview(ctx context.Context, m entities.ReviewModeration) (dto.Review, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Review{}, err
+	}
+
+	if _, err := uuid.Parse(m.ReviewID); err != nil {
+		 return dto.Review{}, utils.NewNotFound("review does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.Review{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	review, err := s.repository.ModerateReview(ctx, tx, m)
+	if err != nil {
+		 return dto.Review{}, err
+	}
+
+	if err := s.repository.RefreshReviewRatings(ctx, tx, review.ID); err != nil {
+		 return dto.Review{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.Review{}, err
+	}
+
+	return s.toReviewDTO(ctx, review)
+}
+
+// ModerateCourierReview publishes or hides a courier review. Hidden reviews
+// stop counting towards the courier's rating.
+func (s ReviewService) ModerateCourierReview(ctx context.Context, m entities.ReviewModeration) (dto.CourierReview, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	if _, err := uuid.Parse(m.ReviewID); err != nil {
+		 return dto.CourierReview{}, utils.NewNotFound("courier review does not exist")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.CourierReview{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	review, err := s.repository.ModerateCourierReview(ctx, tx, m)
+	if err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	if err := s.repository.RefreshCourierRating(ctx, tx, review.CourierID); err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.CourierReview{}, err
+	}
+
+	return toCourierReviewDTO(review), nil
+}
+
+func checkReviewFilter(f entities.ReviewFilter) error {
+	if f.Status != "" && f.Status != entities.ReviewPublished && f.Status != entities.ReviewHidden {
+		 return utils.NewBadRequest("status must be Published or Hidden")
+	}
+
+	for _, id := range []string{f.MerchantID, f.CourierID} {
+		if _, err := uuid.Parse(id); id != "" && err != nil {
+			 return utils.NewBadRequest("invalid id filter: " + id)
+		}
+	}
+
+	return nil
+}
+
+// toReviewDTO signs a fresh link for every photo of rv.
+func (s ReviewService) toReviewDTO(ctx context.Context, rv entities.Review) (dto.Review, error) {
+	items := make([]dto.ItemRating, 0, len(rv.Items))
+	for _, it := range rv.Items {
+		items = append(items, dto.ItemRating{ItemID: it.ItemID, Rating: it.Rating})
+	}
+
+	photos := make([]string, 0, len(rv.PhotoKeys))
+	for _, name := range rv.PhotoKeys {
+		link, err := s.files.PresignedURL(ctx, name)
+		if err != nil {
+			 return dto.Review{}, utils.NewInternal("failed to sign review photo link")
+		}
+		photos = append(photos, link)
+	}
+
+	return dto.Review{
+		ID:               rv.ID,
+		OrderID:          rv.OrderID,
+		MerchantID:       rv.MerchantID,
+		Rating:           rv.Rating,
+		Comment:          rv.Comment,
+		PhotoURLs:        photos,
+		Items:            items,
+		Status:           rv.Status,
+		ModerationReason: rv.ModerationReason,
+		ModeratedAt:      rv.ModeratedAt,
+		CreatedAt:        rv.CreatedAt,
+	}, nil
+}
+
+func toCourierReviewDTO(rv entities.CourierReview) dto.CourierReview {
+	return dto.CourierReview{
+		ID:               rv.ID,
+		OrderID:          rv.OrderID,
+		CourierID:        rv.CourierID,
+		Rating:           rv.Rating,
+		Comment:          rv.Comment,
+		Status:           rv.Status,
+		ModerationReason: rv.ModerationReason,
+		ModeratedAt:      rv.ModeratedAt,
+		CreatedAt:        rv.CreatedAt,
+	}
+}
+
+// uploadedPhoto returns the object name of photo, a link to a review photo
+// userID uploaded: it lives under reviews/<userID>/ in the storage bucket and
+// is actually there.
+func (s ReviewService) uploadedPhoto(ctx context.Context, photo, userID string) (string, error) {
+	invalid := utils.NewBadRequest("photos must be uploaded through POST /users/reviews/photos")
+
+	name, ok := s.files.ObjectName(photo)
+	if !ok {
+		 return "", invalid
+	}
+
+	file, ok := strings.CutPrefix(name, "reviews/"+userID+"/")
+	if !ok || file == "" || strings.Contains(file, "/") {
+		 return "", invalid
+	}
+
+	exists, err := s.files.Exists(ctx, name)
+	if err != nil {
+		 return "", utils.NewInternal("failed to check review photo")
+	}
+	if !exists {
+		 return "", invalid
+	}
+
+	return name, nil
+}
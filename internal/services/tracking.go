@@ -0,0 +1,180 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/events"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxPingSkew bounds how far in the future a ping may claim to be recorded,
+	// to allow for device clocks running a little fast.
+	maxPingSkew = 2 * time.Minute
+	// maxPingAge bounds how late a ping may arrive, long enough for a courier
+	// who lost signal during a shift. Older ones would need partitions that are
+	// not kept open.
+	maxPingAge = 6 * time.Hour
+)
+
+// TrackingService takes in courier GPS pings and serves the trips built from them.
+type TrackingService struct {
+	repository repository.TrackingRepository
+	couriers   repository.CourierRepository
+	orders     repository.OrderRepository
+	ingestor   LocationIngestor
+	events     events.Bus
+}
+
+func NewTrackingService(repository repository.TrackingRepository, couriers repository.CourierRepository, orders repository.OrderRepository, ingestor LocationIngestor, bus events.Bus) TrackingService {
+	return TrackingService{
+		repository: repository,
+		couriers:   couriers,
+		orders:     orders,
+		ingestor:   ingestor,
+		events:     bus,
+	}
+}
+
+// RecordLocations queues a batch of pings for bulk insert. Pings taken since
+// the courier got the order they are delivering are tagged with it; older ones
+// arrived late and may belong to an earlier trip, so they are left untagged.
+// The newest ping also serves as a dispatch heartbeat and, when it belongs to
+// the current order, is pushed to everyone following it.
+func (s TrackingService) RecordLocations(ctx context.Context, userID string, req dto.LocationBatchRequest) (dto.LocationBatchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.LocationBatchResponse{}, err
+	}
+
+	courier, err := s.couriers.GetCourierByUser(ctx, userID)
+	if err != nil {
+		 return dto.LocationBatchResponse{}, err
+	}
+
+	orderID, since, err := s.repository.GetActiveOrder(ctx, courier.ID)
+	if err != nil {
+		 return dto.LocationBatchResponse{}, err
+	}
+
+	now := time.Now()
+	locations := make([]entities.CourierLocation, 0, len(req.Points))
+	latest := 0
+	for i, p := range req.Points {
+		if p.RecordedAt.After(now.Add(maxPingSkew)) {
+			 return dto.LocationBatchResponse{}, utils.NewBadRequest("recordedAt is in the future")
+		}
+		if p.RecordedAt.Before(now.Add(-maxPingAge)) {
+			 return dto.LocationBatchResponse{}, utils.NewBadRequest("recordedAt is too old")
+		}
+		if p.RecordedAt.After(req.Points[latest].RecordedAt) {
+			 latest = i
+		}
+
+		var pingOrderID *string
+		if orderID != nil && !p.RecordedAt.Before(since) {
+			 pingOrderID = orderID
+		}
+
+		locations = append(locations, entities.CourierLocation{
+			CourierID:  courier.ID,
+			OrderID:    pingOrderID,
+			RecordedAt: p.RecordedAt,
+			Lat:        p.Lat,
+			Lon:        p.Lon,
+			AccuracyM:  p.AccuracyM,
+			SpeedKmh:   p.SpeedKmh,
+		})
+	}
+
+	if err := s.ingestor.Enqueue(locations); err != nil {
+		 return dto.LocationBatchResponse{}, err
+	}
+
+	last := req.Points[latest]
+	if _, err := s.couriers.RecordHeartbeat(ctx, courier.ID, entities.Location{Lat: last.Lat, Lon: last.Lon}); err != nil {
+		 return dto.LocationBatchResponse{}, err
+	}
+
+	if current := locations[latest].OrderID; current != nil {
+		s.publishLocation(context.WithoutCancel(ctx), *current, last)
+	}
+
+	return dto.LocationBatchResponse{Accepted: len(locations), OrderID: orderID}, nil
+}
+
+func (s TrackingService) publishLocation(ctx context.Context, orderID string, p dto.LocationPing) {
+	e, err := events.NewEvent(events.TypeCourierLocation, orderID, dto.LocationPing{
+		Lat:        p.Lat,
+		Lon:        p.Lon,
+		RecordedAt: p.RecordedAt,
+		SpeedKmh:   p.SpeedKmh,
+	})
+	if err == nil {
+		 err = s.events.Publish(ctx, e)
+	}
+	if err != nil {
+		 log.Error().Err(err).Str("orderId", orderID).Msg("failed to publish courier location")
+	}
+}
+
+// GetTrip returns the route the courier drove for one of the user's orders.
+func (s TrackingService) GetTrip(ctx context.Context, userID, orderID string) (dto.Trip, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Trip{}, err
+	}
+
+	if _, err := uuid.Parse(orderID); err != nil {
+		 return dto.Trip{}, utils.NewNotFound("order does not exist")
+	}
+
+	ownerID, err := s.orders.GetOrderOwner(ctx, orderID)
+	if err != nil {
+		 return dto.Trip{}, utils.NewNotFound("order does not exist")
+	}
+	if err := authorizeOwner(ownerID, userID, "order"); err != nil {
+		 return dto.Trip{}, err
+	}
+
+	trip, err := s.repository.GetTrip(ctx, orderID)
+	if err != nil {
+		 return dto.Trip{}, err
+	}
+
+	return dto.Trip{
+		OrderID:          trip.OrderID,
+		Polyline:         trip.Polyline,
+		Points:           trip.PolylinePoints,
+		DistanceKm:       trip.DistanceKm,
+		DurationMinutes:  float64(trip.DurationSeconds) / 60,
+		EstimatedMinutes: trip.EstimatedMinutes,
+		StartedAt:        trip.StartedAt,
+		EndedAt:          trip.EndedAt,
+	}, nil
+}
+
+func (s TrackingService) GetETAAccuracy(ctx context.Context, filter entities.ETAAccuracyFilter) (dto.ETAAccuracy, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.ETAAccuracy{}, err
+	}
+
+	acc, err := s.repository.GetETAAccuracy(ctx, filter)
+	if err != nil {
+		 return dto.ETAAccuracy{}, err
+	}
+
+	return dto.ETAAccuracy{
+		Trips:               acc.Trips,
+		MeanErrorMinutes:    acc.MeanErrorMinutes,
+		MeanAbsErrorMinutes: acc.MeanAbsErrorMinutes,
+		MeanAbsPctError:     acc.MeanAbsPctError,
+		P50AbsErrorMinutes:  acc.P50AbsErrorMinutes,
+		P90AbsErrorMinutes:  acc.P90AbsErrorMinutes,
+		WithinFiveMinutes:   acc.WithinFiveMinutes,
+	}, nil
+}
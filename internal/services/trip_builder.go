@@ -0,0 +1,117 @@
+package services
+
+import (
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/tracking"
+	"belimang/internal/utils"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	tripBuildBatch = 50
+	// a trip is built once no more pings from before the drop-off can arrive:
+	// they are accepted up to maxPingAge late and then wait in the ingestor's
+	// buffer for a flush
+	tripSettleTime = maxPingAge + 10*time.Minute
+
+	// pings less precise than this are GPS noise and skew the distance
+	maxPingAccuracyM = 50
+	// polyline points closer than this to the simplified line are dropped
+	polylineToleranceM = 10
+)
+
+// TripBuilder turns the pings of delivered orders into a trip: a simplified
+// polyline plus the distance and time actually driven between pickup and
+// drop-off, kept next to the travel time the estimate quoted. Trips are built
+// tripSettleTime after delivery, when late pings can no longer come in.
+type TripBuilder struct {
+	repository repository.TrackingRepository
+	interval   time.Duration
+}
+
+func NewTripBuilder(repository repository.TrackingRepository, interval time.Duration) TripBuilder {
+	return TripBuilder{
+		repository: repository,
+		interval:   interval,
+	}
+}
+
+// Run builds trips until ctx is cancelled. It is meant to be started in its own goroutine.
+func (b TripBuilder) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.buildPending(ctx)
+		}
+	}
+}
+
+func (b TripBuilder) buildPending(ctx context.Context) {
+	sources, err := b.repository.GetUntrackedDeliveries(ctx, tripBuildBatch, tripSettleTime)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list untracked deliveries")
+		return
+	}
+
+	for _, src := range sources {
+		if err := b.build(ctx, src); err != nil {
+			log.Error().Err(err).Str("orderId", src.OrderID).Msg("failed to build order trip")
+		}
+	}
+}
+
+// build stores the trip of one delivery from the courier's pings between
+// pickup and drop-off. Orders without usable pings still get an empty trip so
+// they are not looked at again.
+func (b TripBuilder) build(ctx context.Context, src entities.TripSource) error {
+	var locations []entities.CourierLocation
+	if src.PickedUpAt != nil {
+		found, err := b.repository.GetCourierLocations(ctx, src.CourierID, *src.PickedUpAt, src.DeliveredAt)
+		if err != nil {
+			 return err
+		}
+		locations = found
+	}
+
+	path := make([]utils.Point, 0, len(locations))
+	var startedAt, endedAt *time.Time
+	for _, l := range locations {
+		if l.AccuracyM != nil && *l.AccuracyM > maxPingAccuracyM {
+			continue
+		}
+
+		path = append(path, utils.Point{Lat: l.Lat, Lon: l.Lon})
+		if startedAt == nil {
+			startedAt = &l.RecordedAt
+		}
+		endedAt = &l.RecordedAt
+	}
+
+	trip := entities.Trip{
+		OrderID:          src.OrderID,
+		CourierID:        &src.CourierID,
+		RawPoints:        len(path),
+		EstimatedMinutes: src.EstimatedMinutes,
+		StartedAt:        startedAt,
+		EndedAt:          endedAt,
+	}
+
+	if len(path) >= 2 {
+		simplified := tracking.Simplify(path, polylineToleranceM)
+		trip.Polyline = tracking.EncodePolyline(simplified)
+		trip.PolylinePoints = len(simplified)
+		trip.DistanceKm = tracking.LengthKm(path)
+		trip.DurationSeconds = int(endedAt.Sub(*startedAt).Seconds())
+	}
+
+	return b.repository.InsertTrip(ctx, trip)
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"belimang/internal/dto"
+	"belimang/internal/entities"
+	"belimang/internal/repository"
+	"belimang/internal/utils"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// walletSources names the system account on the other side of each kind of
+// wallet movement.
+var walletSources = map[string]string{
+	entities.LedgerTopUp:        entities.AccountCashClearing,
+	entities.LedgerPromoCredit:  entities.AccountPromoExpense,
+	entities.LedgerRefund:       entities.AccountRevenue,
+	entities.LedgerOrderPayment: entities.AccountRevenue,
+}
+
+// WalletService keeps customer balances in a double-entry ledger. Every
+// movement is a transaction whose entries sum to zero between the customer's
+// wallet and a system account, keyed by a reference so retries post once.
+type WalletService struct {
+	repository repository.LedgerRepository
+}
+
+func NewWalletService(repository repository.LedgerRepository) WalletService {
+	return WalletService{repository: repository}
+}
+
+func (s WalletService) GetWallet(ctx context.Context, userID string) (dto.Wallet, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.Wallet{}, err
+	}
+
+	wallet, err := s.repository.GetWallet(ctx, userID)
+	if isNotFound(err) {
+		 return dto.Wallet{}, nil
+	}
+	if err != nil {
+		 return dto.Wallet{}, err
+	}
+
+	return dto.Wallet{Balance: wallet.Balance, UpdatedAt: wallet.UpdatedAt}, nil
+}
+
+func (s WalletService) GetTransactions(ctx context.Context, userID string, limit, offset int) (dto.WalletTransactionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.WalletTransactionResponse{}, err
+	}
+
+	entries, total, err := s.repository.GetWalletEntries(ctx, userID, limit, offset)
+	if err != nil {
+		 return dto.WalletTransactionResponse{}, err
+	}
+
+	data := make([]dto.WalletTransaction, 0, len(entries))
+	for _, e := range entries {
+		data = append(data, dto.WalletTransaction{
+			TransactionID: e.TransactionID,
+			Type:          e.Kind,
+			Reference:     e.Reference,
+			Description:   e.Description,
+			Amount:        e.Amount,
+			BalanceAfter:  e.BalanceAfter,
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+
+	return dto.WalletTransactionResponse{
+		Data: data,
+		Meta: dto.Meta{Total: total, Limit: limit, Offset: offset},
+	}, nil
+}
+
+// Credit adds a top-up or promotional credit to a customer's wallet on behalf
+// of an admin.
+func (s WalletService) Credit(ctx context.Context, adminID, userID string, req dto.CreditWalletRequest) (dto.CreditWalletResponse, error) {
+	if err := ctx.Err(); err != nil {
+		 return dto.CreditWalletResponse{}, err
+	}
+
+	if _, err := uuid.Parse(userID); err != nil {
+		 return dto.CreditWalletResponse{}, utils.NewNotFound("user not found")
+	}
+
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return dto.CreditWalletResponse{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	txID, err := s.post(ctx, tx, userID, req.Amount, entities.LedgerTransaction{
+		Kind:        req.Type,
+		Reference:   req.Type + ":" + req.Reference,
+		Description: req.Description,
+		CreatedBy:   &adminID,
+	})
+	if err != nil {
+		 return dto.CreditWalletResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		 return dto.CreditWalletResponse{}, err
+	}
+
+	wallet, err := s.GetWallet(ctx, userID)
+	if err != nil {
+		 return dto.CreditWalletResponse{}, err
+	}
+
+	return dto.CreditWalletResponse{TransactionID: txID, Balance: wallet.Balance}, nil
+}
+
+// DebitTx takes amount from the wallet inside the caller's transaction, so an
+// order and its payment commit or roll back together.
+func (s WalletService) DebitTx(ctx context.Context, tx pgx.Tx, userID string, amount int, reference string) (string, error) {
+	return s.post(ctx, tx, userID, -amount, entities.LedgerTransaction{
+		Kind:      entities.LedgerOrderPayment,
+		Reference: reference,
+	})
+}
+
+// Debit is DebitTx in a transaction of its own.
+func (s WalletService) Debit(ctx context.Context, userID string, amount int, reference string) (string, error) {
+	return s.inTx(ctx, func(tx pgx.Tx) (string, error) {
+		return s.DebitTx(ctx, tx, userID, amount, reference)
+	})
+}
+
+// Refund returns money from a refunded order to the wallet.
+func (s WalletService) Refund(ctx context.Context, userID string, amount int, reference string) (string, error) {
+	return s.inTx(ctx, func(tx pgx.Tx) (string, error) {
+		return s.post(ctx, tx, userID, amount, entities.LedgerTransaction{
+			Kind:      entities.LedgerRefund,
+			Reference: reference,
+		})
+	})
+}
+
+func (s WalletService) inTx(ctx context.Context, fn func(pgx.Tx) (string, error)) (string, error) {
+	tx,err := repository.BeginTx(ctx)
+	if err != nil {
+		 return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := fn(tx)
+	if err != nil {
+		 return "", err
+	}
+
+	return id, tx.Commit(ctx)
+}
+
+// post moves amount into the wallet of userID (out of it when negative) against
+// the system account for t.Kind. A reference that was already posted returns
+// the original transaction without moving money again, as long as it was the
+// same movement; reusing it for another wallet, kind or amount is a conflict.
+func (s WalletService) post(ctx context.Context, tx pgx.Tx, userID string, amount int, t entities.LedgerTransaction) (string, error) {
+	if err := ctx.Err(); err != nil {
+		 return "", err
+	}
+
+	if amount == 0 {
+		 return "", utils.NewBadRequest("amount must not be zero")
+	}
+
+	walletID, err := s.repository.EnsureWallet(ctx, tx, userID)
+	if err != nil {
+		 return "", err
+	}
+
+	systemID, err := s.repository.GetSystemAccountID(ctx, tx, walletSources[t.Kind])
+	if err != nil {
+		 return "", err
+	}
+
+	posted, fresh, err := s.repository.InsertTransaction(ctx, tx, t)
+	if err != nil {
+		 return "", err
+	}
+	if !fresh {
+		 return posted.ID, s.checkReplay(ctx, tx, posted, t.Kind, walletID, amount)
+	}
+
+	err = s.repository.PostEntries(ctx, tx, posted.ID, []entities.LedgerLeg{
+		{AccountID: walletID, Amount: amount},
+		{AccountID: systemID, Amount: -amount},
+	})
+	if err != nil {
+		 return "", err
+	}
+
+	return posted.ID, nil
+}
+
+// checkReplay makes sure an already posted transaction is the movement being
+// retried: same kind, and the same amount on the same wallet.
+func (s WalletService) checkReplay(ctx context.Context, tx pgx.Tx, posted entities.LedgerTransaction, kind, walletID string, amount int) error {
+	conflict := utils.NewConflict("reference has already been used for a different transaction")
+	if posted.Kind != kind {
+		 return conflict
+	}
+
+	moved, err := s.repository.GetEntryAmount(ctx, tx, posted.ID, walletID)
+	if isNotFound(err) {
+		 return conflict
+	}
+	if err != nil {
+		 return err
+	}
+
+	if moved != amount {
+		 return conflict
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package tracking
+
+import (
+	"belimang/internal/utils"
+	"math"
+	"strings"
+)
+
+// EncodePolyline encodes path in the Google encoded polyline format with five
+// decimal places, which map clients can draw directly.
+func EncodePolyline(path []utils.Point) string {
+	var b strings.Builder
+	prevLat, prevLon := 0, 0
+	for _, p := range path {
+		lat := int(math.Round(p.Lat * 1e5))
+		lon := int(math.Round(p.Lon * 1e5))
+
+		encodeValue(&b, lat-prevLat)
+		encodeValue(&b, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+
+	return b.String()
+}
+
+func encodeValue(b *strings.Builder, v int) {
+	u := v << 1
+	if v < 0 {
+		 u = ^u
+	}
+
+	for u >= 0x20 {
+		b.WriteByte(byte((0x20 | (u & 0x1f)) + 63))
+		u >>= 5
+	}
+	b.WriteByte(byte(u + 63))
+}
@@ -0,0 +1,85 @@
+// Package tracking turns raw courier GPS pings into trip geometry.
+package tracking
+
+import (
+	"belimang/internal/utils"
+	"math"
+)
+
+const earthRadiusM = 6371000.0
+
+// Simplify reduces a path with the Douglas-Peucker algorithm, dropping points
+// that lie within toleranceM meters of the line between the points kept around
+// them. The first and last points are always kept.
+func Simplify(path []utils.Point, toleranceM float64) []utils.Point {
+	if len(path) < 3 {
+		 return append([]utils.Point(nil), path...)
+	}
+
+	keep := make([]bool, len(path))
+	keep[0], keep[len(path)-1] = true, true
+
+	// an explicit stack instead of recursion, long trips have thousands of pings
+	type span struct{ from, to int }
+	stack := []span{{0, len(path) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		farthest, maxDist := -1, toleranceM
+		for i := s.from + 1; i < s.to; i++ {
+			if d := crossTrackM(path[i], path[s.from], path[s.to]); d > maxDist {
+				farthest, maxDist = i, d
+			}
+		}
+
+		if farthest < 0 {
+			continue
+		}
+
+		keep[farthest] = true
+		stack = append(stack, span{s.from, farthest}, span{farthest, s.to})
+	}
+
+	simplified := make([]utils.Point, 0, len(path))
+	for i, p := range path {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+
+	return simplified
+}
+
+// crossTrackM is the distance in meters from p to the segment a-b. Trips cover
+// a few kilometers at most, so an equirectangular projection around a is
+// accurate enough and much cheaper than spherical geometry.
+func crossTrackM(p, a, b utils.Point) float64 {
+	toRad := math.Pi / 180
+	cosLat := math.Cos(a.Lat * toRad)
+
+	project := func(q utils.Point) (float64, float64) {
+		return (q.Lon - a.Lon) * toRad * cosLat * earthRadiusM, (q.Lat - a.Lat) * toRad * earthRadiusM
+	}
+
+	px, py := project(p)
+	bx, by := project(b)
+
+	lenSq := bx*bx + by*by
+	if lenSq == 0 {
+		 return math.Hypot(px, py)
+	}
+
+	t := max(0, min(1, (px*bx+py*by)/lenSq))
+	return math.Hypot(px-t*bx, py-t*by)
+}
+
+// LengthKm sums the great-circle distance along path.
+func LengthKm(path []utils.Point) float64 {
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		total += utils.Haversine(path[i-1], path[i])
+	}
+
+	return total
+}
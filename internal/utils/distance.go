@@ -22,39 +22,3 @@ func Haversine(a, b Point) float64 {
 	h := sinDLat*sinDLat + math.Cos(lat1)*math.Cos(lat2)*sinDLon*sinDLon
 	return 2 * R * math.Asin(math.Sqrt(h))
 }
-
-func NearestNeighborTSP(startIdx int, points []Point) float64 {
-	n := len(points)
-	if n < 2 {
-		return 0
-	}
-
-	visited := make([]bool, n)
-	current := startIdx
-	totalDist := 0.0
-
-	for step := 0; step < n-2; step++ {
-		visited[current] = true
-		minDist := math.MaxFloat64
-		next := -1
-
-		for j := 0; j < n-1; j++ {
-			if !visited[j] {
-				dist := Haversine(points[current], points[j])
-				if dist < minDist {
-					minDist = dist
-					next = j
-				}
-			}
-		}
-
-		if next == -1 {
-			break
-		}
-		totalDist += minDist
-		current = next
-	}
-
-	totalDist += Haversine(points[current], points[n-1])
-	return totalDist
-}
@@ -28,3 +28,11 @@ func NewBadRequest(msg string) AppError {
 func NewTooManyReq(msg string) AppError {
 	return AppError{StatusCode: 429, Message: msg}
 }
+
+func NewGone(msg string) AppError {
+	return AppError{StatusCode: 410, Message: msg}
+}
+
+func NewUnauthorized(msg string) AppError {
+	return AppError{StatusCode: 401, Message: msg}
+}
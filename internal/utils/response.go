@@ -27,3 +27,21 @@ func SendErrorResponse(w http.ResponseWriter, statusCode int, message string) {
 		"message": message,
 	})
 }
+
+func SendGeoJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		 log.Error().Err(err).Msg("failed to encode geojson response")
+	}
+}
+
+func SendBinary(w http.ResponseWriter, statusCode int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(body); err != nil {
+		 log.Error().Err(err).Msg("failed to write binary response")
+	}
+}
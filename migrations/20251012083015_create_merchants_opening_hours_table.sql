@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE merchants
+    ADD COLUMN IF NOT EXISTS rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS prep_minutes INT NOT NULL DEFAULT 10;
+
+CREATE TABLE IF NOT EXISTS merchants_opening_hours (
+    merchant_id UUID NOT NULL,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    opens_at TIME NOT NULL,
+    closes_at TIME NOT NULL,
+    PRIMARY KEY (merchant_id, day_of_week, opens_at)
+);
+
+CREATE INDEX idx_merchants_rating ON merchants (rating_avg DESC, rating_count DESC);
+CREATE INDEX idx_items_merchant_price ON items (merchant_id, price);
+CREATE INDEX idx_orders_items_estimate_id ON orders_items (estimate_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_items_estimate_id;
+DROP INDEX IF EXISTS idx_items_merchant_price;
+DROP INDEX IF EXISTS idx_merchants_rating;
+
+DROP TABLE IF EXISTS merchants_opening_hours;
+
+ALTER TABLE merchants
+    DROP COLUMN IF EXISTS prep_minutes,
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS total_price BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS price_breakdown JSONB;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS total_price BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS total_price;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS price_breakdown,
+    DROP COLUMN IF EXISTS total_price;
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE promotion_types_enum AS ENUM (
+    'Percentage',
+    'Fixed',
+    'FreeDelivery'
+);
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(32) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    type promotion_types_enum NOT NULL,
+    value INT NOT NULL DEFAULT 0,
+    max_discount INT NOT NULL DEFAULT 0,
+    min_basket INT NOT NULL DEFAULT 0,
+    global_limit INT NOT NULL DEFAULT 0,
+    per_user_limit INT NOT NULL DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    merchant_ids UUID[] NOT NULL DEFAULT '{}',
+    merchant_categories TEXT[] NOT NULL DEFAULT '{}',
+    product_categories TEXT[] NOT NULL DEFAULT '{}',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    order_id UUID NOT NULL UNIQUE,
+    estimate_id UUID NOT NULL,
+    discount INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_promotion_redemptions_promotion_user ON promotion_redemptions (promotion_id, user_id);
+
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS promotion_id UUID,
+    ADD COLUMN IF NOT EXISTS discount INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS discount,
+    DROP COLUMN IF EXISTS promotion_id;
+
+DROP INDEX IF EXISTS idx_promotion_redemptions_promotion_user;
+
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
+
+DROP TYPE IF EXISTS promotion_types_enum;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+UPDATE estimates SET expires_at = created_at + INTERVAL '30 minutes' WHERE expires_at IS NULL;
+
+ALTER TABLE estimates
+    ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX idx_estimates_expires_at ON estimates (expires_at);
+
+-- one order per estimate; fails if duplicate orders were already created from one estimate
+CREATE UNIQUE INDEX idx_orders_estimate_id ON orders (estimate_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_estimate_id;
+DROP INDEX IF EXISTS idx_estimates_expires_at;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS expires_at;
+-- +goose StatementEnd
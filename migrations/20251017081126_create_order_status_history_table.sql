@@ -0,0 +1,99 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE order_statuses_enum AS ENUM (
+    'Placed',
+    'Accepted',
+    'Preparing',
+    'PickedUp',
+    'Delivered',
+    'Cancelled',
+    'Rejected'
+);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS status order_statuses_enum NOT NULL DEFAULT 'Placed',
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status order_statuses_enum,
+    to_status order_statuses_enum NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    changed_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history (order_id, created_at);
+CREATE INDEX idx_orders_status ON orders (status);
+
+INSERT INTO order_status_history (order_id, to_status, created_at)
+SELECT id, status, created_at FROM orders;
+
+CREATE OR REPLACE VIEW order_history_view AS
+SELECT
+    od.id AS order_id,
+    es.user_id AS user_id,
+    mc.id AS merchant_id,
+    mc.name AS merchant_name,
+    mc.category AS merchant_category,
+    mc.imageurl AS merchant_imageurl,
+    ST_Y(mc.location::geometry) AS merchant_lat,
+    ST_X(mc.location::geometry) AS merchant_lon,
+    mc.created_at AS merchant_created_at,
+    it.id AS item_id,
+    it.name AS item_name,
+    it.category AS item_category,
+    it.imageurl AS item_imageurl,
+    it.price AS item_price,
+    oi.quantity AS quantity,
+    it.created_at AS item_created_at,
+    od.status AS order_status,
+    od.created_at AS order_created_at,
+    od.updated_at AS order_updated_at
+FROM orders od
+JOIN estimates es ON es.id = od.estimate_id
+JOIN orders_items oi ON oi.estimate_id = od.estimate_id
+JOIN merchants mc ON mc.id = oi.merchant_id
+JOIN items it ON it.id = oi.merchant_item_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS order_history_view;
+
+CREATE VIEW order_history_view AS
+SELECT
+    od.id AS order_id,
+    es.user_id AS user_id,
+    mc.id AS merchant_id,
+    mc.name AS merchant_name,
+    mc.category AS merchant_category,
+    mc.imageurl AS merchant_imageurl,
+    ST_Y(mc.location::geometry) AS merchant_lat,
+    ST_X(mc.location::geometry) AS merchant_lon,
+    mc.created_at AS merchant_created_at,
+    it.id AS item_id,
+    it.name AS item_name,
+    it.category AS item_category,
+    it.imageurl AS item_imageurl,
+    it.price AS item_price,
+    oi.quantity AS quantity,
+    it.created_at AS item_created_at
+FROM orders od
+JOIN estimates es ON es.id = od.estimate_id
+JOIN orders_items oi ON oi.estimate_id = od.estimate_id
+JOIN merchants mc ON mc.id = oi.merchant_id
+JOIN items it ON it.id = oi.merchant_item_id;
+
+DROP TABLE IF EXISTS order_status_history;
+DROP INDEX IF EXISTS idx_orders_status;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS order_statuses_enum;
+-- +goose StatementEnd
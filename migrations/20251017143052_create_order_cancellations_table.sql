@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE cancellation_reasons_enum AS ENUM (
+    'CustomerRequest',
+    'MerchantClosed',
+    'OutOfStock',
+    'CourierUnavailable',
+    'SuspectedFraud',
+    'Other'
+);
+
+CREATE TYPE cancellation_initiators_enum AS ENUM (
+    'User',
+    'Merchant',
+    'Admin'
+);
+
+CREATE TYPE refund_statuses_enum AS ENUM (
+    'Pending',
+    'Completed',
+    'Failed'
+);
+
+CREATE TABLE IF NOT EXISTS order_cancellations (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    reason_code cancellation_reasons_enum NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    initiated_by cancellation_initiators_enum NOT NULL,
+    actor_id UUID,
+    previous_status order_statuses_enum NOT NULL,
+    fee BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- payment_id is filled in by the payment subsystem when the refund is issued
+CREATE TABLE IF NOT EXISTS refunds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    payment_id UUID,
+    amount BIGINT NOT NULL,
+    status refund_statuses_enum NOT NULL DEFAULT 'Pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (amount >= 0)
+);
+
+CREATE INDEX idx_refunds_order_id ON refunds (order_id);
+CREATE INDEX idx_refunds_status ON refunds (status) WHERE status = 'Pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS order_cancellations;
+
+DROP TYPE IF EXISTS refund_statuses_enum;
+DROP TYPE IF EXISTS cancellation_initiators_enum;
+DROP TYPE IF EXISTS cancellation_reasons_enum;
+-- +goose StatementEnd
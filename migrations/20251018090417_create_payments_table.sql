@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE payment_statuses_enum AS ENUM (
+    'Pending',
+    'RequiresAction',
+    'Succeeded',
+    'Failed',
+    'Cancelled',
+    'Refunded'
+);
+
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    provider VARCHAR(32) NOT NULL,
+    provider_ref VARCHAR(255),
+    amount BIGINT NOT NULL,
+    status payment_statuses_enum NOT NULL DEFAULT 'Pending',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (amount >= 0)
+);
+
+CREATE UNIQUE INDEX idx_payments_provider_ref ON payments (provider, provider_ref) WHERE provider_ref IS NOT NULL;
+
+-- one row per delivery id, so a retried webhook is only applied once
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider VARCHAR(32) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, event_id)
+);
+
+ALTER TABLE refunds
+    ADD CONSTRAINT fk_refunds_payment_id FOREIGN KEY (payment_id) REFERENCES payments(id),
+    ADD COLUMN IF NOT EXISTS provider_ref VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS failure_reason TEXT NOT NULL DEFAULT '';
+
+ALTER TYPE cancellation_reasons_enum ADD VALUE IF NOT EXISTS 'PaymentFailed';
+ALTER TYPE cancellation_initiators_enum ADD VALUE IF NOT EXISTS 'System';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refunds
+    DROP CONSTRAINT IF EXISTS fk_refunds_payment_id,
+    DROP COLUMN IF EXISTS provider_ref,
+    DROP COLUMN IF EXISTS failure_reason;
+
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS payments;
+
+DROP TYPE IF EXISTS payment_statuses_enum;
+-- +goose StatementEnd
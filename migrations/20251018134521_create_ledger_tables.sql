@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE ledger_account_kinds_enum AS ENUM (
+    'CustomerWallet',
+    'CashClearing',
+    'Revenue',
+    'PromoExpense'
+);
+
+CREATE TYPE ledger_transaction_kinds_enum AS ENUM (
+    'TopUp',
+    'OrderPayment',
+    'Refund',
+    'PromoCredit'
+);
+
+-- system accounts have no owner; customer wallets can never go below zero
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    kind ledger_account_kinds_enum NOT NULL,
+    balance BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (kind <> 'CustomerWallet' OR balance >= 0),
+    CHECK ((kind = 'CustomerWallet') = (owner_id IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX idx_ledger_accounts_owner ON ledger_accounts (owner_id, kind) WHERE owner_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_ledger_accounts_system ON ledger_accounts (kind) WHERE owner_id IS NULL;
+
+-- reference is the idempotency key of the movement, e.g. "order:<id>"
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind ledger_transaction_kinds_enum NOT NULL,
+    reference VARCHAR(128) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- the entries of a transaction always sum to zero
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions(id) ON DELETE RESTRICT,
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL,
+    balance_after BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (amount <> 0)
+);
+
+CREATE INDEX idx_ledger_entries_account ON ledger_entries (account_id, created_at DESC);
+CREATE INDEX idx_ledger_entries_transaction ON ledger_entries (transaction_id);
+
+INSERT INTO ledger_accounts (kind) VALUES ('CashClearing'), ('Revenue'), ('PromoExpense');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS ledger_accounts;
+
+DROP TYPE IF EXISTS ledger_transaction_kinds_enum;
+DROP TYPE IF EXISTS ledger_account_kinds_enum;
+-- +goose StatementEnd
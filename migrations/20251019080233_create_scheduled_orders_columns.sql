@@ -0,0 +1,86 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE order_statuses_enum ADD VALUE IF NOT EXISTS 'Scheduled' BEFORE 'Placed';
+
+-- release_at is when a scheduled order is handed to the merchants: the
+-- requested delivery time minus preparation and travel time
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS release_at TIMESTAMPTZ;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS release_at TIMESTAMPTZ;
+
+CREATE INDEX idx_orders_release_at ON orders (status, release_at) WHERE release_at IS NOT NULL;
+
+CREATE OR REPLACE VIEW order_history_view AS
+SELECT
+    od.id AS order_id,
+    es.user_id AS user_id,
+    mc.id AS merchant_id,
+    mc.name AS merchant_name,
+    mc.category AS merchant_category,
+    mc.imageurl AS merchant_imageurl,
+    ST_Y(mc.location::geometry) AS merchant_lat,
+    ST_X(mc.location::geometry) AS merchant_lon,
+    mc.created_at AS merchant_created_at,
+    it.id AS item_id,
+    it.name AS item_name,
+    it.category AS item_category,
+    it.imageurl AS item_imageurl,
+    it.price AS item_price,
+    oi.quantity AS quantity,
+    it.created_at AS item_created_at,
+    od.status AS order_status,
+    od.created_at AS order_created_at,
+    od.updated_at AS order_updated_at,
+    od.scheduled_for AS order_scheduled_for
+FROM orders od
+JOIN estimates es ON es.id = od.estimate_id
+JOIN orders_items oi ON oi.estimate_id = od.estimate_id
+JOIN merchants mc ON mc.id = oi.merchant_id
+JOIN items it ON it.id = oi.merchant_item_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS order_history_view;
+
+CREATE VIEW order_history_view AS
+SELECT
+    od.id AS order_id,
+    es.user_id AS user_id,
+    mc.id AS merchant_id,
+    mc.name AS merchant_name,
+    mc.category AS merchant_category,
+    mc.imageurl AS merchant_imageurl,
+    ST_Y(mc.location::geometry) AS merchant_lat,
+    ST_X(mc.location::geometry) AS merchant_lon,
+    mc.created_at AS merchant_created_at,
+    it.id AS item_id,
+    it.name AS item_name,
+    it.category AS item_category,
+    it.imageurl AS item_imageurl,
+    it.price AS item_price,
+    oi.quantity AS quantity,
+    it.created_at AS item_created_at,
+    od.status AS order_status,
+    od.created_at AS order_created_at,
+    od.updated_at AS order_updated_at
+FROM orders od
+JOIN estimates es ON es.id = od.estimate_id
+JOIN orders_items oi ON oi.estimate_id = od.estimate_id
+JOIN merchants mc ON mc.id = oi.merchant_id
+JOIN items it ON it.id = oi.merchant_item_id;
+
+DROP INDEX IF EXISTS idx_orders_release_at;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS release_at,
+    DROP COLUMN IF EXISTS scheduled_for;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS release_at,
+    DROP COLUMN IF EXISTS scheduled_for;
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE merchants
+    ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_merchants_owner_id ON merchants (owner_id) WHERE owner_id IS NOT NULL;
+
+CREATE TYPE merchant_order_statuses_enum AS ENUM (
+    'Pending',
+    'Accepted',
+    'Ready',
+    'PickedUp',
+    'Rejected',
+    'Cancelled'
+);
+
+-- one sub-order per merchant taking part in an order, so each kitchen can
+-- accept, reject and finish its share independently
+CREATE TABLE IF NOT EXISTS merchant_orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    status merchant_order_statuses_enum NOT NULL DEFAULT 'Pending',
+    prep_minutes INT CHECK (prep_minutes > 0),
+    ready_by TIMESTAMPTZ,
+    ready_at TIMESTAMPTZ,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (order_id, merchant_id)
+);
+
+CREATE INDEX idx_merchant_orders_queue ON merchant_orders (merchant_id, status, created_at);
+
+INSERT INTO merchant_orders (order_id, merchant_id, status, created_at, updated_at)
+SELECT DISTINCT
+    o.id,
+    oi.merchant_id,
+    CASE o.status
+        WHEN 'Scheduled' THEN 'Pending'
+        WHEN 'Placed' THEN 'Pending'
+        WHEN 'Accepted' THEN 'Accepted'
+        WHEN 'Preparing' THEN 'Accepted'
+        WHEN 'PickedUp' THEN 'PickedUp'
+        WHEN 'Delivered' THEN 'PickedUp'
+        WHEN 'Rejected' THEN 'Rejected'
+        ELSE 'Cancelled'
+    END::merchant_order_statuses_enum,
+    o.created_at,
+    o.updated_at
+FROM orders o
+JOIN orders_items oi ON oi.estimate_id = o.estimate_id
+ON CONFLICT (order_id, merchant_id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS merchant_orders;
+DROP TYPE IF EXISTS merchant_order_statuses_enum;
+
+DROP INDEX IF EXISTS idx_merchants_owner_id;
+
+ALTER TABLE merchants
+    DROP COLUMN IF EXISTS owner_id;
+-- +goose StatementEnd
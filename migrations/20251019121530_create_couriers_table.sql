@@ -0,0 +1,75 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE courier_statuses_enum AS ENUM (
+    'Offline',
+    'Available',
+    'Busy'
+);
+
+CREATE TABLE IF NOT EXISTS couriers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    status courier_statuses_enum NOT NULL DEFAULT 'Offline',
+    location GEOGRAPHY(Point, 4326),
+    last_seen_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_couriers_location ON couriers USING GIST (location) WHERE status = 'Available';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS courier_id UUID REFERENCES couriers(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_orders_courier_id ON orders (courier_id) WHERE courier_id IS NOT NULL;
+
+-- the start of the quoted route and its length, so dispatch knows where the
+-- courier picks up first and how far the delivery runs after that
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS start_merchant_id UUID REFERENCES merchants(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS route_distance_km DOUBLE PRECISION;
+
+CREATE TYPE dispatch_offer_statuses_enum AS ENUM (
+    'Pending',
+    'Accepted',
+    'Declined',
+    'Expired',
+    'Cancelled'
+);
+
+CREATE TABLE IF NOT EXISTS dispatch_offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    courier_id UUID NOT NULL REFERENCES couriers(id) ON DELETE CASCADE,
+    status dispatch_offer_statuses_enum NOT NULL DEFAULT 'Pending',
+    score DOUBLE PRECISION NOT NULL,
+    approach_km DOUBLE PRECISION NOT NULL,
+    route_km DOUBLE PRECISION NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- an order is offered to one courier at a time, and a courier weighs one offer at a time
+CREATE UNIQUE INDEX idx_dispatch_offers_pending_order ON dispatch_offers (order_id) WHERE status = 'Pending';
+CREATE UNIQUE INDEX idx_dispatch_offers_pending_courier ON dispatch_offers (courier_id) WHERE status = 'Pending';
+CREATE INDEX idx_dispatch_offers_order_courier ON dispatch_offers (order_id, courier_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS dispatch_offers;
+DROP TYPE IF EXISTS dispatch_offer_statuses_enum;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS route_distance_km,
+    DROP COLUMN IF EXISTS start_merchant_id;
+
+DROP INDEX IF EXISTS idx_orders_courier_id;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS courier_id;
+
+DROP TABLE IF EXISTS couriers;
+DROP TYPE IF EXISTS courier_statuses_enum;
+-- +goose StatementEnd
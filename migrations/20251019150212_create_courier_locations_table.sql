@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- travel time the estimate quoted, kept so trips can be compared against it
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS estimated_delivery_minutes INT;
+
+-- raw GPS pings, partitioned by month; the ingestor creates each month's
+-- partition when its first ping arrives and the default partition catches
+-- anything it could not
+CREATE TABLE IF NOT EXISTS courier_locations (
+    courier_id UUID NOT NULL,
+    order_id UUID,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    lat DOUBLE PRECISION NOT NULL,
+    lon DOUBLE PRECISION NOT NULL,
+    accuracy_m REAL,
+    speed_kmh REAL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+) PARTITION BY RANGE (recorded_at);
+
+CREATE TABLE IF NOT EXISTS courier_locations_default PARTITION OF courier_locations DEFAULT;
+
+CREATE INDEX idx_courier_locations_courier ON courier_locations (courier_id, recorded_at);
+CREATE INDEX idx_courier_locations_order ON courier_locations (order_id, recorded_at) WHERE order_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS order_trips (
+    order_id UUID PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+    courier_id UUID REFERENCES couriers(id) ON DELETE SET NULL,
+    polyline TEXT NOT NULL DEFAULT '',
+    raw_points INT NOT NULL DEFAULT 0,
+    polyline_points INT NOT NULL DEFAULT 0,
+    distance_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    duration_seconds INT NOT NULL DEFAULT 0,
+    estimated_minutes INT,
+    started_at TIMESTAMPTZ,
+    ended_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_trips_ended_at ON order_trips (ended_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_trips;
+DROP TABLE IF EXISTS courier_locations;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS estimated_delivery_minutes;
+-- +goose StatementEnd
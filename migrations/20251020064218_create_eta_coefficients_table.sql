@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- estimated_delivery_minutes now holds the whole quote including preparation,
+-- travel_minutes keeps the part after pickup so trips still compare like for like
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS travel_minutes INT;
+
+-- each recalibration appends a model, the newest one is used for estimates
+CREATE TABLE IF NOT EXISTS eta_models (
+    id BIGSERIAL PRIMARY KEY,
+    stop_dwell_minutes DOUBLE PRECISION NOT NULL,
+    -- median moving speed per local hour of day, 0 where there was too little data
+    speed_kmh DOUBLE PRECISION[] NOT NULL CHECK (array_length(speed_kmh, 1) = 24),
+    segment_samples INT NOT NULL DEFAULT 0,
+    trip_samples INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS merchant_prep_stats (
+    merchant_id UUID PRIMARY KEY REFERENCES merchants(id) ON DELETE CASCADE,
+    median_minutes DOUBLE PRECISION NOT NULL,
+    p90_minutes DOUBLE PRECISION NOT NULL,
+    samples INT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS merchant_prep_stats;
+DROP TABLE IF EXISTS eta_models;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS travel_minutes;
+-- +goose StatementEnd
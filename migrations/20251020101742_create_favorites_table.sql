@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- unavailable items stay in the menu for past orders but cannot be ordered
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS is_available BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS favorite_merchants (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, merchant_id)
+);
+
+CREATE TABLE IF NOT EXISTS favorite_items (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, item_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS favorite_items;
+DROP TABLE IF EXISTS favorite_merchants;
+
+ALTER TABLE items
+    DROP COLUMN IF EXISTS is_available;
+-- +goose StatementEnd
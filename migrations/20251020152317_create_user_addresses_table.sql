@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL,
+    location GEOGRAPHY(Point, 4326) NOT NULL,
+    building VARCHAR(100) NOT NULL DEFAULT '',
+    floor VARCHAR(20) NOT NULL DEFAULT '',
+    notes VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_addresses_user_id ON user_addresses (user_id);
+
+-- where the order goes, copied from the saved address at quote time so later
+-- edits or deletes of the address do not move past orders
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS delivery_address_id UUID REFERENCES user_addresses(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS delivery_location GEOGRAPHY(Point, 4326),
+    ADD COLUMN IF NOT EXISTS delivery_building VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS delivery_floor VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS delivery_notes VARCHAR(500) NOT NULL DEFAULT '';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS delivery_address_id UUID REFERENCES user_addresses(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS delivery_location GEOGRAPHY(Point, 4326),
+    ADD COLUMN IF NOT EXISTS delivery_building VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS delivery_floor VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS delivery_notes VARCHAR(500) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS delivery_notes,
+    DROP COLUMN IF EXISTS delivery_floor,
+    DROP COLUMN IF EXISTS delivery_building,
+    DROP COLUMN IF EXISTS delivery_location,
+    DROP COLUMN IF EXISTS delivery_address_id;
+
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS delivery_notes,
+    DROP COLUMN IF EXISTS delivery_floor,
+    DROP COLUMN IF EXISTS delivery_building,
+    DROP COLUMN IF EXISTS delivery_location,
+    DROP COLUMN IF EXISTS delivery_address_id;
+
+DROP TABLE IF EXISTS user_addresses;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the quote as it was computed, so what a customer was shown can be audited
+-- and reproduced after prices, menus or the routing and ETA models change
+ALTER TABLE estimates
+    ADD COLUMN IF NOT EXISTS quote_input JSONB,
+    ADD COLUMN IF NOT EXISTS quote_route JSONB,
+    ADD COLUMN IF NOT EXISTS quote_eta JSONB,
+    ADD COLUMN IF NOT EXISTS algorithm_version TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE estimates
+    DROP COLUMN IF EXISTS algorithm_version,
+    DROP COLUMN IF EXISTS quote_eta,
+    DROP COLUMN IF EXISTS quote_route,
+    DROP COLUMN IF EXISTS quote_input;
+-- +goose StatementEnd
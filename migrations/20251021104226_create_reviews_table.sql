@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+-- reviews are published right away and admins hide the ones that break the rules,
+-- only published reviews count towards the summary ratings
+CREATE TYPE review_statuses_enum AS ENUM (
+    'Published',
+    'Hidden'
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment VARCHAR(1000) NOT NULL DEFAULT '',
+    photo_urls TEXT[] NOT NULL DEFAULT '{}',
+    status review_statuses_enum NOT NULL DEFAULT 'Published',
+    moderation_reason VARCHAR(500) NOT NULL DEFAULT '',
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (order_id, merchant_id)
+);
+
+CREATE INDEX idx_reviews_merchant_id ON reviews (merchant_id, created_at DESC);
+CREATE INDEX idx_reviews_status ON reviews (status, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS review_items (
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    PRIMARY KEY (review_id, item_id)
+);
+
+CREATE INDEX idx_review_items_item_id ON review_items (item_id);
+
+CREATE TABLE IF NOT EXISTS courier_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders(id) ON DELETE CASCADE,
+    courier_id UUID NOT NULL REFERENCES couriers(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment VARCHAR(1000) NOT NULL DEFAULT '',
+    status review_statuses_enum NOT NULL DEFAULT 'Published',
+    moderation_reason VARCHAR(500) NOT NULL DEFAULT '',
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_courier_reviews_courier_id ON courier_reviews (courier_id, created_at DESC);
+CREATE INDEX idx_courier_reviews_status ON courier_reviews (status, created_at DESC);
+
+-- merchants already carry rating_avg and rating_count
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE couriers
+    ADD COLUMN IF NOT EXISTS rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE couriers
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg;
+
+ALTER TABLE items
+    DROP COLUMN IF EXISTS rating_count,
+    DROP COLUMN IF EXISTS rating_avg;
+
+DROP TABLE IF EXISTS courier_reviews;
+DROP TABLE IF EXISTS review_items;
+DROP TABLE IF EXISTS reviews;
+DROP TYPE IF EXISTS review_statuses_enum;
+-- +goose StatementEnd
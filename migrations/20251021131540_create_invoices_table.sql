@@ -0,0 +1,92 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the price each item was quoted at, so invoices do not follow later menu changes
+ALTER TABLE orders_items
+    ADD COLUMN IF NOT EXISTS unit_price INT;
+
+CREATE OR REPLACE VIEW order_history_view AS
+SELECT
+    od.id AS order_id,
+    es.user_id AS user_id,
+    mc.id AS merchant_id,
+    mc.name AS merchant_name,
+    mc.category AS merchant_category,
+    mc.imageurl AS merchant_imageurl,
+    ST_Y(mc.location::geometry) AS merchant_lat,
+    ST_X(mc.location::geometry) AS merchant_lon,
+    mc.created_at AS merchant_created_at,
+    it.id AS item_id,
+    it.name AS item_name,
+    it.category AS item_category,
+    it.imageurl AS item_imageurl,
+    it.price AS item_price,
+    oi.quantity AS quantity,
+    it.created_at AS item_created_at,
+    od.status AS order_status,
+    od.created_at AS order_created_at,
+    od.updated_at AS order_updated_at,
+    od.scheduled_for AS order_scheduled_for,
+    COALESCE(oi.unit_price, it.price) AS unit_price
+FROM orders od
+JOIN estimates es ON es.id = od.estimate_id
+JOIN orders_items oi ON oi.estimate_id = od.estimate_id
+JOIN merchants mc ON mc.id = oi.merchant_id
+JOIN items it ON it.id = oi.merchant_item_id;
+
+-- every merchant numbers its invoices without gaps, the counter row is locked
+-- while a number is taken so concurrent invoices wait for each other
+CREATE TABLE IF NOT EXISTS merchant_invoice_sequences (
+    merchant_id UUID PRIMARY KEY REFERENCES merchants(id) ON DELETE CASCADE,
+    last_number BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    merchant_id UUID NOT NULL REFERENCES merchants(id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    number VARCHAR(64) NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (order_id, merchant_id),
+    UNIQUE (merchant_id, sequence)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS merchant_invoice_sequences;
+
+DROP VIEW IF EXISTS order_history_view;
+
+CREATE VIEW order_history_view AS
+SELECT
+    od.id AS order_id,
+    es.user_id AS user_id,
+    mc.id AS merchant_id,
+    mc.name AS merchant_name,
+    mc.category AS merchant_category,
+    mc.imageurl AS merchant_imageurl,
+    ST_Y(mc.location::geometry) AS merchant_lat,
+    ST_X(mc.location::geometry) AS merchant_lon,
+    mc.created_at AS merchant_created_at,
+    it.id AS item_id,
+    it.name AS item_name,
+    it.category AS item_category,
+    it.imageurl AS item_imageurl,
+    it.price AS item_price,
+    oi.quantity AS quantity,
+    it.created_at AS item_created_at,
+    od.status AS order_status,
+    od.created_at AS order_created_at,
+    od.updated_at AS order_updated_at,
+    od.scheduled_for AS order_scheduled_for
+FROM orders od
+JOIN estimates es ON es.id = od.estimate_id
+JOIN orders_items oi ON oi.estimate_id = od.estimate_id
+JOIN merchants mc ON mc.id = oi.merchant_id
+JOIN items it ON it.id = oi.merchant_item_id;
+
+ALTER TABLE orders_items
+    DROP COLUMN IF EXISTS unit_price;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- when the kitchen accepted its share, so preparation times are measured from
+-- a fixed start rather than from ready_by
+ALTER TABLE merchant_orders
+    ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMPTZ;
+
+-- ready_by of sub-orders not yet ready still holds accepted + prep_minutes,
+-- ready ones had it moved and cannot be recovered
+UPDATE merchant_orders
+SET accepted_at = ready_by - make_interval(mins => prep_minutes)
+WHERE status = 'Accepted'
+AND ready_by IS NOT NULL
+AND prep_minutes IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE merchant_orders
+    DROP COLUMN IF EXISTS accepted_at;
+-- +goose StatementEnd
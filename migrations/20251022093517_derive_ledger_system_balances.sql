@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- system accounts are on the other side of every wallet movement, so updating
+-- their balance row serialized all payments. Their entries no longer carry a
+-- running balance and the balance column is left as it was; the view below is
+-- the balance of every account from its entries.
+ALTER TABLE ledger_entries
+    ALTER COLUMN balance_after DROP NOT NULL;
+
+CREATE OR REPLACE VIEW ledger_account_balances AS
+SELECT a.id, a.owner_id, a.kind, COALESCE(SUM(e.amount), 0)::BIGINT AS balance
+FROM ledger_accounts a
+LEFT JOIN ledger_entries e ON e.account_id = a.id
+GROUP BY a.id, a.owner_id, a.kind;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS ledger_account_balances;
+
+UPDATE ledger_entries e
+SET balance_after = running.balance
+FROM (
+    SELECT id, SUM(amount) OVER (PARTITION BY account_id ORDER BY created_at, id) AS balance
+    FROM ledger_entries
+) running
+WHERE running.id = e.id
+AND e.balance_after IS NULL;
+
+UPDATE ledger_accounts a
+SET balance = b.balance
+FROM (
+    SELECT account_id, SUM(amount) AS balance FROM ledger_entries GROUP BY account_id
+) b
+WHERE b.account_id = a.id
+AND a.owner_id IS NULL;
+
+ALTER TABLE ledger_entries
+    ALTER COLUMN balance_after SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a default partition holding rows of a month blocks creating that month's
+-- partition for good, so pings move into monthly partitions and the default
+-- one goes away. The ingestor keeps partitions open ahead of the data.
+ALTER TABLE courier_locations DETACH PARTITION courier_locations_default;
+
+DO $$
+DECLARE
+    month DATE;
+BEGIN
+    FOR month IN
+        SELECT DISTINCT date_trunc('month', recorded_at AT TIME ZONE 'UTC')::date FROM courier_locations_default
+        UNION
+        SELECT (date_trunc('month', CURRENT_TIMESTAMP AT TIME ZONE 'UTC') + make_interval(months => n))::date
+        FROM generate_series(0, 2) n
+    LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF courier_locations FOR VALUES FROM (%L) TO (%L)',
+            'courier_locations_' || to_char(month, 'YYYY_MM'),
+            month::timestamp AT TIME ZONE 'UTC',
+            (month + INTERVAL '1 month')::timestamp AT TIME ZONE 'UTC'
+        );
+    END LOOP;
+END $$;
+
+INSERT INTO courier_locations SELECT * FROM courier_locations_default;
+
+DROP TABLE courier_locations_default;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS courier_locations_default PARTITION OF courier_locations DEFAULT;
+-- +goose StatementEnd
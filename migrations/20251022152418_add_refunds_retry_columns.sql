@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- failed refunds are sent again at next_attempt_at; it is NULL once the
+-- processor has given up on them
+ALTER TABLE refunds
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP;
+
+-- refunds that failed before retries existed get one more go
+UPDATE refunds
+SET attempts = 1, next_attempt_at = CURRENT_TIMESTAMP
+WHERE status = 'Failed';
+
+CREATE INDEX idx_refunds_next_attempt_at ON refunds (next_attempt_at) WHERE status = 'Failed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refunds_next_attempt_at;
+
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- review photos are kept as object names in the bucket and a fresh link is
+-- signed whenever a review is read; the stored links stopped working after a week
+ALTER TABLE reviews RENAME COLUMN photo_urls TO photo_keys;
+
+UPDATE reviews
+SET photo_keys = ARRAY(
+    SELECT regexp_replace(link, '^[^?]*?/(reviews/[^?]*)(\?.*)?$', '\1')
+    FROM unnest(photo_keys) AS link
+)
+WHERE cardinality(photo_keys) > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- the signed links cannot be restored, the column holds object names from here on
+ALTER TABLE reviews RENAME COLUMN photo_keys TO photo_urls;
+-- +goose StatementEnd